@@ -3,10 +3,18 @@ package main
 import (
 	"context"
 
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/routes"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/config"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/workers"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/database"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/fx"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/logger"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/ratelimit"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/sms"
 
 	"github.com/labstack/echo/v4"
 )
@@ -29,11 +37,63 @@ func main() {
 		}
 	}()
 
-	// Initialize Echo
+	db := mongoClient.Database(cfg.DatabaseName)
+
+	// Start background workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transactionService := services.NewTransactionService(repository.NewTxRunner(db), repository.NewTransactionRepository(db), repository.NewBalanceRepository(db), repository.NewIdempotencyRepository(db), repository.NewLedgerRepository(db), repository.NewAuditEventRepository(db), repository.NewAccountRepository(db), repository.NewUserRepository(db), repository.NewLimitPolicyRepository(db))
+	workers.NewHoldExpiryWorker(transactionService, cfg.HoldExpiryInterval, log).Start(ctx)
+	workers.NewOverdraftChargeWorker(transactionService, cfg.OverdraftCharge, log).Start(ctx)
+
+	// Apply admin currency overrides before serving, then keep them in sync across replicas
+	currencyService := services.NewCurrencyService(repository.NewTxRunner(db), repository.NewCurrencyRepository(db), repository.NewAuditEventRepository(db))
+	if err := currencyService.LoadOverrides(ctx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to load currency overrides")
+	}
+	workers.NewCurrencyRefreshWorker(currencyService, cfg.CurrencyRefresh, log).Start(ctx)
+
+	// Initialize mail delivery
+	mail, err := mailer.New(cfg.MailOutput)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize mailer")
+	}
+
+	// Initialize text message delivery
+	texter, err := sms.New(cfg.SMSOutput)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize SMS sender")
+	}
+
+	// Initialize exchange rates
+	rates, err := fx.New(cfg.FXRates)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize FX rates")
+	}
+
+	// Refuse rate limits that would never refill
+	if err := middleware.AuthRateLimit.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid RATE_LIMIT_AUTH_BURST or RATE_LIMIT_AUTH_PERIOD")
+	}
+	if err := middleware.MoneyRateLimit.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid RATE_LIMIT_MONEY_BURST or RATE_LIMIT_MONEY_PERIOD")
+	}
+
+	// Initialize rate limit buckets, in MongoDB when limits must hold across replicas
+	var limiter ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimitStore == "mongo" {
+		limiter = repository.NewRateLimitRepository(db)
+	}
+
+	// Initialize Echo, reading client IPs from the connection unless it comes from a trusted proxy
 	e := echo.New()
+	e.IPExtractor, err = middleware.ClientIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to parse trusted proxies")
+	}
 
 	// Setup routes
-	routes.Setup(e, mongoClient, log)
+	routes.Setup(e, db, mail, texter, rates, limiter, log)
 
 	// Start server
 	log.Info().Msgf("Server starting on port %s", cfg.Port)
@@ -0,0 +1,81 @@
+// Command verify-chain walks the per-account transaction hash chains and reports the first break in each.
+// It verifies one account with -account, or every account with transactions, and exits with status 1
+// if any chain is broken.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/config"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/database"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/logger"
+)
+
+func main() {
+	accountHex := flag.String("account", "", "ID of the account to verify; every account when empty")
+	flag.Parse()
+
+	// Initialize logger
+	log := logger.New()
+
+	// Load configuration
+	cfg := config.Load()
+
+	mongoClient, err := database.ConnectDB(cfg.MongoURI, cfg.DatabaseName)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to MongoDB")
+	}
+	defer func() {
+		if err := mongoClient.Disconnect(context.Background()); err != nil {
+			log.Error().Err(err).Msg("Failed to disconnect from MongoDB")
+		}
+	}()
+
+	ctx := context.Background()
+	chainService := services.NewChainService(repository.NewTransactionRepository(mongoClient.Database(cfg.DatabaseName)))
+
+	var results []dtos.ChainVerification
+	if *accountHex != "" {
+		accountID, err := primitive.ObjectIDFromHex(*accountHex)
+		if err != nil {
+			log.Fatal().Str("account", *accountHex).Msg("Invalid account ID")
+		}
+		result, err := chainService.VerifyAccount(ctx, accountID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to verify transaction chain")
+		}
+		results = append(results, *result)
+	} else {
+		results, err = chainService.VerifyAll(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to verify transaction chains")
+		}
+	}
+
+	// Print one result per line so the head hashes can be stored as an external anchor
+	encoder := json.NewEncoder(os.Stdout)
+	broken := 0
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			log.Fatal().Err(err).Msg("Failed to write verification result")
+		}
+		if !result.Valid {
+			broken++
+		}
+	}
+
+	if broken > 0 {
+		log.Error().Int("broken", broken).Int("accounts", len(results)).Msg("Transaction chain verification failed")
+		mongoClient.Disconnect(context.Background())
+		os.Exit(1)
+	}
+	log.Info().Int("accounts", len(results)).Msg("Transaction chains verified")
+}
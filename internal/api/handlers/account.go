@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type AccountHandler struct {
+	accountService services.AccountService
+	accessService  services.AccessService
+}
+
+func NewAccountHandler(accountService services.AccountService, accessService services.AccessService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		accessService:  accessService,
+	}
+}
+
+// OpenAccount handles the POST /accounts endpoint
+func (h *AccountHandler) OpenAccount(c echo.Context) error {
+	var input dtos.OpenAccountRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	account, err := h.accountService.OpenAccount(c.Request().Context(), middleware.GetUserID(c), input)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusCreated, account)
+}
+
+// ListAccounts handles the GET /accounts endpoint
+func (h *AccountHandler) ListAccounts(c echo.Context) error {
+	response, err := h.accountService.ListAccounts(c.Request().Context(), middleware.GetUserID(c))
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetAccount handles the GET /accounts/:id endpoint
+func (h *AccountHandler) GetAccount(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionAccountRead); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	account, err := h.accountService.GetAccount(c.Request().Context(), accountID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, account)
+}
+
+// AddHolder handles the POST /accounts/:id/holders endpoint
+func (h *AccountHandler) AddHolder(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	var input dtos.AddHolderRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	principal := middleware.GetUserID(c)
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), principal, accountID, models.PermissionAccountManage); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	account, err := h.accountService.AddHolder(c.Request().Context(), principal, accountID, input)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, account)
+}
+
+// RemoveHolder handles the DELETE /accounts/:id/holders/:user_id endpoint
+func (h *AccountHandler) RemoveHolder(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	userID, err := primitive.ObjectIDFromHex(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid user ID",
+		))
+	}
+
+	principal := middleware.GetUserID(c)
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), principal, accountID, models.PermissionAccountManage); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	account, err := h.accountService.RemoveHolder(c.Request().Context(), principal, accountID, userID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, account)
+}
+
+// SetTier handles the PUT /admin/accounts/:account_id/tier endpoint
+func (h *AccountHandler) SetTier(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("account_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	var input dtos.SetAccountTierRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	principal := middleware.GetUserID(c)
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), principal, accountID, models.PermissionAccountManage); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	account, err := h.accountService.SetTier(c.Request().Context(), principal, accountID, models.AccountTier(input.Tier))
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, account)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey handles the POST /api-keys endpoint. The secret is only returned in this response.
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	var input dtos.CreateAPIKeyRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	response, err := h.apiKeyService.Create(c.Request().Context(), middleware.GetUserID(c), input)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListAPIKeys handles the GET /api-keys endpoint
+func (h *APIKeyHandler) ListAPIKeys(c echo.Context) error {
+	response, err := h.apiKeyService.List(c.Request().Context(), middleware.GetUserID(c))
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RotateAPIKey handles the POST /api-keys/:id/rotate endpoint
+func (h *APIKeyHandler) RotateAPIKey(c echo.Context) error {
+	keyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid API key ID",
+		))
+	}
+
+	response, err := h.apiKeyService.Rotate(c.Request().Context(), middleware.GetUserID(c), keyID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// RevokeAPIKey handles the DELETE /api-keys/:id endpoint
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	keyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid API key ID",
+		))
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request().Context(), middleware.GetUserID(c), keyID); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
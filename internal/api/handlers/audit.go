@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEvents handles the GET /admin/audit endpoint
+func (h *AuditHandler) ListEvents(c echo.Context) error {
+	var query dtos.AuditEventListQuery
+	if err := c.Bind(&query); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(query); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	response, err := h.auditService.ListEvents(c.Request().Context(), query)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
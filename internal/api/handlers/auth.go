@@ -1,14 +1,18 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 )
 
 type AuthHandler struct {
@@ -33,7 +37,6 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	}
 
 	response, err := h.authService.Register(c.Request().Context(), input)
-	fmt.Printf("DEBUG: Register response=%%#v, err=%%v\n", response, err)
 	if err != nil {
 		if err == services.ErrEmailExists {
 			return c.JSON(http.StatusConflict, map[string]string{"error": "Email already exists"})
@@ -44,7 +47,7 @@ func (h *AuthHandler) Register(c echo.Context) error {
 	return c.JSON(http.StatusCreated, response)
 }
 
-// Login handles user login
+// Login handles user login. Accounts with MFA enabled receive an MFA token for VerifyMFA instead of a session.
 func (h *AuthHandler) Login(c echo.Context) error {
 	var input dtos.LoginRequest
 	if err := c.Bind(&input); err != nil {
@@ -55,14 +58,263 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
 	}
 
+	input.IP = c.RealIP()
 	response, err := h.authService.Login(c.Request().Context(), input)
-	fmt.Printf("DEBUG: Login response=%%#v, err=%%v\n", response, err)
 	if err != nil {
 		if err == services.ErrInvalidCredentials {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid credentials"})
 		}
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many failed login attempts, try again later"})
+		}
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to login"})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Refresh exchanges a refresh token for a new token pair
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var input dtos.RefreshRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	response, err := h.authService.Refresh(c.Request().Context(), input)
+	if err != nil {
+		if err == services.ErrInvalidRefreshToken {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired refresh token"})
+		}
+		if err == services.ErrRefreshTokenReused {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Refresh token reuse detected, session revoked"})
+		}
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to refresh token"})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Logout revokes the session of the given refresh token
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var input dtos.LogoutRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	if err := h.authService.Logout(c.Request().Context(), input); err != nil {
+		if err == services.ErrInvalidRefreshToken {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired refresh token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to logout"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ForgotPassword mails a password reset link. It answers 202 whether or not the email is registered.
+func (h *AuthHandler) ForgotPassword(c echo.Context) error {
+	var input dtos.ForgotPasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	if err := h.authService.ForgotPassword(c.Request().Context(), input); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to send password reset email"})
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ResetPassword sets a new password with the token from a reset link
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	var input dtos.ResetPasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	if err := h.authService.ResetPassword(c.Request().Context(), input); err != nil {
+		if err == services.ErrInvalidActionToken {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid or expired token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to reset password"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// VerifyEmail confirms an email address with the token from a verification link
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	var input dtos.VerifyEmailRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	if err := h.authService.VerifyEmail(c.Request().Context(), input); err != nil {
+		if err == services.ErrInvalidActionToken {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid or expired token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to verify email"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResendVerification mails a new verification link to the authenticated account
+func (h *AuthHandler) ResendVerification(c echo.Context) error {
+	if err := h.authService.ResendVerification(c.Request().Context(), middleware.GetUserID(c)); err != nil {
+		if err == services.ErrEmailVerified {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Email is already verified"})
+		}
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to send verification email"})
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// VerifyPhone confirms a phone number with the token from a verification link
+func (h *AuthHandler) VerifyPhone(c echo.Context) error {
+	var input dtos.VerifyPhoneRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	if err := h.authService.VerifyPhone(c.Request().Context(), input); err != nil {
+		if err == services.ErrInvalidActionToken {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid or expired token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to verify phone number"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResendPhoneVerification texts a new verification link to the authenticated account's phone number
+func (h *AuthHandler) ResendPhoneVerification(c echo.Context) error {
+	if err := h.authService.ResendPhoneVerification(c.Request().Context(), middleware.GetUserID(c)); err != nil {
+		if err == services.ErrPhoneVerified {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "Phone number is already verified"})
+		}
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to send phone verification"})
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// VerifyMFA completes a login with a TOTP or recovery code
+func (h *AuthHandler) VerifyMFA(c echo.Context) error {
+	var input dtos.MFALoginRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	input.IP = c.RealIP()
+	response, err := h.authService.VerifyMFA(c.Request().Context(), input)
+	if err != nil {
+		if err == services.ErrInvalidMFAToken {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired MFA token"})
+		}
+		if err == services.ErrInvalidMFACode {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid MFA code"})
+		}
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many failed login attempts, try again later"})
+		}
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to login"})
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
+
+// EnrollMFA starts TOTP enrollment for the authenticated account
+func (h *AuthHandler) EnrollMFA(c echo.Context) error {
+	response, err := h.authService.EnrollMFA(c.Request().Context(), middleware.GetUserID(c))
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ActivateMFA confirms TOTP enrollment with a first code and returns the recovery codes
+func (h *AuthHandler) ActivateMFA(c echo.Context) error {
+	var input dtos.MFAActivateRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	response, err := h.authService.ActivateMFA(c.Request().Context(), middleware.GetUserID(c), input)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// mfaError maps the errors of MFA management to responses
+func mfaError(c echo.Context, err error) error {
+	switch err {
+	case services.ErrMFAAlreadyEnabled:
+		return c.JSON(http.StatusConflict, map[string]string{"error": "MFA is already enabled"})
+	case services.ErrMFANotEnrolled:
+		return c.JSON(http.StatusConflict, map[string]string{"error": "MFA enrollment has not been started"})
+	case services.ErrInvalidMFACode:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid MFA code"})
+	case services.ErrMFAManagement:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "MFA can only be managed with an access token"})
+	}
+	if customErr, ok := utils.IsCustomError(err); ok {
+		return c.JSON(customErr.Code, customErr)
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update MFA"})
+}
@@ -2,8 +2,14 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,11 +17,13 @@ import (
 
 type BalanceHandler struct {
 	balanceService *services.BalanceService
+	accessService  services.AccessService
 }
 
-func NewBalanceHandler(balanceService *services.BalanceService) *BalanceHandler {
+func NewBalanceHandler(balanceService *services.BalanceService, accessService services.AccessService) *BalanceHandler {
 	return &BalanceHandler{
 		balanceService: balanceService,
+		accessService:  accessService,
 	}
 }
 
@@ -30,6 +38,13 @@ func (h *BalanceHandler) GetBalances(c echo.Context) error {
 		))
 	}
 
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionAccountRead); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
 	response, err := h.balanceService.GetBalances(c.Request().Context(), accountID)
 	if err != nil {
 		if customErr, ok := utils.IsCustomError(err); ok {
@@ -40,3 +55,76 @@ func (h *BalanceHandler) GetBalances(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, response)
 }
+
+// RebuildBalances handles the POST /admin/accounts/:account_id/balances/rebuild endpoint
+func (h *BalanceHandler) RebuildBalances(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("account_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionAccountManage); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	response, err := h.balanceService.RebuildBalances(c.Request().Context(), accountID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// SetOverdraft handles the PUT /admin/accounts/:account_id/balances/:currency/overdraft endpoint
+func (h *BalanceHandler) SetOverdraft(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("account_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	currency := strings.ToUpper(c.Param("currency"))
+	if !money.IsEnabled(currency) {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"currency must be the code of an enabled ISO 4217 currency",
+		))
+	}
+
+	var input dtos.SetOverdraftRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionAccountManage); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	response, err := h.balanceService.SetOverdraft(c.Request().Context(), accountID, currency, input)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
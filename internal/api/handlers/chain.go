@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type ChainHandler struct {
+	chainService services.ChainService
+}
+
+func NewChainHandler(chainService services.ChainService) *ChainHandler {
+	return &ChainHandler{
+		chainService: chainService,
+	}
+}
+
+// VerifyChain handles the GET /audit/accounts/:id/chain endpoint
+func (h *ChainHandler) VerifyChain(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	response, err := h.chainService.VerifyAccount(c.Request().Context(), accountID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
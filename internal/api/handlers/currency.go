@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type CurrencyHandler struct {
+	currencyService services.CurrencyService
+}
+
+func NewCurrencyHandler(currencyService services.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{currencyService: currencyService}
+}
+
+// ListCurrencies handles the GET /currencies endpoint
+func (h *CurrencyHandler) ListCurrencies(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.currencyService.ListCurrencies(c.Request().Context()))
+}
+
+// UpdateCurrency handles the PATCH /admin/currencies/:code endpoint
+func (h *CurrencyHandler) UpdateCurrency(c echo.Context) error {
+	var input dtos.UpdateCurrencyRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	currency, err := h.currencyService.UpdateCurrency(c.Request().Context(), middleware.GetUserID(c), c.Param("code"), input)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, currency)
+}
+
+// ResetCurrency handles the DELETE /admin/currencies/:code endpoint
+func (h *CurrencyHandler) ResetCurrency(c echo.Context) error {
+	currency, err := h.currencyService.ResetCurrency(c.Request().Context(), middleware.GetUserID(c), c.Param("code"))
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, currency)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type FXHandler struct {
+	fxService     services.FXService
+	accessService services.AccessService
+}
+
+func NewFXHandler(fxService services.FXService, accessService services.AccessService) *FXHandler {
+	return &FXHandler{
+		fxService:     fxService,
+		accessService: accessService,
+	}
+}
+
+// CreateQuote handles the POST /fx/quotes endpoint
+func (h *FXHandler) CreateQuote(c echo.Context) error {
+	var input dtos.FXQuoteRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	accountID, err := primitive.ObjectIDFromHex(input.AccountID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid account ID"})
+	}
+
+	// Only callers who could execute the quote may request one
+	principal := middleware.GetUserID(c)
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), principal, accountID, models.PermissionFundsWithdraw); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	amount, err := input.Amount.Amount(input.SellCurrency)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(http.StatusBadRequest, err.Error()))
+	}
+
+	response, err := h.fxService.CreateQuote(c.Request().Context(), principal, accountID, input.SellCurrency, input.BuyCurrency, amount)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// Convert handles the POST /fx/convert endpoint
+func (h *FXHandler) Convert(c echo.Context) error {
+	var input dtos.FXConvertRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	quoteID, err := primitive.ObjectIDFromHex(input.QuoteID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid quote ID"})
+	}
+
+	ctx := c.Request().Context()
+	principal := middleware.GetUserID(c)
+	quote, err := h.fxService.GetQuote(ctx, principal, quoteID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	// Access is checked again in case it was withdrawn after the quote was made
+	if err := h.accessService.AuthorizeAccount(ctx, principal, quote.AccountID, models.PermissionFundsWithdraw); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	response, err := h.fxService.Convert(ctx, principal, quoteID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type LimitHandler struct {
+	limitService services.LimitService
+}
+
+func NewLimitHandler(limitService services.LimitService) *LimitHandler {
+	return &LimitHandler{limitService: limitService}
+}
+
+// ListPolicies handles the GET /admin/limits endpoint
+func (h *LimitHandler) ListPolicies(c echo.Context) error {
+	response, err := h.limitService.ListPolicies(c.Request().Context())
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// SetPolicy handles the PUT /admin/limits/:tier/:currency/:direction endpoint
+func (h *LimitHandler) SetPolicy(c echo.Context) error {
+	var input dtos.SetLimitPolicyRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	response, err := h.limitService.SetPolicy(c.Request().Context(), middleware.GetUserID(c), c.Param("tier"), c.Param("currency"), c.Param("direction"), input)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeletePolicy handles the DELETE /admin/limits/:tier/:currency/:direction endpoint
+func (h *LimitHandler) DeletePolicy(c echo.Context) error {
+	err := h.limitService.DeletePolicy(c.Request().Context(), middleware.GetUserID(c), c.Param("tier"), c.Param("currency"), c.Param("direction"))
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
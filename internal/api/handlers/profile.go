@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// GetProfile handles the GET /me endpoint
+func (h *AuthHandler) GetProfile(c echo.Context) error {
+	user, err := h.authService.GetProfile(c.Request().Context(), middleware.GetUserID(c))
+	if err != nil {
+		return profileError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// UpdateProfile handles the PATCH /me endpoint
+func (h *AuthHandler) UpdateProfile(c echo.Context) error {
+	var input dtos.UpdateProfileRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	user, err := h.authService.UpdateProfile(c.Request().Context(), middleware.GetUserID(c), input)
+	if err != nil {
+		return profileError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// ChangePassword handles the POST /me/password endpoint
+func (h *AuthHandler) ChangePassword(c echo.Context) error {
+	var input dtos.ChangePasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	if err := h.authService.ChangePassword(c.Request().Context(), middleware.GetUserID(c), input); err != nil {
+		return profileError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// profileError maps the errors of profile management to responses
+func profileError(c echo.Context, err error) error {
+	switch err {
+	case services.ErrWrongPassword:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Current password is incorrect"})
+	case services.ErrProfileManagement:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Profile can only be changed with an access token"})
+	case services.ErrUserModified:
+		return c.JSON(http.StatusConflict, map[string]string{"error": "User was modified since it was read, reload it and retry"})
+	case services.ErrEmailExists:
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Email already exists"})
+	case services.ErrPhoneExists:
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Phone number already exists"})
+	}
+	var throttled *services.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many failed password attempts, try again later"})
+	}
+	if customErr, ok := utils.IsCustomError(err); ok {
+		return c.JSON(customErr.Code, customErr)
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update profile"})
+}
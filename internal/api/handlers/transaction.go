@@ -2,17 +2,27 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/validation"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// IdempotencyKeyHeader is the request header clients use to make deposits and withdrawals safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLength = 255
+
 type TransactionHandler struct {
-	transactionService *services.TransactionService
+	transactionService services.TransactionService
+	accessService      services.AccessService
 }
 
 // GetBalances handles the GET /transactions/balances/:account_id endpoint
@@ -26,6 +36,13 @@ func (h *TransactionHandler) GetBalances(c echo.Context) error {
 		))
 	}
 
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionAccountRead); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
 	response, err := h.transactionService.GetBalances(c.Request().Context(), accountID)
 	if err != nil {
 		if customErr, ok := utils.IsCustomError(err); ok {
@@ -37,9 +54,10 @@ func (h *TransactionHandler) GetBalances(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
-func NewTransactionHandler(transactionService *services.TransactionService) *TransactionHandler {
+func NewTransactionHandler(transactionService services.TransactionService, accessService services.AccessService) *TransactionHandler {
 	return &TransactionHandler{
 		transactionService: transactionService,
+		accessService:      accessService,
 	}
 }
 
@@ -58,7 +76,24 @@ func (h *TransactionHandler) Deposit(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid account ID"})
 	}
 
-	transactionID, err := h.transactionService.Deposit(c.Request().Context(), accountID, input.Amount, input.Currency)
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionFundsDeposit); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	idempotencyKey := c.Request().Header.Get(IdempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Idempotency-Key must not exceed 255 characters"})
+	}
+
+	amount, err := input.Amount.Amount(input.Currency)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(http.StatusBadRequest, err.Error()))
+	}
+
+	transactionID, err := h.transactionService.Deposit(c.Request().Context(), accountID, amount, input.Currency, idempotencyKey)
 	if err != nil {
 		if customErr, ok := utils.IsCustomError(err); ok {
 			return c.JSON(customErr.Code, customErr)
@@ -86,7 +121,24 @@ func (h *TransactionHandler) Withdraw(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid account ID"})
 	}
 
-	transactionID, err := h.transactionService.Withdraw(c.Request().Context(), accountID, input.Amount, input.Currency)
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionFundsWithdraw); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	idempotencyKey := c.Request().Header.Get(IdempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Idempotency-Key must not exceed 255 characters"})
+	}
+
+	amount, err := input.Amount.Amount(input.Currency)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(http.StatusBadRequest, err.Error()))
+	}
+
+	transactionID, err := h.transactionService.Withdraw(c.Request().Context(), accountID, amount, input.Currency, idempotencyKey)
 	if err != nil {
 		if customErr, ok := utils.IsCustomError(err); ok {
 			return c.JSON(customErr.Code, customErr)
@@ -98,3 +150,290 @@ func (h *TransactionHandler) Withdraw(c echo.Context) error {
 		TransactionID: transactionID,
 	})
 }
+
+// Transfer handles the POST /transactions/transfer endpoint
+func (h *TransactionHandler) Transfer(c echo.Context) error {
+	var input dtos.TransferRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	sourceID, err := primitive.ObjectIDFromHex(input.SourceAccountID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid source account ID"})
+	}
+
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), sourceID, models.PermissionFundsWithdraw); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	destinationID, err := primitive.ObjectIDFromHex(input.DestinationAccountID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid destination account ID"})
+	}
+
+	amount, err := input.Amount.Amount(input.Currency)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(http.StatusBadRequest, err.Error()))
+	}
+
+	response, err := h.transactionService.Transfer(c.Request().Context(), sourceID, destinationID, amount, input.Currency, input.Description)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListAccountTransactions handles the GET /accounts/:id/transactions endpoint
+func (h *TransactionHandler) ListAccountTransactions(c echo.Context) error {
+	accountID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid account ID",
+		))
+	}
+
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionAccountRead); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	var query dtos.TransactionHistoryQuery
+	if err := c.Bind(&query); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(query); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	response, err := h.transactionService.ListTransactions(c.Request().Context(), accountID, query)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetTransaction handles the GET /transactions/:id endpoint
+func (h *TransactionHandler) GetTransaction(c echo.Context) error {
+	transactionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid transaction ID",
+		))
+	}
+
+	if err := h.accessService.AuthorizeTransaction(c.Request().Context(), middleware.GetUserID(c), transactionID, models.PermissionAccountRead); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	response, err := h.transactionService.GetTransaction(c.Request().Context(), transactionID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Authorize handles the POST /transactions/authorize endpoint
+func (h *TransactionHandler) Authorize(c echo.Context) error {
+	var input dtos.AuthorizeRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	accountID, err := primitive.ObjectIDFromHex(input.AccountID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid account ID"})
+	}
+
+	if err := h.accessService.AuthorizeAccount(c.Request().Context(), middleware.GetUserID(c), accountID, models.PermissionFundsWithdraw); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	amount, err := input.Amount.Amount(input.Currency)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(http.StatusBadRequest, err.Error()))
+	}
+
+	ttl := time.Duration(input.ExpiresInSeconds) * time.Second
+	response, err := h.transactionService.Authorize(c.Request().Context(), accountID, amount, input.Currency, input.Description, ttl)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// Capture handles the POST /transactions/:id/capture endpoint
+func (h *TransactionHandler) Capture(c echo.Context) error {
+	holdID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid transaction ID",
+		))
+	}
+
+	if err := h.accessService.AuthorizeTransaction(c.Request().Context(), middleware.GetUserID(c), holdID, models.PermissionFundsWithdraw); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	var input dtos.CaptureRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	ctx := c.Request().Context()
+
+	// Partial captures are parsed with the currency of the hold
+	var amount *money.Amount
+	if input.Amount != "" {
+		hold, err := h.transactionService.GetTransaction(ctx, holdID)
+		if err != nil {
+			if customErr, ok := utils.IsCustomError(err); ok {
+				return c.JSON(customErr.Code, customErr)
+			}
+			return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+		}
+
+		parsed, err := input.Amount.Amount(hold.Currency)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, utils.NewError(http.StatusBadRequest, err.Error()))
+		}
+		amount = &parsed
+	}
+
+	response, err := h.transactionService.Capture(ctx, holdID, amount)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Void handles the POST /transactions/:id/void endpoint
+func (h *TransactionHandler) Void(c echo.Context) error {
+	holdID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid transaction ID",
+		))
+	}
+
+	if err := h.accessService.AuthorizeTransaction(c.Request().Context(), middleware.GetUserID(c), holdID, models.PermissionFundsWithdraw); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	response, err := h.transactionService.Void(c.Request().Context(), holdID)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Reverse handles the POST /transactions/:id/reverse endpoint
+func (h *TransactionHandler) Reverse(c echo.Context) error {
+	transactionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, utils.NewError(
+			http.StatusBadRequest,
+			"invalid transaction ID",
+		))
+	}
+
+	if err := h.accessService.AuthorizeTransaction(c.Request().Context(), middleware.GetUserID(c), transactionID, models.PermissionTransactionReverse); err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	var input dtos.ReverseRequest
+	if err := c.Bind(&input); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if errors := validation.ValidateStruct(input); len(errors) > 0 {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"errors": errors})
+	}
+
+	ctx := c.Request().Context()
+
+	// Partial refunds are parsed with the currency of the original transaction
+	var amount *money.Amount
+	if input.Amount != "" {
+		original, err := h.transactionService.GetTransaction(ctx, transactionID)
+		if err != nil {
+			if customErr, ok := utils.IsCustomError(err); ok {
+				return c.JSON(customErr.Code, customErr)
+			}
+			return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+		}
+
+		parsed, err := input.Amount.Amount(original.Currency)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, utils.NewError(http.StatusBadRequest, err.Error()))
+		}
+		amount = &parsed
+	}
+
+	response, err := h.transactionService.Reverse(ctx, transactionID, amount, input.Reason)
+	if err != nil {
+		if customErr, ok := utils.IsCustomError(err); ok {
+			return c.JSON(customErr.Code, customErr)
+		}
+		return c.JSON(http.StatusInternalServerError, utils.WrapError(err, http.StatusInternalServerError))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
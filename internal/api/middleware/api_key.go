@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// APIKeyHeader carries the API key of machine clients
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth returns a middleware function that authenticates requests carrying an API key.
+// Requests without the header are left to Auth.
+func APIKeyAuth(apiKeyService services.APIKeyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(APIKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+
+			principal, err := apiKeyService.Authenticate(c.Request().Context(), key)
+			if err != nil {
+				if err == utils.ErrInvalidAPIKey {
+					return c.JSON(http.StatusUnauthorized, map[string]string{
+						"error": "Invalid, expired or revoked API key",
+					})
+				}
+				if customErr, ok := utils.IsCustomError(err); ok {
+					return c.JSON(customErr.Code, map[string]string{
+						"error": customErr.Message,
+					})
+				}
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to authenticate API key",
+				})
+			}
+
+			setPrincipal(c, principal)
+
+			return next(c)
+		}
+	}
+}
+
+// RequireScope returns a middleware function that rejects API keys without the given scope.
+// Principals authenticated with a token are not restricted. It must run after Auth.
+func RequireScope(permission models.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := GetUserID(c)
+			if principal == nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Authentication is required",
+				})
+			}
+
+			if !principal.Allows(permission) {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "API key is missing the " + string(permission) + " scope",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
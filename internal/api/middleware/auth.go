@@ -5,17 +5,27 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/jwt"
 )
 
-// UserIDKey is the key used to store the user ID in the context
-const UserIDKey = "user_id"
+// PrincipalKey is the key used to store the authenticated principal in the context
+const PrincipalKey = "principal"
 
-// Auth returns a middleware function that authenticates requests using JWT
-func Auth() echo.MiddlewareFunc {
+// Auth returns a middleware function that authenticates requests using JWT and loads
+// the user named by the token's subject. Tokens from a session that was logged out or
+// revoked after refresh token reuse are rejected. Requests already authenticated by APIKeyAuth
+// are passed through.
+func Auth(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if GetUserID(c) != nil {
+				return next(c)
+			}
+
 			// Get token from Authorization header
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
@@ -41,19 +51,73 @@ func Auth() echo.MiddlewareFunc {
 				})
 			}
 
-			// Add user ID to context
-			c.Set(UserIDKey, claims.UserID)
+			userID, err := primitive.ObjectIDFromHex(claims.Subject)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid or expired token",
+				})
+			}
+
+			sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid or expired token",
+				})
+			}
+
+			// Reject tokens from revoked sessions
+			revoked, err := refreshTokenRepo.IsFamilyRevoked(c.Request().Context(), sessionID)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to check token revocation",
+				})
+			}
+			if revoked {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Token has been revoked",
+				})
+			}
+
+			// Load the user the token was issued to
+			user, err := userRepo.FindByID(c.Request().Context(), userID)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to load user",
+				})
+			}
+			if user == nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid or expired token",
+				})
+			}
+
+			// A token minted before a role change no longer reflects the user's privileges
+			if claims.Role != string(models.RoleOf(user)) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Token role is outdated, refresh the token",
+				})
+			}
+
+			// Add principal to context
+			setPrincipal(c, models.NewPrincipal(user, claims.ID, sessionID))
 
 			return next(c)
 		}
 	}
 }
 
-// GetUserID retrieves the authenticated user's ID from the context
-func GetUserID(c echo.Context) uint {
-	userID, ok := c.Get(UserIDKey).(uint)
+// setPrincipal stores the principal in the echo context and in the request context, where services
+// read it from
+func setPrincipal(c echo.Context, principal *models.Principal) {
+	c.Set(PrincipalKey, principal)
+	c.SetRequest(c.Request().WithContext(models.ContextWithPrincipal(c.Request().Context(), principal)))
+}
+
+// GetUserID retrieves the authenticated principal from the context
+func GetUserID(c echo.Context) *models.Principal {
+	principal, ok := c.Get(PrincipalKey).(*models.Principal)
 	if !ok {
-		return 0 // Return 0 if user ID is not found or invalid
+		return nil // Return nil if the principal is not found or invalid
 	}
-	return userID
+	return principal
 }
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ClientIPExtractor returns how echo reads the client IP address that login throttling, rate limits and
+// audit events rely on. With no trusted proxies it is the peer address of the connection and forwarding
+// headers are ignored, since any client can set them. Otherwise trustedProxies is a comma-separated list
+// of CIDR ranges, and X-Forwarded-For is followed back through proxies in those ranges only.
+func ClientIPExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	if strings.TrimSpace(trustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range strings.Split(trustedProxies, ",") {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(proxy))
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %w", proxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
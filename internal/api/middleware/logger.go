@@ -24,6 +24,7 @@ func RequestLogger(log zerolog.Logger) echo.MiddlewareFunc {
 				Str("method", req.Method).
 				Str("uri", req.RequestURI).
 				Int("status", res.Status).
+				Str("request_id", res.Header().Get(echo.HeaderXRequestID)).
 				Dur("duration", time.Since(start)).
 				Msg("Request handled")
 
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/ratelimit"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+var (
+	// AuthRateLimit is the budget of each client IP address on the public auth endpoints
+	AuthRateLimit = ratelimit.Limit{
+		Burst:  utils.GetIntEnv("RATE_LIMIT_AUTH_BURST", 10),
+		Period: utils.GetDurationEnv("RATE_LIMIT_AUTH_PERIOD", time.Minute),
+	}
+
+	// MoneyRateLimit is the budget of each account or API key on the endpoints that move funds
+	MoneyRateLimit = ratelimit.Limit{
+		Burst:  utils.GetIntEnv("RATE_LIMIT_MONEY_BURST", 30),
+		Period: utils.GetDurationEnv("RATE_LIMIT_MONEY_PERIOD", time.Minute),
+	}
+)
+
+// RateLimit returns a middleware function that spends one token of the named budget per request.
+// Clients are told apart by API key, then by account, then by IP address, so it should run after
+// APIKeyAuth and Auth where there are any. Requests are let through if the store fails.
+func RateLimit(store ratelimit.Store, budget string, limit ratelimit.Limit) echo.MiddlewareFunc {
+	policy := fmt.Sprintf("%d;w=%d", limit.Burst, int(limit.Period.Seconds()))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			result, err := store.Take(c.Request().Context(), budget+":"+rateLimitClient(c), limit, time.Now())
+			if err != nil {
+				log.Error().Err(err).Str("budget", budget).Msg("Failed to check rate limit")
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("RateLimit-Policy", policy)
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", ceilSeconds(result.Reset))
+
+			if !result.Allowed {
+				header.Set("Retry-After", ceilSeconds(result.RetryAfter))
+				return c.JSON(http.StatusTooManyRequests, map[string]string{
+					"error": "Rate limit exceeded, try again later",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// rateLimitClient identifies the client a request is counted against
+func rateLimitClient(c echo.Context) string {
+	principal := GetUserID(c)
+	switch {
+	case principal != nil && principal.APIKeyID != nil:
+		return "key:" + principal.APIKeyID.Hex()
+	case principal != nil:
+		return "user:" + principal.UserID.Hex()
+	default:
+		return "ip:" + c.RealIP()
+	}
+}
+
+// ceilSeconds formats d as whole seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
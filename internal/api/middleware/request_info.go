@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// RequestInfo returns a middleware function that passes the client IP, user agent and request ID on
+// to the service layer through the request context. The IP is resolved by the server's IP extractor, see
+// ClientIPExtractor. It must run after the RequestID middleware.
+func RequestInfo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			info := models.RequestInfo{
+				IP:        c.RealIP(),
+				UserAgent: req.UserAgent(),
+				RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+			}
+			c.SetRequest(req.WithContext(models.ContextWithRequestInfo(req.Context(), info)))
+
+			return next(c)
+		}
+	}
+}
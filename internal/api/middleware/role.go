@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// RequireRole returns a middleware function that only lets principals with one of the given roles through.
+// It must run after Auth.
+func RequireRole(roles ...models.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := GetUserID(c)
+			if principal == nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Authentication is required",
+				})
+			}
+
+			for _, role := range roles {
+				if principal.Role == role {
+					return next(c)
+				}
+			}
+
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Insufficient role for this operation",
+			})
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupAccountRoutes sets up all account related routes
+// @Summary Setup account routes
+// @Description Configures account endpoints under /api/v1/accounts
+// @Tags accounts
+func SetupAccountRoutes(g *echo.Group, h *handlers.AccountHandler, transactions *handlers.TransactionHandler) {
+	accounts := g.Group("/accounts")
+
+	// POST /api/v1/accounts
+	accounts.POST("", h.OpenAccount)
+
+	// GET /api/v1/accounts
+	accounts.GET("", h.ListAccounts)
+
+	// GET /api/v1/accounts/:id
+	accounts.GET("/:id", h.GetAccount)
+
+	// POST /api/v1/accounts/:id/holders
+	accounts.POST("/:id/holders", h.AddHolder)
+
+	// DELETE /api/v1/accounts/:id/holders/:user_id
+	accounts.DELETE("/:id/holders/:user_id", h.RemoveHolder)
+
+	// GET /api/v1/accounts/:id/transactions
+	accounts.GET("/:id/transactions", transactions.ListAccountTransactions)
+}
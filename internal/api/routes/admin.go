@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupAdminRoutes sets up user, account, currency and limit management routes
+// @Summary Setup admin routes
+// @Description Configures admin-only endpoints on the /api/v1/admin group
+// @Tags admin
+func SetupAdminRoutes(admin *echo.Group, users *handlers.UserHandler, accounts *handlers.AccountHandler, balances *handlers.BalanceHandler, currencies *handlers.CurrencyHandler, limits *handlers.LimitHandler) {
+	// GET /api/v1/admin/users
+	admin.GET("/users", users.ListUsers)
+
+	// GET /api/v1/admin/users/:id
+	admin.GET("/users/:id", users.GetUser)
+
+	// PUT /api/v1/admin/users/:id/role
+	admin.PUT("/users/:id/role", users.UpdateRole)
+
+	// POST /api/v1/admin/users/:id/unlock
+	admin.POST("/users/:id/unlock", users.UnlockUser)
+
+	// POST /api/v1/admin/users/:id/block
+	admin.POST("/users/:id/block", users.BlockUser)
+
+	// POST /api/v1/admin/users/:id/unblock
+	admin.POST("/users/:id/unblock", users.UnblockUser)
+
+	// POST /api/v1/admin/users/:id/deactivate
+	admin.POST("/users/:id/deactivate", users.DeactivateUser)
+
+	// PUT /api/v1/admin/accounts/:account_id/tier
+	admin.PUT("/accounts/:account_id/tier", accounts.SetTier)
+
+	// POST /api/v1/admin/accounts/:account_id/balances/rebuild
+	admin.POST("/accounts/:account_id/balances/rebuild", balances.RebuildBalances)
+
+	// PUT /api/v1/admin/accounts/:account_id/balances/:currency/overdraft
+	admin.PUT("/accounts/:account_id/balances/:currency/overdraft", balances.SetOverdraft)
+
+	// PATCH /api/v1/admin/currencies/:code
+	admin.PATCH("/currencies/:code", currencies.UpdateCurrency)
+
+	// DELETE /api/v1/admin/currencies/:code
+	admin.DELETE("/currencies/:code", currencies.ResetCurrency)
+
+	// GET /api/v1/admin/limits
+	admin.GET("/limits", limits.ListPolicies)
+
+	// PUT /api/v1/admin/limits/:tier/:currency/:direction
+	admin.PUT("/limits/:tier/:currency/:direction", limits.SetPolicy)
+
+	// DELETE /api/v1/admin/limits/:tier/:currency/:direction
+	admin.DELETE("/limits/:tier/:currency/:direction", limits.DeletePolicy)
+}
+
+// SetupAuditorRoutes sets up read-only routes over every user and account
+// @Summary Setup auditor routes
+// @Description Configures read-only endpoints on the /api/v1/audit group
+// @Tags audit
+func SetupAuditorRoutes(audit *echo.Group, users *handlers.UserHandler, transactions *handlers.TransactionHandler, balances *handlers.BalanceHandler, chains *handlers.ChainHandler) {
+	// GET /api/v1/audit/users
+	audit.GET("/users", users.ListUsers)
+
+	// GET /api/v1/audit/accounts/:account_id/balances
+	audit.GET("/accounts/:account_id/balances", balances.GetBalances)
+
+	// GET /api/v1/audit/accounts/:id/transactions
+	audit.GET("/accounts/:id/transactions", transactions.ListAccountTransactions)
+
+	// GET /api/v1/audit/accounts/:id/chain
+	audit.GET("/accounts/:id/chain", chains.VerifyChain)
+
+	// GET /api/v1/audit/transactions/:id
+	audit.GET("/transactions/:id", transactions.GetTransaction)
+}
+
+// SetupTellerRoutes sets up the routes tellers use to serve customers at the counter
+// @Summary Setup teller routes
+// @Description Configures teller endpoints on the /api/v1/teller group. Tellers deposit through /api/v1/transactions/deposit.
+// @Tags teller
+func SetupTellerRoutes(teller *echo.Group, balances *handlers.BalanceHandler) {
+	// GET /api/v1/teller/accounts/:account_id/balances
+	teller.GET("/accounts/:account_id/balances", balances.GetBalances)
+}
+
+// SetupAuditLogRoutes sets up the routes that read the audit log
+// @Summary Setup audit log routes
+// @Description Configures the audit log endpoint on the /api/v1/admin/audit group, open to auditors and admins
+// @Tags audit
+func SetupAuditLogRoutes(auditLog *echo.Group, events *handlers.AuditHandler) {
+	// GET /api/v1/admin/audit
+	auditLog.GET("", events.ListEvents)
+}
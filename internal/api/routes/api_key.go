@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+)
+
+// SetupAPIKeyRoutes sets up API key management routes
+// @Summary Setup API key routes
+// @Description Configures endpoints to create, list, rotate and revoke API keys
+// @Tags api-keys
+func SetupAPIKeyRoutes(g *echo.Group, apiKeyHandler *handlers.APIKeyHandler) {
+	keys := g.Group("/api-keys")
+	keys.POST("", apiKeyHandler.CreateAPIKey)
+	keys.GET("", apiKeyHandler.ListAPIKeys)
+	keys.POST("/:id/rotate", apiKeyHandler.RotateAPIKey)
+	keys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+}
@@ -6,8 +6,37 @@ import (
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
 )
 
-func SetupAuthRoutes(e *echo.Echo, authHandler *handlers.AuthHandler) {
-	auth := e.Group("/auth")
+// SetupAuthRoutes sets up the public authentication routes, each request spending a token of the auth rate limit
+func SetupAuthRoutes(g *echo.Group, authHandler *handlers.AuthHandler, limit echo.MiddlewareFunc) {
+	auth := g.Group("/auth", limit)
 	auth.POST("/register", authHandler.Register)
 	auth.POST("/login", authHandler.Login)
+	auth.POST("/login/mfa", authHandler.VerifyMFA)
+	auth.POST("/refresh", authHandler.Refresh)
+	auth.POST("/logout", authHandler.Logout)
+	auth.POST("/password/forgot", authHandler.ForgotPassword)
+	auth.POST("/password/reset", authHandler.ResetPassword)
+	auth.POST("/email/verify", authHandler.VerifyEmail)
+	auth.POST("/phone/verify", authHandler.VerifyPhone)
+}
+
+// SetupMFARoutes sets up second factor management routes for authenticated accounts
+func SetupMFARoutes(g *echo.Group, authHandler *handlers.AuthHandler) {
+	mfa := g.Group("/auth/mfa")
+	mfa.POST("/enroll", authHandler.EnrollMFA)
+	mfa.POST("/activate", authHandler.ActivateMFA)
+}
+
+// SetupVerificationRoutes sets up routes that resend verification links to authenticated accounts
+func SetupVerificationRoutes(g *echo.Group, authHandler *handlers.AuthHandler) {
+	g.POST("/auth/email/verify/resend", authHandler.ResendVerification)
+	g.POST("/auth/phone/verify/resend", authHandler.ResendPhoneVerification)
+}
+
+// SetupProfileRoutes sets up the routes accounts use to read and change their own profile
+func SetupProfileRoutes(g *echo.Group, authHandler *handlers.AuthHandler) {
+	me := g.Group("/me")
+	me.GET("", authHandler.GetProfile)
+	me.PATCH("", authHandler.UpdateProfile)
+	me.POST("/password", authHandler.ChangePassword)
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupCurrencyRoutes sets up the currency registry routes
+// @Summary Setup currency routes
+// @Description Configures the currency listing under /api/v1/currencies
+// @Tags currencies
+func SetupCurrencyRoutes(g *echo.Group, h *handlers.CurrencyHandler) {
+	// GET /api/v1/currencies
+	g.GET("/currencies", h.ListCurrencies)
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupFXRoutes sets up all foreign exchange related routes
+// @Summary Setup FX routes
+// @Description Configures quote and conversion endpoints under /api/v1/fx.
+// Converting spends a token of the money rate limit.
+// @Tags fx
+func SetupFXRoutes(g *echo.Group, h *handlers.FXHandler, moneyLimit echo.MiddlewareFunc) {
+	fx := g.Group("/fx")
+
+	// POST /api/v1/fx/quotes
+	fx.POST("/quotes", h.CreateQuote)
+
+	// POST /api/v1/fx/convert
+	fx.POST("/convert", h.Convert, moneyLimit)
+}
@@ -8,13 +8,21 @@ import (
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/fx"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/ratelimit"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/sms"
 )
 
-func Setup(e *echo.Echo, db *mongo.Client, logger zerolog.Logger) {
+func Setup(e *echo.Echo, db *mongo.Database, mail mailer.Mailer, texter sms.Sender, rates fx.RateProvider, limiter ratelimit.Store, logger zerolog.Logger) {
 	// Middleware
 	e.Use(echomw.Recover())
 	e.Use(echomw.CORS())
+	e.Use(echomw.RequestID())
+	e.Use(middleware.RequestInfo())
 	e.Use(middleware.RequestLogger(logger))
 
 	// Health Check
@@ -27,16 +35,55 @@ func Setup(e *echo.Echo, db *mongo.Client, logger zerolog.Logger) {
 	v1 := e.Group("/api/v1")
 
 	// Public routes (no authentication required)
-	SetupAuthRoutes(v1, db)
+	userRepo := repository.NewUserRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	auditRepo := repository.NewAuditEventRepository(db)
+	authHandler := handlers.NewAuthHandler(services.NewAuthService(userRepo, accountRepo, refreshTokenRepo, repository.NewActionTokenRepository(db), loginAttemptRepo, auditRepo, mail, texter))
+	SetupAuthRoutes(v1, authHandler, middleware.RateLimit(limiter, "auth", middleware.AuthRateLimit))
 
-	// Protected routes (authentication required)
-	protected := v1.Group("", middleware.Auth())
+	// Protected routes (authentication required, by API key or token)
+	apiKeyService := services.NewAPIKeyService(repository.NewAPIKeyRepository(db), userRepo, auditRepo)
+	protected := v1.Group("", middleware.APIKeyAuth(apiKeyService), middleware.Auth(userRepo, refreshTokenRepo))
+	transactionRepo := repository.NewTransactionRepository(db)
+	accessService := services.NewAccessService(transactionRepo, accountRepo)
+	moneyLimit := middleware.RateLimit(limiter, "money", middleware.MoneyRateLimit)
 
 	// Transaction routes
-	transactionHandler := handlers.NewTransactionHandler(services.NewTransactionService(db))
-	SetupTransactionRoutes(protected, transactionHandler)
+	transactionService := services.NewTransactionService(repository.NewTxRunner(db), transactionRepo, repository.NewBalanceRepository(db), repository.NewIdempotencyRepository(db), repository.NewLedgerRepository(db), auditRepo, accountRepo, userRepo, repository.NewLimitPolicyRepository(db))
+	transactionHandler := handlers.NewTransactionHandler(transactionService, accessService)
+	SetupTransactionRoutes(protected, transactionHandler, moneyLimit)
+
+	// FX routes
+	fxService := services.NewFXService(transactionService, repository.NewFXQuoteRepository(db), rates)
+	SetupFXRoutes(protected, handlers.NewFXHandler(fxService, accessService), moneyLimit)
+
+	// Account routes
+	accountHandler := handlers.NewAccountHandler(services.NewAccountService(accountRepo, userRepo, auditRepo), accessService)
+	SetupAccountRoutes(protected, accountHandler, transactionHandler)
 
 	// Balance routes
-	balanceHandler := handlers.NewBalanceHandler(services.NewBalanceService(db))
+	balanceHandler := handlers.NewBalanceHandler(services.NewBalanceService(repository.NewTxRunner(db), repository.NewBalanceRepository(db), accountRepo, repository.NewLedgerRepository(db), auditRepo), accessService)
 	SetupBalanceRoutes(protected, balanceHandler)
+
+	// Currency routes
+	currencyHandler := handlers.NewCurrencyHandler(services.NewCurrencyService(repository.NewTxRunner(db), repository.NewCurrencyRepository(db), auditRepo))
+	SetupCurrencyRoutes(protected, currencyHandler)
+
+	// MFA, verification and profile routes
+	SetupMFARoutes(protected, authHandler)
+	SetupVerificationRoutes(protected, authHandler)
+	SetupProfileRoutes(protected, authHandler)
+
+	// API key routes
+	SetupAPIKeyRoutes(protected, handlers.NewAPIKeyHandler(apiKeyService))
+
+	// Role restricted routes
+	limitHandler := handlers.NewLimitHandler(services.NewLimitService(repository.NewLimitPolicyRepository(db), auditRepo))
+	userHandler := handlers.NewUserHandler(services.NewUserService(userRepo, loginAttemptRepo, refreshTokenRepo, auditRepo))
+	SetupAdminRoutes(protected.Group("/admin", middleware.RequireRole(models.RoleAdmin), middleware.RequireScope(models.PermissionAccountManage)), userHandler, accountHandler, balanceHandler, currencyHandler, limitHandler)
+	SetupAuditLogRoutes(protected.Group("/admin/audit", middleware.RequireRole(models.RoleAuditor, models.RoleAdmin), middleware.RequireScope(models.PermissionAccountRead)), handlers.NewAuditHandler(services.NewAuditService(auditRepo)))
+	SetupAuditorRoutes(protected.Group("/audit", middleware.RequireRole(models.RoleAuditor, models.RoleAdmin), middleware.RequireScope(models.PermissionAccountRead)), userHandler, transactionHandler, balanceHandler, handlers.NewChainHandler(services.NewChainService(transactionRepo)))
+	SetupTellerRoutes(protected.Group("/teller", middleware.RequireRole(models.RoleTeller, models.RoleAdmin)), balanceHandler)
 }
@@ -7,14 +7,33 @@ import (
 
 // SetupTransactionRoutes sets up all transaction related routes
 // @Summary Setup transaction routes
-// @Description Configures deposit and withdrawal endpoints under /api/v1/transactions
+// @Description Configures deposit, withdrawal and transfer endpoints under /api/v1/transactions.
+// Every endpoint that moves funds spends a token of the money rate limit.
 // @Tags transactions
-func SetupTransactionRoutes(g *echo.Group, h *handlers.TransactionHandler) {
+func SetupTransactionRoutes(g *echo.Group, h *handlers.TransactionHandler, moneyLimit echo.MiddlewareFunc) {
 	transactions := g.Group("/transactions")
 
 	// POST /api/v1/transactions/deposit
-	transactions.POST("/deposit", h.Deposit)
+	transactions.POST("/deposit", h.Deposit, moneyLimit)
 
 	// POST /api/v1/transactions/withdraw
-	transactions.POST("/withdraw", h.Withdraw)
+	transactions.POST("/withdraw", h.Withdraw, moneyLimit)
+
+	// POST /api/v1/transactions/transfer
+	transactions.POST("/transfer", h.Transfer, moneyLimit)
+
+	// POST /api/v1/transactions/authorize
+	transactions.POST("/authorize", h.Authorize, moneyLimit)
+
+	// GET /api/v1/transactions/:id
+	transactions.GET("/:id", h.GetTransaction)
+
+	// POST /api/v1/transactions/:id/capture
+	transactions.POST("/:id/capture", h.Capture, moneyLimit)
+
+	// POST /api/v1/transactions/:id/void
+	transactions.POST("/:id/void", h.Void, moneyLimit)
+
+	// POST /api/v1/transactions/:id/reverse
+	transactions.POST("/:id/reverse", h.Reverse, moneyLimit)
 }
@@ -7,13 +7,19 @@ import (
 	"github.com/go-playground/validator/v10"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 )
 
 var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
-	// Add custom validations here if needed
+	// currency accepts the upper-case code of any currency in the registry. Disabled currencies are
+	// refused by the services for money coming in only, so existing funds can still be moved out.
+	validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		_, ok := money.LookupCurrency(fl.Field().String())
+		return ok
+	})
 }
 
 // ValidateStruct validates a struct using validator tags and returns structured validation errors
@@ -46,6 +52,8 @@ func generateValidationMessage(err validator.FieldError) string {
 		return fmt.Sprintf("%s must not exceed %s characters", err.Field(), err.Param())
 	case "e164":
 		return "Invalid phone number format. Must be in E.164 format"
+	case "currency":
+		return fmt.Sprintf("%s must be the code of an ISO 4217 currency", err.Field())
 	default:
 		return fmt.Sprintf("%s is not valid", err.Field())
 	}
@@ -1,19 +1,39 @@
 package config
 
-import "github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+import (
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
 
 type Config struct {
-	MongoURI     string
-	DatabaseName string
-	Port         string
-	Environment  string
+	MongoURI           string
+	DatabaseName       string
+	Port               string
+	Environment        string
+	HoldExpiryInterval time.Duration
+	CurrencyRefresh    time.Duration
+	OverdraftCharge    time.Duration
+	MailOutput         string
+	SMSOutput          string
+	RateLimitStore     string
+	FXRates            string
+	TrustedProxies     string
 }
 
 func Load() *Config {
 	return &Config{
-		MongoURI:     utils.GetEnv("MONGO_URI", "mongodb://localhost:27017"),
-		DatabaseName: utils.GetEnv("DB_NAME", "axis_assessment"),
-		Port:         utils.GetEnv("PORT", "8080"),
-		Environment:  utils.GetEnv("ENV", "development"),
+		MongoURI:           utils.GetEnv("MONGO_URI", "mongodb://localhost:27017"),
+		DatabaseName:       utils.GetEnv("DB_NAME", "axis_assessment"),
+		Port:               utils.GetEnv("PORT", "8080"),
+		Environment:        utils.GetEnv("ENV", "development"),
+		HoldExpiryInterval: utils.GetDurationEnv("HOLD_EXPIRY_INTERVAL", time.Minute),
+		CurrencyRefresh:    utils.GetDurationEnv("CURRENCY_REFRESH_INTERVAL", time.Minute),
+		OverdraftCharge:    utils.GetDurationEnv("OVERDRAFT_CHARGE_INTERVAL", time.Hour),
+		MailOutput:         utils.GetEnv("MAIL_OUTPUT", "stdout"),
+		SMSOutput:          utils.GetEnv("SMS_OUTPUT", "stdout"),
+		RateLimitStore:     utils.GetEnv("RATE_LIMIT_STORE", "memory"),
+		FXRates:            utils.GetEnv("FX_RATES", "static"),
+		TrustedProxies:     utils.GetEnv("TRUSTED_PROXIES", ""),
 	}
 }
@@ -1,14 +1,25 @@
 package dtos
 
-import "time"
-
-// CreateAccountDTO represents the data needed to create an account in the repository
-type CreateAccountDTO struct {
-	Name        string
-	Email       string
-	PhoneNumber string
-	Password    string
-	Status      string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+import "github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+
+// OpenAccountRequest represents the body of POST /accounts
+type OpenAccountRequest struct {
+	Product string `json:"product" validate:"required,oneof=current savings"`
+	Name    string `json:"name" validate:"max=100"`
+}
+
+// AddHolderRequest represents the body of POST /accounts/:id/holders. The user is looked up by email.
+type AddHolderRequest struct {
+	Email       string              `json:"email" validate:"required,email"`
+	Permissions []models.Permission `json:"permissions" validate:"required,min=1,dive,oneof=account:read funds:deposit funds:withdraw account:manage"`
+}
+
+// SetAccountTierRequest represents the body of PUT /admin/accounts/:account_id/tier
+type SetAccountTierRequest struct {
+	Tier string `json:"tier" validate:"required,oneof=standard premium business"`
+}
+
+// AccountListResponse lists the accounts a user holds
+type AccountListResponse struct {
+	Accounts []models.Account `json:"accounts"`
 }
@@ -0,0 +1,25 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// CreateAPIKeyRequest represents the body of POST /api-keys
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=account:read funds:deposit funds:withdraw transaction:reverse account:manage"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeySecretResponse is returned when a key is created or rotated. The key is never shown again.
+type APIKeySecretResponse struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyListResponse lists the keys of an account
+type APIKeyListResponse struct {
+	APIKeys []models.APIKey `json:"api_keys"`
+}
@@ -0,0 +1,38 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditEventListQuery represents the query parameters of GET /admin/audit
+type AuditEventListQuery struct {
+	ActorID   string `query:"actor_id"`
+	UserID    string `query:"user_id"`
+	AccountID string `query:"account_id"`
+	Action    string `query:"action" validate:"max=64"`
+	From      string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To        string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor    string `query:"cursor"`
+}
+
+// AuditEventFilter narrows an audit event listing in the repository
+type AuditEventFilter struct {
+	ActorID         *primitive.ObjectID
+	TargetUserID    *primitive.ObjectID
+	TargetAccountID *primitive.ObjectID
+	Action          string
+	From            *time.Time
+	To              *time.Time
+	Before          *primitive.ObjectID
+	Limit           int
+}
+
+// AuditEventListResponse is one page of audit events, newest first
+type AuditEventListResponse struct {
+	Events     []models.AuditEvent `json:"events"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
@@ -14,10 +14,72 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8,max=72"`
+	IP       string `json:"-"` // Client IP, set by the handler for brute-force protection
 }
 
-// AuthResponse represents the authentication response
+// RefreshRequest represents the token refresh request data
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest represents the logout request data
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// ForgotPasswordRequest asks for a password reset link
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest sets a new password with the token from the reset link
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// VerifyEmailRequest confirms an email address with the token from the verification link
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// VerifyPhoneRequest confirms a phone number with the token from the texted verification link
+type VerifyPhoneRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// MFALoginRequest represents the second login step for accounts with MFA enabled
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+	IP           string `json:"-"` // Client IP, set by the handler for brute-force protection
+}
+
+// MFAActivateRequest confirms an MFA enrollment with a first code from the authenticator app
+type MFAActivateRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// AuthResponse represents the authentication response. When the account has MFA enabled, login only
+// returns MFARequired and an MFAToken to exchange at /auth/login/mfa.
 type AuthResponse struct {
-	Token string          `json:"token"`
-	User  *models.Account `json:"user"`
+	Token        string          `json:"token,omitempty"`
+	RefreshToken string          `json:"refresh_token,omitempty"`
+	ExpiresIn    int64           `json:"expires_in,omitempty"` // Access token lifetime in seconds
+	User         *models.User    `json:"user,omitempty"`
+	Account      *models.Account `json:"account,omitempty"` // Account opened on registration
+	MFARequired  bool            `json:"mfa_required,omitempty"`
+	MFAToken     string          `json:"mfa_token,omitempty"`
+}
+
+// MFAEnrollResponse carries the TOTP secret to add to an authenticator app
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFAActivateResponse carries the recovery codes, which are only shown once
+type MFAActivateResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
@@ -1,5 +1,7 @@
 package dtos
 
+import "github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+
 // BalanceResponse represents the response for getting account balances
 type BalanceResponse struct {
 	AccountID string            `json:"account_id"`
@@ -11,8 +13,20 @@ type BalancesResponse struct {
 	Balances []CurrencyBalance `json:"balances"`
 }
 
-// CurrencyBalance represents a balance for a specific currency
+// CurrencyBalance represents a balance for a specific currency. Current is the ledger
+// balance; Available excludes funds reserved by pending holds and includes unused overdraft.
 type CurrencyBalance struct {
-	Currency string  `json:"currency" validate:"required,len=3"`
-	Amount   float64 `json:"amount" validate:"required,gte=0"`
+	Currency       string        `json:"currency" validate:"required,len=3"`
+	Current        money.Decimal `json:"current" validate:"required"`
+	Available      money.Decimal `json:"available" validate:"required"`
+	OverdraftLimit money.Decimal `json:"overdraft_limit,omitempty"`
+	OverdraftUsed  money.Decimal `json:"overdraft_used,omitempty"`
+}
+
+// SetOverdraftRequest represents the body of PUT /admin/accounts/:account_id/balances/:currency/overdraft.
+// A zero limit removes the overdraft facility for new withdrawals.
+type SetOverdraftRequest struct {
+	Limit           money.Decimal `json:"limit" validate:"required"`
+	InterestRateBps int           `json:"interest_rate_bps" validate:"min=0,max=10000"`
+	DailyFee        money.Decimal `json:"daily_fee"`
 }
@@ -0,0 +1,28 @@
+package dtos
+
+// Reasons a transaction chain verification can fail
+const (
+	ChainBreakHashMismatch           = "hash_mismatch"            // A covered field no longer matches the stored hash
+	ChainBreakPreviousHashMismatch   = "previous_hash_mismatch"   // The previous transaction was edited, replaced or removed
+	ChainBreakSequenceGap            = "sequence_gap"             // A transaction is missing from the chain
+	ChainBreakUnchained              = "unchained"                // A transaction was inserted outside the chain
+	ChainBreakReversedAmountMismatch = "reversed_amount_mismatch" // The amount recorded as reversed differs from the chained reversals
+)
+
+// ChainBreak locates the first transaction at which an account's chain stops verifying
+type ChainBreak struct {
+	Sequence      int64  `json:"sequence"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+// ChainVerification is the result of walking an account's transaction chain. HeadHash covers every
+// verified transaction, so recording it elsewhere lets a later verification prove nothing was truncated.
+type ChainVerification struct {
+	AccountID    string      `json:"account_id"`
+	Valid        bool        `json:"valid"`
+	Verified     int64       `json:"verified"`
+	HeadSequence int64       `json:"head_sequence"`
+	HeadHash     string      `json:"head_hash,omitempty"`
+	Break        *ChainBreak `json:"break,omitempty"`
+}
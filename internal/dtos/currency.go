@@ -0,0 +1,14 @@
+package dtos
+
+import "github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+
+// UpdateCurrencyRequest represents the body of PATCH /admin/currencies/:code. Omitted fields are left as they are.
+type UpdateCurrencyRequest struct {
+	MinorUnits *int  `json:"minor_units" validate:"omitempty,min=0,max=4"`
+	Enabled    *bool `json:"enabled"`
+}
+
+// CurrencyListResponse lists the currency registry
+type CurrencyListResponse struct {
+	Currencies []money.Currency `json:"currencies"`
+}
@@ -0,0 +1,44 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+)
+
+// FXQuoteRequest represents the body of POST /fx/quotes. Amount is what is sold, in SellCurrency.
+type FXQuoteRequest struct {
+	AccountID    string        `json:"account_id" validate:"required"`
+	SellCurrency string        `json:"sell_currency" validate:"required,currency"`
+	BuyCurrency  string        `json:"buy_currency" validate:"required,currency"`
+	Amount       money.Decimal `json:"amount" validate:"required"`
+}
+
+// FXConvertRequest represents the body of POST /fx/convert
+type FXConvertRequest struct {
+	QuoteID string `json:"quote_id" validate:"required"`
+}
+
+// FXQuoteResponse represents a locked conversion price
+type FXQuoteResponse struct {
+	QuoteID      string        `json:"quote_id"`
+	AccountID    string        `json:"account_id"`
+	SellCurrency string        `json:"sell_currency"`
+	BuyCurrency  string        `json:"buy_currency"`
+	SellAmount   money.Decimal `json:"sell_amount"`
+	BuyAmount    money.Decimal `json:"buy_amount"`
+	Rate         string        `json:"rate"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+}
+
+// FXConversionResponse represents an executed quote
+type FXConversionResponse struct {
+	ConversionID        string        `json:"conversion_id"`
+	QuoteID             string        `json:"quote_id"`
+	DebitTransactionID  string        `json:"debit_transaction_id"`
+	CreditTransactionID string        `json:"credit_transaction_id"`
+	SellAmount          money.Decimal `json:"sell_amount"`
+	SellCurrency        string        `json:"sell_currency"`
+	BuyAmount           money.Decimal `json:"buy_amount"`
+	BuyCurrency         string        `json:"buy_currency"`
+}
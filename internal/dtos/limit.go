@@ -0,0 +1,50 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+)
+
+// SetLimitPolicyRequest represents the body of PUT /admin/limits/:tier/:currency/:direction. Omitted or
+// zero limits are not enforced.
+type SetLimitPolicyRequest struct {
+	MinPerTransaction money.Decimal `json:"min_per_transaction"`
+	MaxPerTransaction money.Decimal `json:"max_per_transaction"`
+	DailyCount        int64         `json:"daily_count" validate:"min=0"`
+	DailyAmount       money.Decimal `json:"daily_amount"`
+	MonthlyCount      int64         `json:"monthly_count" validate:"min=0"`
+	MonthlyAmount     money.Decimal `json:"monthly_amount"`
+}
+
+// LimitPolicyResponse represents the limits of one tier, currency and direction. Limits that are not
+// enforced are omitted.
+type LimitPolicyResponse struct {
+	Tier              string        `json:"tier"`
+	Currency          string        `json:"currency"`
+	Direction         string        `json:"direction"`
+	MinPerTransaction money.Decimal `json:"min_per_transaction,omitempty"`
+	MaxPerTransaction money.Decimal `json:"max_per_transaction,omitempty"`
+	DailyCount        int64         `json:"daily_count,omitempty"`
+	DailyAmount       money.Decimal `json:"daily_amount,omitempty"`
+	MonthlyCount      int64         `json:"monthly_count,omitempty"`
+	MonthlyAmount     money.Decimal `json:"monthly_amount,omitempty"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// LimitPolicyListResponse lists every limit policy
+type LimitPolicyListResponse struct {
+	Policies []LimitPolicyResponse `json:"policies"`
+}
+
+// LimitExceededDetails tells the client which limit a rejected movement hit. Allowed and Used are
+// transaction counts for count limits and decimal amounts of Currency otherwise.
+type LimitExceededDetails struct {
+	Limit     string     `json:"limit"`
+	Direction string     `json:"direction"`
+	Currency  string     `json:"currency"`
+	Tier      string     `json:"tier"`
+	Allowed   string     `json:"allowed"`
+	Used      string     `json:"used,omitempty"`      // Used so far in the window; unset for per-transaction limits
+	ResetsAt  *time.Time `json:"resets_at,omitempty"` // When the window restarts; unset for per-transaction limits
+}
@@ -1,23 +1,149 @@
 package dtos
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // TransactionRequest represents the transaction request data
 type TransactionRequest struct {
-	AccountID string  `json:"account_id" validate:"required"`
-	Amount    float64 `json:"amount" validate:"required,gt=0"`
-	Currency  string  `json:"currency" validate:"required,len=3"`
+	AccountID string        `json:"account_id" validate:"required"`
+	Amount    money.Decimal `json:"amount" validate:"required"`
+	Currency  string        `json:"currency" validate:"required,currency"`
+}
+
+// TransferRequest represents the account-to-account transfer request data
+type TransferRequest struct {
+	SourceAccountID      string        `json:"source_account_id" validate:"required"`
+	DestinationAccountID string        `json:"destination_account_id" validate:"required"`
+	Amount               money.Decimal `json:"amount" validate:"required"`
+	Currency             string        `json:"currency" validate:"required,currency"`
+	Description          string        `json:"description" validate:"max=255"`
 }
 
 // CreateTransactionDTO represents the data needed to create a transaction
 type CreateTransactionDTO struct {
-	AccountID primitive.ObjectID
-	Amount    float64
-	Currency  string
-	Type      string
+	AccountID      primitive.ObjectID
+	Amount         money.Amount
+	Currency       string
+	Type           string
+	Description    string
+	TransferID     *primitive.ObjectID
+	ConversionID   *primitive.ObjectID
+	JournalEntryID primitive.ObjectID
+	Status         string // Defaults to completed
+	HeldAmount     money.Amount
+	ExpiresAt      *time.Time
+	ReversalOf     *primitive.ObjectID
+	Charge         bool
+	APIKeyID       *primitive.ObjectID // Key that authenticated the request, if any
 }
 
 // TransactionResponse represents the transaction response data
 type TransactionResponse struct {
 	TransactionID string `json:"transaction_id"`
 }
+
+// TransferResponse represents the transfer response data
+type TransferResponse struct {
+	TransferID          string `json:"transfer_id"`
+	DebitTransactionID  string `json:"debit_transaction_id"`
+	CreditTransactionID string `json:"credit_transaction_id"`
+}
+
+// TransactionHistoryQuery represents the query parameters for listing an account's transactions
+type TransactionHistoryQuery struct {
+	Type      string        `query:"type" validate:"omitempty,oneof=debit credit"`
+	Status    string        `query:"status" validate:"omitempty,oneof=pending completed failed cancelled"`
+	Currency  string        `query:"currency" validate:"omitempty,currency"`
+	From      string        `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To        string        `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	MinAmount money.Decimal `query:"min_amount"`
+	MaxAmount money.Decimal `query:"max_amount"`
+	Cursor    string        `query:"cursor"`
+	Limit     int           `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// TransactionFilter represents the parsed filters used to query transactions in the repository
+type TransactionFilter struct {
+	AccountID primitive.ObjectID
+	Type      string
+	Status    string
+	Currency  string
+	From      *time.Time
+	To        *time.Time
+	MinAmount *money.Amount
+	MaxAmount *money.Amount
+	After     *TransactionCursor
+	Limit     int
+}
+
+// TransactionCursor is the keyset position of the last transaction on a page
+type TransactionCursor struct {
+	TransactionDate time.Time
+	ID              primitive.ObjectID
+}
+
+// TransactionDetail represents a single transaction in API responses
+type TransactionDetail struct {
+	ID              string        `json:"id"`
+	AccountID       string        `json:"account_id"`
+	Type            string        `json:"type"`
+	Amount          money.Decimal `json:"amount"`
+	Currency        string        `json:"currency"`
+	Status          string        `json:"status"`
+	Reference       string        `json:"reference,omitempty"`
+	Description     string        `json:"description,omitempty"`
+	TransferID      string        `json:"transfer_id,omitempty"`
+	ReversalOf      string        `json:"reversal_of,omitempty"`
+	ReversedAmount  money.Decimal `json:"reversed_amount,omitempty"`
+	APIKeyID        string        `json:"api_key_id,omitempty"`
+	TransactionDate time.Time     `json:"transaction_date"`
+}
+
+// TransactionHistoryResponse represents a page of transactions
+type TransactionHistoryResponse struct {
+	Transactions []TransactionDetail `json:"transactions"`
+	NextCursor   string              `json:"next_cursor,omitempty"`
+}
+
+// AuthorizeRequest represents the request to place a hold on an account's funds
+type AuthorizeRequest struct {
+	AccountID        string        `json:"account_id" validate:"required"`
+	Amount           money.Decimal `json:"amount" validate:"required"`
+	Currency         string        `json:"currency" validate:"required,currency"`
+	Description      string        `json:"description" validate:"max=255"`
+	ExpiresInSeconds int           `json:"expires_in_seconds" validate:"omitempty,min=60,max=2592000"`
+}
+
+// CaptureRequest represents the request to capture a hold; an empty amount captures it in full
+type CaptureRequest struct {
+	Amount money.Decimal `json:"amount"`
+}
+
+// HoldResponse represents the state of a hold
+type HoldResponse struct {
+	TransactionID string        `json:"transaction_id"`
+	Status        string        `json:"status"`
+	HeldAmount    money.Decimal `json:"held_amount"`
+	Amount        money.Decimal `json:"amount"`
+	Currency      string        `json:"currency"`
+	ExpiresAt     *time.Time    `json:"expires_at,omitempty"`
+}
+
+// ReverseRequest represents the request to reverse a transaction; an empty amount reverses the remainder
+type ReverseRequest struct {
+	Amount money.Decimal `json:"amount"`
+	Reason string        `json:"reason" validate:"required,max=255"`
+}
+
+// ReversalResponse represents the outcome of a reversal
+type ReversalResponse struct {
+	OriginalTransactionID string        `json:"original_transaction_id"`
+	ReversalTransactionID string        `json:"reversal_transaction_id"`
+	ReversedAmount        money.Decimal `json:"reversed_amount"`
+	RemainingAmount       money.Decimal `json:"remaining_amount"`
+	Currency              string        `json:"currency"`
+}
@@ -0,0 +1,74 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateUserDTO represents the data needed to create a user in the repository
+type CreateUserDTO struct {
+	Name        string
+	Email       string
+	PhoneNumber string
+	Password    string
+	Status      string
+	Role        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// UserFilter narrows a user listing in the repository
+type UserFilter struct {
+	Role   string
+	Status string
+	After  *primitive.ObjectID
+	Limit  int
+}
+
+// UserListQuery represents the query parameters of GET /admin/users
+type UserListQuery struct {
+	Role   string `query:"role" validate:"omitempty,oneof=customer teller admin auditor"`
+	Status string `query:"status" validate:"omitempty,oneof=active inactive blocked"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string `query:"cursor"`
+}
+
+// UserListResponse is one page of users
+type UserListResponse struct {
+	Users      []models.User `json:"users"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// UpdateRoleRequest represents the body of PUT /admin/users/:id/role
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=customer teller admin auditor"`
+}
+
+// UnlockUserRequest represents the body of POST /admin/users/:id/unlock
+type UnlockUserRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// ChangeUserStatusRequest represents the body of POST /admin/users/:id/block, /unblock and /deactivate
+type ChangeUserStatusRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// UpdateProfileRequest represents the body of PATCH /me. Omitted fields are left unchanged. UpdatedAt
+// must be the updated_at of the profile the change is based on, so that concurrent changes are not
+// overwritten. Changing the email or phone number requires the current password.
+type UpdateProfileRequest struct {
+	Name            *string    `json:"name" validate:"omitempty,min=2,max=100"`
+	Email           *string    `json:"email" validate:"omitempty,email"`
+	PhoneNumber     *string    `json:"phone_number" validate:"omitempty,e164"`
+	CurrentPassword string     `json:"current_password" validate:"required_with=Email PhoneNumber,max=72"`
+	UpdatedAt       *time.Time `json:"updated_at" validate:"required"`
+}
+
+// ChangePasswordRequest represents the body of POST /me/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=72"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+}
@@ -8,43 +8,124 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Account is a product that holds money, such as a current or savings account. Balances, transactions
+// and ledger postings reference it by ID. It is held by one owner and any number of joint holders.
 type Account struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name        string            `bson:"name" json:"name" validate:"required"`
-	Email       string            `bson:"email" json:"email" validate:"required,email"`
-	PhoneNumber string            `bson:"phone_number" json:"phone_number" validate:"required"`
-	Password    string            `bson:"password" json:"-"` // Password is never returned in JSON
-	Status      AccountStatus     `bson:"status" json:"status"`
-	CreatedAt   time.Time         `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time         `bson:"updated_at" json:"updated_at"`
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Product   AccountProduct     `bson:"product" json:"product"`
+	Name      string             `bson:"name,omitempty" json:"name,omitempty"` // Label chosen by the owner
+	Tier      AccountTier        `bson:"tier,omitempty" json:"tier,omitempty"` // Selects the limit policies; unset means standard
+	Holders   []AccountHolder    `bson:"holders" json:"holders"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
-type AccountStatus string
+// AccountHolder is a user who may act on an account. The owner may do everything; joint holders are
+// limited to the permissions the owner granted them.
+type AccountHolder struct {
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Role        HolderRole         `bson:"role" json:"role"`
+	Permissions []Permission       `bson:"permissions,omitempty" json:"permissions,omitempty"` // Unset for the owner
+	AddedAt     time.Time          `bson:"added_at" json:"added_at"`
+}
+
+type HolderRole string
+
+const (
+	HolderRoleOwner HolderRole = "owner"
+	HolderRoleJoint HolderRole = "joint"
+)
+
+type AccountProduct string
+
+const (
+	AccountProductCurrent AccountProduct = "current"
+	AccountProductSavings AccountProduct = "savings"
+)
+
+// AccountTier groups accounts that share velocity limit policies
+type AccountTier string
 
 const (
-	AccountStatusActive   AccountStatus = "active"
-	AccountStatusInactive AccountStatus = "inactive"
-	AccountStatusBlocked  AccountStatus = "blocked"
+	AccountTierStandard AccountTier = "standard"
+	AccountTierPremium  AccountTier = "premium"
+	AccountTierBusiness AccountTier = "business"
 )
 
+// HolderPermissions lists the permissions an owner may grant a joint holder
+var HolderPermissions = []Permission{
+	PermissionAccountRead,
+	PermissionFundsDeposit,
+	PermissionFundsWithdraw,
+	PermissionAccountManage,
+}
+
 // Collection related constants
 const (
 	AccountCollection = "accounts"
 )
 
+// Holder returns the entry of userID among the account's holders, or nil if the user does not hold it
+func (a *Account) Holder(userID primitive.ObjectID) *AccountHolder {
+	for i := range a.Holders {
+		if a.Holders[i].UserID == userID {
+			return &a.Holders[i]
+		}
+	}
+	return nil
+}
+
+// IsAccountTier reports whether t is a known account tier
+func IsAccountTier(t AccountTier) bool {
+	switch t {
+	case AccountTierStandard, AccountTierPremium, AccountTierBusiness:
+		return true
+	}
+	return false
+}
+
+// LimitTier returns the tier whose limit policies apply to the account
+func (a *Account) LimitTier() AccountTier {
+	if a.Tier == "" {
+		return AccountTierStandard
+	}
+	return a.Tier
+}
+
+// Allows reports whether the holder may perform permission on the account. Holding an account never
+// grants staff-only permissions such as reversing its transactions.
+func (h *AccountHolder) Allows(permission Permission) bool {
+	if !IsHolderPermission(permission) {
+		return false
+	}
+	if h.Role == HolderRoleOwner {
+		return true
+	}
+	for _, p := range h.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHolderPermission reports whether p may be granted to a joint holder
+func IsHolderPermission(p Permission) bool {
+	for _, allowed := range HolderPermissions {
+		if p == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // EnsureIndexes creates the required indexes for the Account collection
 func (a *Account) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 	indexes := []mongo.IndexModel{
 		{
-			Keys:    bson.D{{Key: "email", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys:    bson.D{{Key: "phone_number", Value: 1}},
-			Options: options.Index().SetUnique(true),
+			Keys: bson.D{{Key: "holders.user_id", Value: 1}},
 		},
 	}
 
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ActionToken is a single-use token mailed to a user to confirm an action, such as resetting
+// the password or verifying the email address. Expired tokens are removed by a TTL index.
+type ActionToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Purpose   ActionPurpose      `bson:"purpose" json:"purpose"`
+	TokenHash string             `bson:"token_hash" json:"-"`                    // SHA-256 of the mailed token
+	Email     string             `bson:"email" json:"email"`                     // User email when the token was issued
+	Phone     string             `bson:"phone,omitempty" json:"phone,omitempty"` // User phone number when the token was issued
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty" json:"used_at,omitempty"`
+}
+
+type ActionPurpose string
+
+const (
+	ActionPasswordReset     ActionPurpose = "password_reset"
+	ActionEmailVerification ActionPurpose = "email_verification"
+	ActionPhoneVerification ActionPurpose = "phone_verification"
+)
+
+// Collection related constants
+const (
+	ActionTokenCollection = "action_tokens"
+)
+
+// IsUsable reports whether the token can still be redeemed at now
+func (t *ActionToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// EnsureIndexes creates the required indexes for the ActionToken collection
+func (t *ActionToken) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "purpose", Value: 1},
+			},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	col := db.Collection(ActionTokenCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", ActionTokenCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", ActionTokenCollection).Msg("Indexes created successfully")
+	return nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// APIKey lets a machine client act on behalf of a user within a set of scopes
+type APIKey struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Name        string              `bson:"name" json:"name"`
+	Prefix      string              `bson:"prefix" json:"prefix"` // First characters of the key, to tell keys apart
+	KeyHash     string              `bson:"key_hash" json:"-"`    // SHA-256 of the full key
+	Scopes      []Permission        `bson:"scopes" json:"scopes"`
+	ExpiresAt   *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RotatedFrom *primitive.ObjectID `bson:"rotated_from,omitempty" json:"rotated_from,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// Collection related constants
+const (
+	APIKeyCollection = "api_keys"
+)
+
+// IsActive reports whether the key can still authenticate requests at now
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// EnsureIndexes creates the required indexes for the APIKey collection
+func (k *APIKey) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+	}
+
+	col := db.Collection(APIKeyCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", APIKeyCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", APIKeyCollection).Msg("Indexes created successfully")
+	return nil
+}
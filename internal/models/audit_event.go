@@ -0,0 +1,162 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditAction names a security or money event
+type AuditAction string
+
+const (
+	AuditActionRegister           AuditAction = "auth.register"
+	AuditActionLogin              AuditAction = "auth.login"
+	AuditActionLoginFailed        AuditAction = "auth.login_failed"
+	AuditActionLogout             AuditAction = "auth.logout"
+	AuditActionPasswordReset      AuditAction = "auth.password_reset"
+	AuditActionPasswordChanged    AuditAction = "auth.password_changed"
+	AuditActionMFAEnabled         AuditAction = "auth.mfa_enabled"
+	AuditActionAPIKeyCreated      AuditAction = "api_key.created"
+	AuditActionAPIKeyRotated      AuditAction = "api_key.rotated"
+	AuditActionAPIKeyRevoked      AuditAction = "api_key.revoked"
+	AuditActionRoleChanged        AuditAction = "account.role_changed"
+	AuditActionAccountLocked      AuditAction = "account.locked"
+	AuditActionAccountUnlocked    AuditAction = "account.unlocked"
+	AuditActionProfileUpdated     AuditAction = "account.profile_updated"
+	AuditActionAccountBlocked     AuditAction = "account.blocked"
+	AuditActionAccountUnblocked   AuditAction = "account.unblocked"
+	AuditActionAccountDeactivated AuditAction = "account.deactivated"
+	AuditActionAccountOpened      AuditAction = "account.opened"
+	AuditActionHolderAdded        AuditAction = "account.holder_added"
+	AuditActionHolderRemoved      AuditAction = "account.holder_removed"
+	AuditActionOverdraftSet       AuditAction = "account.overdraft_set"
+	AuditActionTierChanged        AuditAction = "account.tier_changed"
+	AuditActionDeposit            AuditAction = "funds.deposit"
+	AuditActionWithdrawal         AuditAction = "funds.withdrawal"
+	AuditActionTransfer           AuditAction = "funds.transfer"
+	AuditActionHoldPlaced         AuditAction = "funds.hold_placed"
+	AuditActionHoldCaptured       AuditAction = "funds.hold_captured"
+	AuditActionHoldVoided         AuditAction = "funds.hold_voided"
+	AuditActionReversal           AuditAction = "funds.reversal"
+	AuditActionFXConversion       AuditAction = "funds.fx_conversion"
+	AuditActionOverdraftCharged   AuditAction = "funds.overdraft_charged"
+	AuditActionBalancesRebuilt    AuditAction = "funds.balances_rebuilt"
+	AuditActionCurrencyUpdated    AuditAction = "currency.updated"
+	AuditActionCurrencyReset      AuditAction = "currency.reset"
+	AuditActionLimitPolicySet     AuditAction = "limits.policy_set"
+	AuditActionLimitPolicyDeleted AuditAction = "limits.policy_deleted"
+)
+
+// IdentityAuditActions lists the actions about a login identity. Before users were split from accounts
+// these events named the identity in target_account_id; the migrated user kept that ID.
+var IdentityAuditActions = []AuditAction{
+	AuditActionRegister,
+	AuditActionLogin,
+	AuditActionLoginFailed,
+	AuditActionLogout,
+	AuditActionPasswordReset,
+	AuditActionPasswordChanged,
+	AuditActionMFAEnabled,
+	AuditActionRoleChanged,
+	AuditActionAccountLocked,
+	AuditActionAccountUnlocked,
+	AuditActionProfileUpdated,
+	AuditActionAccountBlocked,
+	AuditActionAccountUnblocked,
+	AuditActionAccountDeactivated,
+}
+
+// AuditEvent records who did what to which user or account. Events are only ever inserted; money events
+// are written in the same Mongo transaction as the balance change they describe.
+type AuditEvent struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Action          AuditAction         `bson:"action" json:"action"`
+	ActorID         *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // Unset for the system and anonymous callers
+	ActorRole       Role                `bson:"actor_role,omitempty" json:"actor_role,omitempty"`
+	APIKeyID        *primitive.ObjectID `bson:"api_key_id,omitempty" json:"api_key_id,omitempty"`
+	TargetUserID    *primitive.ObjectID `bson:"target_user_id,omitempty" json:"target_user_id,omitempty"`
+	TargetAccountID *primitive.ObjectID `bson:"target_account_id,omitempty" json:"target_account_id,omitempty"`
+	TransactionID   *primitive.ObjectID `bson:"transaction_id,omitempty" json:"transaction_id,omitempty"`
+	Balances        []BalanceChange     `bson:"balances,omitempty" json:"balances,omitempty"`
+	Details         map[string]string   `bson:"details,omitempty" json:"details,omitempty"`
+	IP              string              `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent       string              `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	RequestID       string              `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// TargetUser returns the user the event is about. Identity events recorded before users existed only
+// name the account, whose ID the user kept.
+func (e *AuditEvent) TargetUser() *primitive.ObjectID {
+	if e.TargetUserID != nil || e.TargetAccountID == nil {
+		return e.TargetUserID
+	}
+	for _, action := range IdentityAuditActions {
+		if e.Action == action {
+			return e.TargetAccountID
+		}
+	}
+	return nil
+}
+
+// BalanceChange is one account balance before and after a money event
+type BalanceChange struct {
+	AccountID  primitive.ObjectID `bson:"account_id" json:"account_id"`
+	Currency   string             `bson:"currency" json:"currency"`
+	Before     money.Amount       `bson:"before" json:"before"` // minor units of Currency
+	After      money.Amount       `bson:"after" json:"after"`
+	HeldBefore money.Amount       `bson:"held_before" json:"held_before"`
+	HeldAfter  money.Amount       `bson:"held_after" json:"held_after"`
+}
+
+// Collection related constants
+const (
+	AuditEventCollection = "audit_events"
+)
+
+// EnsureIndexes creates the required indexes for the AuditEvent collection
+func (e *AuditEvent) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "target_account_id", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"target_account_id": bson.M{"$exists": true},
+			}),
+		},
+		{
+			Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"target_user_id": bson.M{"$exists": true},
+			}),
+		},
+		{
+			Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"actor_id": bson.M{"$exists": true},
+			}),
+		},
+		{
+			Keys: bson.D{{Key: "action", Value: 1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+	}
+
+	col := db.Collection(AuditEventCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", AuditEventCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", AuditEventCollection).Msg("Indexes created successfully")
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,9 +15,53 @@ import (
 type Balance struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	AccountID primitive.ObjectID `bson:"account_id" json:"account_id" validate:"required"`
-	Amount    float64           `bson:"amount" json:"amount"`
-	Currency  string            `bson:"currency" json:"currency" validate:"required,len=3"` // ISO 4217
-	UpdatedAt time.Time         `bson:"updated_at" json:"updated_at"`
+	Amount    money.Amount       `bson:"amount" json:"amount"`                               // minor units of Currency
+	Held      money.Amount       `bson:"held" json:"held"`                                   // reserved by pending holds
+	Currency  string             `bson:"currency" json:"currency" validate:"required,len=3"` // ISO 4217
+	Overdraft *Overdraft         `bson:"overdraft,omitempty" json:"overdraft,omitempty"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Overdraft lets a balance go negative down to -Limit. While it is negative, the overdraft job charges
+// interest and a flat fee once per day.
+type Overdraft struct {
+	Limit           money.Amount `bson:"limit" json:"limit"`                                         // minor units of the balance currency
+	InterestRateBps int          `bson:"interest_rate_bps" json:"interest_rate_bps"`                 // Yearly rate on the overdrawn amount, in basis points
+	DailyFee        money.Amount `bson:"daily_fee" json:"daily_fee"`                                 // Charged for every day the balance is overdrawn
+	ChargedThrough  *time.Time   `bson:"charged_through,omitempty" json:"charged_through,omitempty"` // Start of the last UTC day charged
+}
+
+// Available returns the amount that can be spent once pending holds are reserved, including any
+// unused overdraft
+func (b *Balance) Available() money.Amount {
+	return b.Amount - b.Held + b.OverdraftLimit()
+}
+
+// OverdraftLimit returns how far the balance may go below zero
+func (b *Balance) OverdraftLimit() money.Amount {
+	if b.Overdraft == nil {
+		return 0
+	}
+	return b.Overdraft.Limit
+}
+
+// OverdraftUsed returns how far the balance is below zero
+func (b *Balance) OverdraftUsed() money.Amount {
+	if b.Amount >= 0 {
+		return 0
+	}
+	return -b.Amount
+}
+
+// DailyOverdraftCharge returns the interest and fee for one day at the current overdrawn amount.
+// Interest accrues on an actual/365 basis and is rounded down to a minor unit.
+func (b *Balance) DailyOverdraftCharge() (interest, fee money.Amount) {
+	used := b.OverdraftUsed()
+	if b.Overdraft == nil || used == 0 {
+		return 0, 0
+	}
+	interest = used * money.Amount(b.Overdraft.InterestRateBps) / (10000 * 365)
+	return interest, b.Overdraft.DailyFee
 }
 
 // Collection related constants
@@ -26,16 +71,25 @@ const (
 
 // EnsureIndexes creates the required indexes for the Balance collection
 func (b *Balance) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
-	indexModel := mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "account_id", Value: 1},
-			{Key: "currency", Value: 1},
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "account_id", Value: 1},
+				{Key: "currency", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Finds balances due an overdraft charge
+			Keys: bson.D{{Key: "overdraft.charged_through", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{
+				"overdraft": bson.M{"$exists": true},
+			}),
 		},
-		Options: options.Index().SetUnique(true),
 	}
 
 	col := db.Collection(BalanceCollection)
-	_, err := col.Indexes().CreateOne(ctx, indexModel)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
 	if err != nil {
 		log.Error().Err(err).Str("collection", BalanceCollection).Msg("Failed to create indexes")
 		return err
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CurrencyOverride is an admin change to a currency of the embedded ISO 4217 table. Unset fields keep
+// the table's value.
+type CurrencyOverride struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Code       string              `bson:"code" json:"code"`
+	MinorUnits *int                `bson:"minor_units,omitempty" json:"minor_units,omitempty"`
+	Enabled    *bool               `bson:"enabled,omitempty" json:"enabled,omitempty"`
+	UpdatedBy  *primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Collection related constants
+const (
+	CurrencyOverrideCollection = "currency_overrides"
+)
+
+// EnsureIndexes creates the required indexes for the CurrencyOverride collection
+func (o *CurrencyOverride) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	col := db.Collection(CurrencyOverrideCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", CurrencyOverrideCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", CurrencyOverrideCollection).Msg("Indexes created successfully")
+	return nil
+}
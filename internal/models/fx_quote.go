@@ -0,0 +1,60 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FXQuote locks the price of converting SellAmount of one of an account's balances into another currency.
+// The customer receives BuyAmount; Spread is the difference to the mid-market amount, kept as revenue.
+type FXQuote struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AccountID    primitive.ObjectID  `bson:"account_id" json:"account_id"`
+	UserID       primitive.ObjectID  `bson:"user_id" json:"user_id"` // User who requested the quote; only they may execute it
+	SellCurrency string              `bson:"sell_currency" json:"sell_currency"`
+	BuyCurrency  string              `bson:"buy_currency" json:"buy_currency"`
+	SellAmount   money.Amount        `bson:"sell_amount" json:"sell_amount"` // minor units of SellCurrency
+	BuyAmount    money.Amount        `bson:"buy_amount" json:"buy_amount"`   // minor units of BuyCurrency
+	Spread       money.Amount        `bson:"spread" json:"spread"`           // minor units of BuyCurrency
+	MidRate      string              `bson:"mid_rate" json:"mid_rate"`       // Provider rate, rounded for display
+	Rate         string              `bson:"rate" json:"rate"`               // Rate after the spread, rounded for display
+	ExpiresAt    time.Time           `bson:"expires_at" json:"expires_at"`
+	UsedAt       *time.Time          `bson:"used_at,omitempty" json:"used_at,omitempty"`
+	ConversionID *primitive.ObjectID `bson:"conversion_id,omitempty" json:"conversion_id,omitempty"` // Shared by the transactions that executed the quote
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// Collection related constants
+const (
+	FXQuoteCollection = "fx_quotes"
+
+	// FXQuoteRetention is how long a quote is kept after it expires
+	FXQuoteRetention = 24 * time.Hour
+)
+
+// EnsureIndexes creates the required indexes for the FXQuote collection
+func (q *FXQuote) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(FXQuoteRetention.Seconds())),
+		},
+	}
+
+	col := db.Collection(FXQuoteCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", FXQuoteCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", FXQuoteCollection).Msg("Indexes created successfully")
+	return nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IdempotencyKey records the outcome of a money-moving request sent with an Idempotency-Key header
+type IdempotencyKey struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AccountID     primitive.ObjectID `bson:"account_id" json:"account_id"`
+	Key           string             `bson:"key" json:"key"`
+	Operation     string             `bson:"operation" json:"operation"`
+	RequestHash   string             `bson:"request_hash" json:"-"` // SHA-256 of the canonical request
+	TransactionID primitive.ObjectID `bson:"transaction_id,omitempty" json:"transaction_id"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// Collection related constants
+const (
+	IdempotencyKeyCollection = "idempotency_keys"
+
+	// IdempotencyKeyIndex is the unique index on (account_id, key)
+	IdempotencyKeyIndex = "account_id_1_key_1"
+
+	// IdempotencyKeyTTL is how long a key is remembered before it may be reused
+	IdempotencyKeyTTL = 24 * time.Hour
+)
+
+// EnsureIndexes creates the required indexes for the IdempotencyKey collection
+func (k *IdempotencyKey) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "account_id", Value: 1},
+				{Key: "key", Value: 1},
+			},
+			Options: options.Index().SetName(IdempotencyKeyIndex).SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	col := db.Collection(IdempotencyKeyCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", IdempotencyKeyCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", IdempotencyKeyCollection).Msg("Indexes created successfully")
+	return nil
+}
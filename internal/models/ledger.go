@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// JournalEntry is a balanced set of postings recorded for a single money movement.
+// Customer balances are projections of the postings made against their ledger accounts.
+type JournalEntry struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind        JournalEntryKind   `bson:"kind" json:"kind"`
+	Description string             `bson:"description" json:"description"`
+	Postings    []Posting          `bson:"postings" json:"postings"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Posting is one debit or credit line of a journal entry
+type Posting struct {
+	LedgerAccount string              `bson:"ledger_account" json:"ledger_account"`
+	AccountID     *primitive.ObjectID `bson:"account_id,omitempty" json:"account_id,omitempty"` // Set for customer ledger accounts
+	Direction     PostingDirection    `bson:"direction" json:"direction"`
+	Amount        money.Amount        `bson:"amount" json:"amount"` // minor units of Currency, always positive
+	Currency      string              `bson:"currency" json:"currency"`
+}
+
+type JournalEntryKind string
+
+const (
+	JournalEntryKindDeposit         JournalEntryKind = "deposit"
+	JournalEntryKindWithdrawal      JournalEntryKind = "withdrawal"
+	JournalEntryKindTransfer        JournalEntryKind = "transfer"
+	JournalEntryKindHoldCapture     JournalEntryKind = "hold_capture"
+	JournalEntryKindReversal        JournalEntryKind = "reversal"
+	JournalEntryKindOpeningBalance  JournalEntryKind = "opening_balance"
+	JournalEntryKindFXConversion    JournalEntryKind = "fx_conversion"
+	JournalEntryKindOverdraftCharge JournalEntryKind = "overdraft_charge"
+)
+
+type PostingDirection string
+
+const (
+	PostingDirectionDebit  PostingDirection = "debit"
+	PostingDirectionCredit PostingDirection = "credit"
+)
+
+// System ledger accounts that balance customer postings
+const (
+	LedgerAccountCashIn            = "system:cash-in"
+	LedgerAccountCashOut           = "system:cash-out"
+	LedgerAccountFees              = "system:fees"
+	LedgerAccountOpeningBalance    = "system:opening-balance"
+	LedgerAccountFXPosition        = "system:fx-position"
+	LedgerAccountFXRevenue         = "system:fx-revenue"
+	LedgerAccountOverdraftInterest = "system:overdraft-interest"
+)
+
+// CustomerLedgerAccount returns the ledger account code holding a customer's funds.
+// Customer accounts are liabilities: credits increase the balance and debits reduce it.
+func CustomerLedgerAccount(accountID primitive.ObjectID) string {
+	return fmt.Sprintf("customer:%s", accountID.Hex())
+}
+
+// Collection related constants
+const (
+	JournalEntryCollection = "journal_entries"
+)
+
+// EnsureIndexes creates the required indexes for the JournalEntry collection
+func (j *JournalEntry) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "postings.account_id", Value: 1},
+				{Key: "postings.currency", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "postings.ledger_account", Value: 1}},
+		},
+	}
+
+	col := db.Collection(JournalEntryCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", JournalEntryCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", JournalEntryCollection).Msg("Indexes created successfully")
+	return nil
+}
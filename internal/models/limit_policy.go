@@ -0,0 +1,169 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LimitPolicy caps the money an account of Tier may move in Currency in one Direction. Zero fields
+// are not limited. Daily windows start at UTC midnight and monthly windows on the first of the month.
+type LimitPolicy struct {
+	ID                primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Tier              AccountTier         `bson:"tier" json:"tier"`
+	Currency          string              `bson:"currency" json:"currency"`
+	Direction         LimitDirection      `bson:"direction" json:"direction"`
+	MinPerTransaction money.Amount        `bson:"min_per_transaction" json:"min_per_transaction"` // minor units of Currency
+	MaxPerTransaction money.Amount        `bson:"max_per_transaction" json:"max_per_transaction"` // minor units of Currency
+	DailyCount        int64               `bson:"daily_count" json:"daily_count"`
+	DailyAmount       money.Amount        `bson:"daily_amount" json:"daily_amount"` // minor units of Currency
+	MonthlyCount      int64               `bson:"monthly_count" json:"monthly_count"`
+	MonthlyAmount     money.Amount        `bson:"monthly_amount" json:"monthly_amount"` // minor units of Currency
+	UpdatedBy         *primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt         time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// LimitDirection is the kind of movement a limit policy applies to
+type LimitDirection string
+
+const (
+	// LimitDirectionDeposit covers deposits. Incoming transfers, conversions and reversals are not counted.
+	LimitDirectionDeposit LimitDirection = "deposit"
+	// LimitDirectionWithdrawal covers withdrawals, outgoing transfers and holds. Conversions, reversals and
+	// bank charges are not counted.
+	LimitDirectionWithdrawal LimitDirection = "withdrawal"
+)
+
+// IsLimitDirection reports whether d is a known limit direction
+func IsLimitDirection(d LimitDirection) bool {
+	return d == LimitDirectionDeposit || d == LimitDirectionWithdrawal
+}
+
+// LimitName identifies one limit of a policy
+type LimitName string
+
+const (
+	LimitMinPerTransaction LimitName = "min_per_transaction"
+	LimitMaxPerTransaction LimitName = "max_per_transaction"
+	LimitDailyCount        LimitName = "daily_count"
+	LimitDailyAmount       LimitName = "daily_amount"
+	LimitMonthlyCount      LimitName = "monthly_count"
+	LimitMonthlyAmount     LimitName = "monthly_amount"
+)
+
+// IsCount reports whether the limit caps a number of transactions rather than an amount
+func (n LimitName) IsCount() bool {
+	return n == LimitDailyCount || n == LimitMonthlyCount
+}
+
+// ResetsAt returns when the window of a daily or monthly limit restarts after now, or nil for
+// per-transaction limits
+func (n LimitName) ResetsAt(now time.Time) *time.Time {
+	dayStart, monthStart := LimitWindows(now)
+	var resets time.Time
+	switch n {
+	case LimitDailyCount, LimitDailyAmount:
+		resets = dayStart.AddDate(0, 0, 1)
+	case LimitMonthlyCount, LimitMonthlyAmount:
+		resets = monthStart.AddDate(0, 1, 0)
+	default:
+		return nil
+	}
+	return &resets
+}
+
+// LimitWindows returns the start of the UTC day and month containing now
+func LimitWindows(now time.Time) (dayStart, monthStart time.Time) {
+	now = now.UTC()
+	dayStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return dayStart, monthStart
+}
+
+// LimitBreach describes the first limit a movement would exceed. Allowed and Used are counts for
+// count limits and minor units otherwise; Used is zero for per-transaction limits.
+type LimitBreach struct {
+	Limit   LimitName
+	Allowed int64
+	Used    int64
+}
+
+// HasWindowLimits reports whether the policy has daily or monthly limits, which need the usage history
+func (p *LimitPolicy) HasWindowLimits() bool {
+	return p.DailyCount > 0 || p.DailyAmount > 0 || p.MonthlyCount > 0 || p.MonthlyAmount > 0
+}
+
+// Check returns the first limit that moving amount would exceed given the usage so far, or nil if it
+// is within every limit. usage may be nil when the policy has no window limits.
+func (p *LimitPolicy) Check(usage *LimitUsage, amount money.Amount) *LimitBreach {
+	if amount < p.MinPerTransaction {
+		return &LimitBreach{Limit: LimitMinPerTransaction, Allowed: int64(p.MinPerTransaction)}
+	}
+	if p.MaxPerTransaction > 0 && amount > p.MaxPerTransaction {
+		return &LimitBreach{Limit: LimitMaxPerTransaction, Allowed: int64(p.MaxPerTransaction)}
+	}
+	if usage == nil {
+		usage = &LimitUsage{}
+	}
+
+	windows := []struct {
+		name                LimitName
+		allowed, used, adds int64
+	}{
+		{LimitDailyCount, p.DailyCount, usage.DailyCount, 1},
+		{LimitDailyAmount, int64(p.DailyAmount), int64(usage.DailyAmount), int64(amount)},
+		{LimitMonthlyCount, p.MonthlyCount, usage.MonthlyCount, 1},
+		{LimitMonthlyAmount, int64(p.MonthlyAmount), int64(usage.MonthlyAmount), int64(amount)},
+	}
+	for _, window := range windows {
+		if window.allowed > 0 && window.used+window.adds > window.allowed {
+			return &LimitBreach{Limit: window.name, Allowed: window.allowed, Used: window.used}
+		}
+	}
+
+	return nil
+}
+
+// LimitUsage is an account's history in one currency and direction over the current day and month.
+// Amounts are net of reversals.
+type LimitUsage struct {
+	DailyCount    int64        `bson:"daily_count"`
+	DailyAmount   money.Amount `bson:"daily_amount"`
+	MonthlyCount  int64        `bson:"monthly_count"`
+	MonthlyAmount money.Amount `bson:"monthly_amount"`
+}
+
+// Collection related constants
+const (
+	LimitPolicyCollection = "limit_policies"
+)
+
+// EnsureIndexes creates the required indexes for the LimitPolicy collection
+func (p *LimitPolicy) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tier", Value: 1},
+				{Key: "currency", Value: 1},
+				{Key: "direction", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	col := db.Collection(LimitPolicyCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", LimitPolicyCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", LimitPolicyCollection).Msg("Indexes created successfully")
+	return nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LoginAttempt counts recent failed logins for one email or client IP. A counter is forgotten once
+// ExpiresAt passes without another failure.
+type LoginAttempt struct {
+	Key           string     `bson:"_id" json:"key"` // "email:<address>" or "ip:<address>"
+	Failures      int        `bson:"failures" json:"failures"`
+	LastFailureAt time.Time  `bson:"last_failure_at" json:"last_failure_at"`
+	LockedUntil   *time.Time `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	ExpiresAt     time.Time  `bson:"expires_at" json:"expires_at"`
+}
+
+// Collection related constants
+const (
+	LoginAttemptCollection = "login_attempts"
+)
+
+// EnsureIndexes creates the required indexes for the LoginAttempt collection
+func (a *LoginAttempt) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	col := db.Collection(LoginAttemptCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", LoginAttemptCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", LoginAttemptCollection).Msg("Indexes created successfully")
+	return nil
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Principal is the authenticated caller of a request, loaded from the user its token or API key names
+type Principal struct {
+	UserID    primitive.ObjectID
+	Email     string
+	Status    UserStatus
+	Role      Role
+	TokenID   string
+	SessionID primitive.ObjectID
+	APIKeyID  *primitive.ObjectID // Set when the request was authenticated with an API key
+	Scopes    []Permission        // Permissions of the API key; empty for tokens
+}
+
+// principalContextKey is the context key under which the principal travels to the service layer
+type principalContextKey struct{}
+
+// NewPrincipal builds the principal for a user authenticated by the token with the given ID
+// issued within sessionID
+func NewPrincipal(user *User, tokenID string, sessionID primitive.ObjectID) *Principal {
+	return &Principal{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Status:    user.Status,
+		Role:      RoleOf(user),
+		TokenID:   tokenID,
+		SessionID: sessionID,
+	}
+}
+
+// NewAPIKeyPrincipal builds the principal for a user authenticated by one of their API keys
+func NewAPIKeyPrincipal(user *User, key *APIKey) *Principal {
+	keyID := key.ID
+	return &Principal{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Status:   user.Status,
+		Role:     RoleOf(user),
+		APIKeyID: &keyID,
+		Scopes:   key.Scopes,
+	}
+}
+
+// Allows reports whether the credential the principal authenticated with may be used for permission.
+// Tokens are unrestricted; API keys are limited to their scopes.
+func (p *Principal) Allows(permission Permission) bool {
+	if p.APIKeyID == nil {
+		return true
+	}
+	for _, scope := range p.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, or nil
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
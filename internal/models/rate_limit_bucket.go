@@ -0,0 +1,46 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RateLimitBucket is a token bucket shared by every replica. A bucket is removed once it would have
+// refilled completely, since a missing bucket counts as full.
+type RateLimitBucket struct {
+	Key       string    `bson:"_id" json:"key"` // "<budget>:<client>", e.g. "money:user:<id>"
+	Tokens    float64   `bson:"tokens" json:"tokens"`
+	Allowed   bool      `bson:"allowed" json:"allowed"` // Whether the latest request got a token
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// Collection related constants
+const (
+	RateLimitBucketCollection = "rate_limit_buckets"
+)
+
+// EnsureIndexes creates the required indexes for the RateLimitBucket collection
+func (b *RateLimitBucket) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	col := db.Collection(RateLimitBucketCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", RateLimitBucketCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", RateLimitBucketCollection).Msg("Indexes created successfully")
+	return nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RefreshToken is an opaque, single-use token that can be exchanged for a new access token.
+// Every token issued from one login shares a FamilyID, which access tokens carry as their session ID.
+type RefreshToken struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	FamilyID   primitive.ObjectID  `bson:"family_id" json:"family_id"`
+	TokenHash  string              `bson:"token_hash" json:"-"` // SHA-256 of the opaque token
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	ExpiresAt  time.Time           `bson:"expires_at" json:"expires_at"`
+	UsedAt     *time.Time          `bson:"used_at,omitempty" json:"used_at,omitempty"`
+	ReplacedBy *primitive.ObjectID `bson:"replaced_by,omitempty" json:"replaced_by,omitempty"`
+	RevokedAt  *time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// Collection related constants
+const (
+	RefreshTokenCollection = "refresh_tokens"
+)
+
+// EnsureIndexes creates the required indexes for the RefreshToken collection
+func (t *RefreshToken) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "family_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	col := db.Collection(RefreshTokenCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", RefreshTokenCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", RefreshTokenCollection).Msg("Indexes created successfully")
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import "context"
+
+// RequestInfo describes the HTTP request an operation runs for, so that events can name where they came from
+type RequestInfo struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// requestInfoContextKey is the context key under which the request info travels to the service layer
+type requestInfoContextKey struct{}
+
+// ContextWithRequestInfo returns a copy of ctx carrying info
+func ContextWithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoContextKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info carried by ctx, or the zero value outside of a request
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoContextKey{}).(RequestInfo)
+	return info
+}
@@ -0,0 +1,71 @@
+package models
+
+// Role determines what a user may do beyond acting on the accounts they hold
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleTeller   Role = "teller"
+	RoleAdmin    Role = "admin"
+	RoleAuditor  Role = "auditor"
+)
+
+// Permission is an operation on an account or its transactions
+type Permission string
+
+const (
+	PermissionAccountRead        Permission = "account:read"
+	PermissionFundsDeposit       Permission = "funds:deposit"
+	PermissionFundsWithdraw      Permission = "funds:withdraw"
+	PermissionTransactionReverse Permission = "transaction:reverse"
+	PermissionAccountManage      Permission = "account:manage"
+)
+
+// rolePermissions lists what each role may do on accounts the user does not hold. Holders may perform
+// the operations their holding allows regardless of role.
+var rolePermissions = map[Role][]Permission{
+	RoleCustomer: {},
+	RoleTeller:   {PermissionAccountRead, PermissionFundsDeposit},
+	RoleAuditor:  {PermissionAccountRead},
+	RoleAdmin: {
+		PermissionAccountRead,
+		PermissionFundsDeposit,
+		PermissionFundsWithdraw,
+		PermissionTransactionReverse,
+		PermissionAccountManage,
+	},
+}
+
+// IsValid reports whether p is a known permission
+func (p Permission) IsValid() bool {
+	switch p {
+	case PermissionAccountRead, PermissionFundsDeposit, PermissionFundsWithdraw,
+		PermissionTransactionReverse, PermissionAccountManage:
+		return true
+	}
+	return false
+}
+
+// IsValid reports whether r is a known role
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Has reports whether the role grants permission on accounts the user does not hold
+func (r Role) Has(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleOf returns the user's role, treating users created before roles existed as customers
+func RoleOf(user *User) Role {
+	if user.Role == "" {
+		return RoleCustomer
+	}
+	return user.Role
+}
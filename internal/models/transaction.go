@@ -4,24 +4,39 @@ import (
 	"context"
 	"time"
 
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Transaction struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	AccountID       primitive.ObjectID `bson:"account_id" json:"account_id" validate:"required"`
-	Type            TransactionType    `bson:"type" json:"type" validate:"required"`
-	Amount          float64            `bson:"amount" json:"amount" validate:"required,gt=0"`
-	Currency        string             `bson:"currency" json:"currency" validate:"required,len=3"` // ISO 4217
-	Status          TransactionStatus  `bson:"status" json:"status"`
-	Reference       string             `bson:"reference" json:"reference"`
-	Description     string             `bson:"description" json:"description"`
-	TransactionDate time.Time          `bson:"transaction_date" json:"transaction_date"`
-	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	AccountID       primitive.ObjectID  `bson:"account_id" json:"account_id" validate:"required"`
+	Type            TransactionType     `bson:"type" json:"type" validate:"required"`
+	Amount          money.Amount        `bson:"amount" json:"amount" validate:"required,gt=0"`      // minor units of Currency
+	Currency        string              `bson:"currency" json:"currency" validate:"required,len=3"` // ISO 4217
+	Status          TransactionStatus   `bson:"status" json:"status"`
+	Reference       string              `bson:"reference" json:"reference"`
+	Description     string              `bson:"description" json:"description"`
+	TransferID      *primitive.ObjectID `bson:"transfer_id,omitempty" json:"transfer_id,omitempty"`     // Shared by both legs of a transfer
+	ConversionID    *primitive.ObjectID `bson:"conversion_id,omitempty" json:"conversion_id,omitempty"` // Shared by both legs of a currency conversion
+	JournalEntryID  primitive.ObjectID  `bson:"journal_entry_id,omitempty" json:"journal_entry_id"`
+	HeldAmount      money.Amount        `bson:"held_amount,omitempty" json:"held_amount,omitempty"` // Amount authorized by a hold
+	ExpiresAt       *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`   // When a pending hold is voided automatically
+	ReversalOf      *primitive.ObjectID `bson:"reversal_of,omitempty" json:"reversal_of,omitempty"` // Original transaction this one compensates
+	Charge          bool                `bson:"charge,omitempty" json:"charge,omitempty"`           // Interest or fees taken by the bank rather than moved by the customer
+	ReversedAmount  money.Amount        `bson:"reversed_amount" json:"reversed_amount"`             // Total compensated so far
+	APIKeyID        *primitive.ObjectID `bson:"api_key_id,omitempty" json:"api_key_id,omitempty"`   // API key that created the transaction
+	ChainSequence   int64               `bson:"chain_sequence,omitempty" json:"chain_sequence"`     // Position in the account's hash chain, from 1
+	PreviousHash    string              `bson:"previous_hash,omitempty" json:"previous_hash"`       // Hash of the account's previous transaction
+	Hash            string              `bson:"hash,omitempty" json:"hash"`                         // See ComputeHash
+	ChainVersion    int                 `bson:"chain_version,omitempty" json:"chain_version"`       // Field set covered by Hash
+	TransactionDate time.Time           `bson:"transaction_date" json:"transaction_date"`
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`
 }
 
 type TransactionType string
@@ -57,6 +72,35 @@ func (t *Transaction) EnsureIndexes(ctx context.Context, db *mongo.Database) err
 		{
 			Keys: bson.D{{Key: "status", Value: 1}},
 		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "expires_at", Value: 1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "reversal_of", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "api_key_id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "transfer_id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			// One transaction per chain position, so concurrent writers cannot fork an account's chain
+			Keys: bson.D{
+				{Key: "account_id", Value: 1},
+				{Key: "chain_sequence", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"chain_sequence": bson.M{"$exists": true},
+			}),
+		},
 	}
 
 	col := db.Collection(TransactionCollection)
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Versions of the field set covered by a transaction's hash
+const (
+	// ChainVersionLegacy hashes leave out the status, the captured amount of a hold and the journal entry
+	ChainVersionLegacy = 0
+	// ChainVersionFinalState hashes cover the transaction as it stays. Holds only join the chain once they
+	// are captured or voided, so their final status and amount are covered too.
+	ChainVersionFinalState = 1
+)
+
+// chainFields are the transaction fields covered by its hash, in a fixed order. The amount reversed so
+// far is left out: each reversal appends its own chained transaction, and verification checks that
+// they add up to the recorded amount.
+type chainFields struct {
+	Version         int    `json:"version,omitempty"` // Omitted for legacy hashes so they still verify
+	Sequence        int64  `json:"sequence"`
+	ID              string `json:"id"`
+	AccountID       string `json:"account_id"`
+	Type            string `json:"type"`
+	Status          string `json:"status,omitempty"`
+	Amount          int64  `json:"amount"`
+	HeldAmount      int64  `json:"held_amount,omitempty"`
+	Currency        string `json:"currency"`
+	Description     string `json:"description"`
+	TransferID      string `json:"transfer_id"`
+	ConversionID    string `json:"conversion_id,omitempty"` // Omitted when unset so hashes from before conversions still verify
+	ReversalOf      string `json:"reversal_of"`
+	Charge          bool   `json:"charge,omitempty"` // Omitted when unset so hashes from before charges still verify
+	JournalEntryID  string `json:"journal_entry_id,omitempty"`
+	APIKeyID        string `json:"api_key_id"`
+	TransactionDate int64  `json:"transaction_date"` // Unix milliseconds, the precision Mongo stores
+	PreviousHash    string `json:"previous_hash"`
+}
+
+// ComputeHash returns the hex SHA-256 over the transaction's canonical fields and the hash of the
+// account's previous transaction. Editing a covered field or removing a transaction breaks the chain.
+func (t *Transaction) ComputeHash() string {
+	fields := chainFields{
+		Sequence:        t.ChainSequence,
+		ID:              t.ID.Hex(),
+		AccountID:       t.AccountID.Hex(),
+		Type:            string(t.Type),
+		Amount:          int64(t.Amount),
+		Currency:        t.Currency,
+		Description:     t.Description,
+		Charge:          t.Charge,
+		TransactionDate: t.TransactionDate.UnixMilli(),
+		PreviousHash:    t.PreviousHash,
+	}
+	if t.ChainVersion >= ChainVersionFinalState {
+		fields.Version = t.ChainVersion
+		fields.Status = string(t.Status)
+		fields.HeldAmount = int64(t.HeldAmount)
+		if !t.JournalEntryID.IsZero() {
+			fields.JournalEntryID = t.JournalEntryID.Hex()
+		}
+	} else if t.HeldAmount > 0 {
+		// Legacy holds were chained when authorized, before a capture could lower their amount
+		fields.Amount = int64(t.HeldAmount)
+	}
+	if t.TransferID != nil {
+		fields.TransferID = t.TransferID.Hex()
+	}
+	if t.ConversionID != nil {
+		fields.ConversionID = t.ConversionID.Hex()
+	}
+	if t.ReversalOf != nil {
+		fields.ReversalOf = t.ReversalOf.Hex()
+	}
+	if t.APIKeyID != nil {
+		fields.APIKeyID = t.APIKeyID.Hex()
+	}
+
+	// Marshalling a struct of strings and integers cannot fail
+	canonical, _ := json.Marshal(fields)
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainTo places the transaction after previous in its account's chain, or first if previous is nil,
+// and computes its hash over the field set of its ChainVersion
+func (t *Transaction) ChainTo(previous *Transaction) {
+	t.ChainSequence = 1
+	t.PreviousHash = ""
+	if previous != nil {
+		t.ChainSequence = previous.ChainSequence + 1
+		t.PreviousHash = previous.Hash
+	}
+	t.Hash = t.ComputeHash()
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// User is a login identity: the credentials, role and second factor of a person, who may hold
+// several accounts
+type User struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name          string             `bson:"name" json:"name" validate:"required"`
+	Email         string             `bson:"email" json:"email" validate:"required,email"`
+	EmailVerified bool               `bson:"email_verified" json:"email_verified"` // Set once the owner follows the verification link
+	PhoneNumber   string             `bson:"phone_number" json:"phone_number" validate:"required"`
+	PhoneVerified bool               `bson:"phone_verified" json:"phone_verified"` // Set once the owner follows the link texted to the number
+	Password      string             `bson:"password" json:"-"`                    // Password is never returned in JSON
+	Status        UserStatus         `bson:"status" json:"status"`
+	Role          Role               `bson:"role" json:"role"`
+	MFA           MFA                `bson:"mfa" json:"mfa"`
+	LockedUntil   *time.Time         `bson:"locked_until,omitempty" json:"locked_until,omitempty"` // Logins are refused until then
+	LockoutEvents []LockoutEvent     `bson:"lockout_events,omitempty" json:"lockout_events,omitempty"`
+	StatusHistory []StatusChange     `bson:"status_history,omitempty" json:"status_history,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// MFA holds a user's TOTP second factor. The secret is stored on enrollment and the factor is only
+// enforced at login once a first code was verified.
+type MFA struct {
+	Enabled       bool     `bson:"enabled" json:"enabled"`
+	Secret        string   `bson:"secret,omitempty" json:"-"`
+	LastStep      int64    `bson:"last_step,omitempty" json:"-"`      // Last accepted TOTP step, so a code cannot be replayed
+	RecoveryCodes []string `bson:"recovery_codes,omitempty" json:"-"` // SHA-256 hashes of the unused recovery codes
+	ChallengeID   string   `bson:"challenge_id,omitempty" json:"-"`   // Token ID of the outstanding login challenge, cleared once it is used
+}
+
+// LockoutEvent records a user being locked out after failed logins or unlocked by an admin
+type LockoutEvent struct {
+	Action  LockoutAction       `bson:"action" json:"action"`
+	At      time.Time           `bson:"at" json:"at"`
+	Until   *time.Time          `bson:"until,omitempty" json:"until,omitempty"`       // End of a lockout
+	ActorID *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // Admin who unlocked the user
+	Reason  string              `bson:"reason,omitempty" json:"reason,omitempty"`
+}
+
+type LockoutAction string
+
+const (
+	LockoutActionLocked   LockoutAction = "locked"
+	LockoutActionUnlocked LockoutAction = "unlocked"
+)
+
+// StatusChange records an admin blocking, unblocking or deactivating a user
+type StatusChange struct {
+	From    UserStatus          `bson:"from" json:"from"`
+	To      UserStatus          `bson:"to" json:"to"`
+	At      time.Time           `bson:"at" json:"at"`
+	ActorID *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // Admin who changed the status
+	Reason  string              `bson:"reason" json:"reason"`
+}
+
+// IsLocked reports whether logins are refused at now
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+type UserStatus string
+
+const (
+	UserStatusActive   UserStatus = "active"
+	UserStatusInactive UserStatus = "inactive"
+	UserStatusBlocked  UserStatus = "blocked"
+)
+
+// IsActive reports whether the user may log in and act on accounts. Users created before statuses
+// were enforced have no status and count as active.
+func (s UserStatus) IsActive() bool {
+	return s == UserStatusActive || s == ""
+}
+
+// Collection related constants
+const (
+	UserCollection = "users"
+)
+
+// EnsureIndexes creates the required indexes for the User collection
+func (u *User) EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "phone_number", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "role", Value: 1}},
+		},
+	}
+
+	col := db.Collection(UserCollection)
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Error().Err(err).Str("collection", UserCollection).Msg("Failed to create indexes")
+		return err
+	}
+
+	log.Info().Str("collection", UserCollection).Msg("Indexes created successfully")
+	return nil
+}
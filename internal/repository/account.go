@@ -2,17 +2,24 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
-	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 )
 
 type AccountRepository interface {
-	Create(ctx context.Context, dto *dtos.CreateAccountDTO) (*models.Account, error)
-	FindByEmail(ctx context.Context, email string) (*models.Account, error)
+	Create(ctx context.Context, account *models.Account) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Account, error)
+	ListByHolder(ctx context.Context, userID primitive.ObjectID) ([]models.Account, error)
+	AddHolder(ctx context.Context, id primitive.ObjectID, holder models.AccountHolder) (*models.Account, error)
+	RemoveHolder(ctx context.Context, id, userID primitive.ObjectID) (*models.Account, error)
+	SetTier(ctx context.Context, id primitive.ObjectID, tier models.AccountTier) (*models.Account, error)
 }
 
 type accountRepository struct {
@@ -23,35 +30,111 @@ func NewAccountRepository(db *mongo.Database) AccountRepository {
 	return &accountRepository{db: db}
 }
 
-func (r *accountRepository) Create(ctx context.Context, dto *dtos.CreateAccountDTO) (*models.Account, error) {
-	account := &models.Account{
-		Name:        dto.Name,
-		Email:       dto.Email,
-		PhoneNumber: dto.PhoneNumber,
-		Password:    dto.Password,
-		Status:      models.AccountStatus(dto.Status),
-		CreatedAt:   dto.CreatedAt,
-		UpdatedAt:   dto.UpdatedAt,
+func (r *accountRepository) Create(ctx context.Context, account *models.Account) error {
+	if account.ID.IsZero() {
+		account.ID = primitive.NewObjectID()
 	}
 
-	col := r.db.Collection(models.AccountCollection)
-	_, err := col.InsertOne(ctx, account)
+	collection := r.db.Collection(models.AccountCollection)
+	if _, err := collection.InsertOne(ctx, account); err != nil {
+		return utils.DatabaseError("creating account", err)
+	}
+
+	return nil
+}
+
+func (r *accountRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Account, error) {
+	collection := r.db.Collection(models.AccountCollection)
+
+	account := &models.Account{}
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(account)
 	if err != nil {
-		return nil, err
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting account", err)
 	}
 
 	return account, nil
 }
 
-func (r *accountRepository) FindByEmail(ctx context.Context, email string) (*models.Account, error) {
-	col := r.db.Collection(models.AccountCollection)
+// ListByHolder returns every account the user holds, oldest first
+func (r *accountRepository) ListByHolder(ctx context.Context, userID primitive.ObjectID) ([]models.Account, error) {
+	collection := r.db.Collection(models.AccountCollection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"holders.user_id": userID}, opts)
+	if err != nil {
+		return nil, utils.DatabaseError("listing accounts", err)
+	}
+	defer cursor.Close(ctx)
+
+	accounts := []models.Account{}
+	if err := cursor.All(ctx, &accounts); err != nil {
+		return nil, utils.DatabaseError("listing accounts", err)
+	}
+
+	return accounts, nil
+}
+
+// AddHolder adds a holder and returns the updated account. It returns nil if the account does not exist
+// or the user already holds it.
+func (r *accountRepository) AddHolder(ctx context.Context, id primitive.ObjectID, holder models.AccountHolder) (*models.Account, error) {
+	return r.updateHolders(ctx,
+		bson.M{"_id": id, "holders.user_id": bson.M{"$ne": holder.UserID}},
+		bson.M{"$push": bson.M{"holders": holder}},
+		"adding account holder",
+	)
+}
+
+// RemoveHolder removes a joint holder and returns the updated account. It returns nil if the account does
+// not exist or the user is not one of its joint holders; the owner cannot be removed.
+func (r *accountRepository) RemoveHolder(ctx context.Context, id, userID primitive.ObjectID) (*models.Account, error) {
+	return r.updateHolders(ctx,
+		bson.M{"_id": id, "holders": bson.M{"$elemMatch": bson.M{"user_id": userID, "role": models.HolderRoleJoint}}},
+		bson.M{"$pull": bson.M{"holders": bson.M{"user_id": userID}}},
+		"removing account holder",
+	)
+}
+
+// updateHolders applies update to the account matched by filter and returns the updated account, or nil
+// if nothing matched
+func (r *accountRepository) updateHolders(ctx context.Context, filter, update bson.M, op string) (*models.Account, error) {
+	collection := r.db.Collection(models.AccountCollection)
+
+	update["$set"] = bson.M{"updated_at": time.Now()}
+
 	account := &models.Account{}
-	err := col.FindOne(ctx, bson.M{"email": email}).Decode(account)
+	err := collection.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(account)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
 		}
-		return nil, err
+		return nil, utils.DatabaseError(op, err)
 	}
+
+	return account, nil
+}
+
+// SetTier changes the tier whose limit policies apply to the account. It returns nil if the account
+// does not exist.
+func (r *accountRepository) SetTier(ctx context.Context, id primitive.ObjectID, tier models.AccountTier) (*models.Account, error) {
+	collection := r.db.Collection(models.AccountCollection)
+
+	account := &models.Account{}
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"tier": tier, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(account)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("setting account tier", err)
+	}
+
 	return account, nil
 }
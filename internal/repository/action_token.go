@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type ActionTokenRepository interface {
+	Create(ctx context.Context, token *models.ActionToken) error
+	FindByHash(ctx context.Context, purpose models.ActionPurpose, tokenHash string) (*models.ActionToken, error)
+	MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error)
+	InvalidateAll(ctx context.Context, userID primitive.ObjectID, purpose models.ActionPurpose) error
+}
+
+type actionTokenRepository struct {
+	db *mongo.Database
+}
+
+func NewActionTokenRepository(db *mongo.Database) ActionTokenRepository {
+	return &actionTokenRepository{db: db}
+}
+
+func (r *actionTokenRepository) Create(ctx context.Context, token *models.ActionToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	collection := r.db.Collection(models.ActionTokenCollection)
+	if _, err := collection.InsertOne(ctx, token); err != nil {
+		return utils.DatabaseError("creating action token", err)
+	}
+
+	return nil
+}
+
+func (r *actionTokenRepository) FindByHash(ctx context.Context, purpose models.ActionPurpose, tokenHash string) (*models.ActionToken, error) {
+	collection := r.db.Collection(models.ActionTokenCollection)
+
+	token := &models.ActionToken{}
+	err := collection.FindOne(ctx, bson.M{"token_hash": tokenHash, "purpose": purpose}).Decode(token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting action token", err)
+	}
+
+	return token, nil
+}
+
+// MarkUsed redeems the token. It only matches an unused token, so of two concurrent redemptions
+// exactly one wins.
+func (r *actionTokenRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := r.db.Collection(models.ActionTokenCollection)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "used_at": nil},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return false, utils.DatabaseError("using action token", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// InvalidateAll marks every outstanding token of the user for purpose as used, so only the newest
+// token mailed can be redeemed
+func (r *actionTokenRepository) InvalidateAll(ctx context.Context, userID primitive.ObjectID, purpose models.ActionPurpose) error {
+	collection := r.db.Collection(models.ActionTokenCollection)
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "purpose": purpose, "used_at": nil},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return utils.DatabaseError("invalidating action tokens", err)
+	}
+
+	return nil
+}
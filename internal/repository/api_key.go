@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	FindByID(ctx context.Context, userID, id primitive.ObjectID) (*models.APIKey, error)
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]models.APIKey, error)
+	Revoke(ctx context.Context, userID, id primitive.ObjectID) (bool, error)
+	TouchLastUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *mongo.Database
+}
+
+func NewAPIKeyRepository(db *mongo.Database) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+
+	collection := r.db.Collection(models.APIKeyCollection)
+	if _, err := collection.InsertOne(ctx, key); err != nil {
+		return utils.DatabaseError("creating API key", err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	return r.findOne(ctx, bson.M{"key_hash": keyHash})
+}
+
+// FindByID returns the key only if it belongs to userID
+func (r *apiKeyRepository) FindByID(ctx context.Context, userID, id primitive.ObjectID) (*models.APIKey, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "user_id": userID})
+}
+
+func (r *apiKeyRepository) findOne(ctx context.Context, filter bson.M) (*models.APIKey, error) {
+	collection := r.db.Collection(models.APIKeyCollection)
+
+	key := &models.APIKey{}
+	err := collection.FindOne(ctx, filter).Decode(key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting API key", err)
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]models.APIKey, error) {
+	collection := r.db.Collection(models.APIKeyCollection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, utils.DatabaseError("listing API keys", err)
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, utils.DatabaseError("listing API keys", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks an active key of userID as revoked. It reports false when there was no such key.
+func (r *apiKeyRepository) Revoke(ctx context.Context, userID, id primitive.ObjectID) (bool, error) {
+	collection := r.db.Collection(models.APIKeyCollection)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return false, utils.DatabaseError("revoking API key", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	collection := r.db.Collection(models.APIKeyCollection)
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": usedAt}})
+	if err != nil {
+		return utils.DatabaseError("updating API key", err)
+	}
+
+	return nil
+}
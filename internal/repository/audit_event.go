@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// AuditEventRepository appends to and reads the audit log. It deliberately offers no way to change
+// or remove an event.
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+	List(ctx context.Context, filter dtos.AuditEventFilter) ([]models.AuditEvent, error)
+}
+
+type auditEventRepository struct {
+	db *mongo.Database
+}
+
+func NewAuditEventRepository(db *mongo.Database) AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+// Create appends an event. Pass the session context of a Mongo transaction to record the event
+// atomically with the change it describes.
+func (r *auditEventRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	collection := r.db.Collection(models.AuditEventCollection)
+
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if _, err := collection.InsertOne(ctx, event); err != nil {
+		return utils.DatabaseError("recording audit event", err)
+	}
+
+	return nil
+}
+
+// List returns events newest first, starting before filter.Before
+func (r *auditEventRepository) List(ctx context.Context, filter dtos.AuditEventFilter) ([]models.AuditEvent, error) {
+	collection := r.db.Collection(models.AuditEventCollection)
+
+	query := bson.M{}
+	if filter.ActorID != nil {
+		query["actor_id"] = *filter.ActorID
+	}
+	if filter.TargetUserID != nil {
+		// Identity events from before users existed name the user by the account ID it kept
+		query["$or"] = bson.A{
+			bson.M{"target_user_id": *filter.TargetUserID},
+			bson.M{
+				"target_user_id":    bson.M{"$exists": false},
+				"target_account_id": *filter.TargetUserID,
+				"action":            bson.M{"$in": models.IdentityAuditActions},
+			},
+		}
+	}
+	if filter.TargetAccountID != nil {
+		query["target_account_id"] = *filter.TargetAccountID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.From != nil || filter.To != nil {
+		createdAt := bson.M{}
+		if filter.From != nil {
+			createdAt["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			createdAt["$lte"] = *filter.To
+		}
+		query["created_at"] = createdAt
+	}
+	if filter.Before != nil {
+		query["_id"] = bson.M{"$lt": *filter.Before}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(filter.Limit))
+
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, utils.DatabaseError("listing audit events", err)
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, utils.DatabaseError("listing audit events", err)
+	}
+
+	return events, nil
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,8 +16,15 @@ import (
 
 type BalanceRepository interface {
 	GetBalances(ctx context.Context, accountID primitive.ObjectID) ([]models.Balance, error)
-	UpdateBalance(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) error
-	CheckAndDeductBalance(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) error
+	GetBalance(ctx context.Context, accountID primitive.ObjectID, currency string) (*models.Balance, error)
+	UpdateBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error
+	CheckAndDeductBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error
+	SetBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error
+	PlaceHold(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error
+	ReleaseHold(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error
+	SetOverdraft(ctx context.Context, accountID primitive.ObjectID, currency string, overdraft models.Overdraft) (*models.Balance, error)
+	FindOverdraftsDue(ctx context.Context, dayStart time.Time, skip []primitive.ObjectID, limit int) ([]models.Balance, error)
+	MarkOverdraftCharged(ctx context.Context, id primitive.ObjectID, chargedThrough *time.Time, dayStart time.Time) (bool, error)
 }
 
 type balanceRepository struct {
@@ -49,7 +58,26 @@ func (r *balanceRepository) GetBalances(ctx context.Context, accountID primitive
 	return balances, nil
 }
 
-func (r *balanceRepository) UpdateBalance(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) error {
+func (r *balanceRepository) GetBalance(ctx context.Context, accountID primitive.ObjectID, currency string) (*models.Balance, error) {
+	collection := r.db.Collection(models.BalanceCollection)
+
+	filter := bson.M{
+		"account_id": accountID,
+		"currency":   currency,
+	}
+
+	balance := &models.Balance{}
+	if err := collection.FindOne(ctx, filter).Decode(balance); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting balance", err)
+	}
+
+	return balance, nil
+}
+
+func (r *balanceRepository) UpdateBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
 	collection := r.db.Collection(models.BalanceCollection)
 
 	filter := bson.M{
@@ -58,7 +86,7 @@ func (r *balanceRepository) UpdateBalance(ctx context.Context, accountID primiti
 	}
 
 	update := bson.M{
-		"$inc": bson.M{"amount": amount},
+		"$inc": bson.M{"amount": int64(amount)},
 		"$set": bson.M{"updated_at": time.Now()},
 		"$setOnInsert": bson.M{
 			"created_at": time.Now(),
@@ -75,16 +103,16 @@ func (r *balanceRepository) UpdateBalance(ctx context.Context, accountID primiti
 	return nil
 }
 
-func (r *balanceRepository) CheckAndDeductBalance(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) error {
+func (r *balanceRepository) CheckAndDeductBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
 	collection := r.db.Collection(models.BalanceCollection)
 
 	filter := bson.M{
 		"account_id": accountID,
 		"currency":   currency,
-		"amount":     bson.M{"$gte": amount},
+		"$expr":      availableAtLeast(amount),
 	}
 	update := bson.M{
-		"$inc": bson.M{"amount": -amount},
+		"$inc": bson.M{"amount": -int64(amount)},
 		"$set": bson.M{"updated_at": time.Now()},
 	}
 
@@ -97,3 +125,169 @@ func (r *balanceRepository) CheckAndDeductBalance(ctx context.Context, accountID
 	}
 	return nil
 }
+
+// SetBalance overwrites the projected amount, used when rebuilding balances from ledger postings
+func (r *balanceRepository) SetBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
+	collection := r.db.Collection(models.BalanceCollection)
+
+	filter := bson.M{
+		"account_id": accountID,
+		"currency":   currency,
+	}
+	update := bson.M{
+		"$set": bson.M{"amount": int64(amount), "updated_at": time.Now()},
+	}
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return utils.DatabaseError("setting balance", err)
+	}
+
+	return nil
+}
+
+// PlaceHold reserves amount from the available balance without changing the ledger balance
+func (r *balanceRepository) PlaceHold(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
+	collection := r.db.Collection(models.BalanceCollection)
+
+	filter := bson.M{
+		"account_id": accountID,
+		"currency":   currency,
+		"$expr":      availableAtLeast(amount),
+	}
+	update := bson.M{
+		"$inc": bson.M{"held": int64(amount)},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return utils.DatabaseError("placing hold", err)
+	}
+	if result.MatchedCount == 0 {
+		return utils.ErrInsufficientBalance
+	}
+	return nil
+}
+
+// ReleaseHold returns previously reserved funds to the available balance
+func (r *balanceRepository) ReleaseHold(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
+	collection := r.db.Collection(models.BalanceCollection)
+
+	filter := bson.M{
+		"account_id": accountID,
+		"currency":   currency,
+		"held":       bson.M{"$gte": int64(amount)},
+	}
+	update := bson.M{
+		"$inc": bson.M{"held": -int64(amount)},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return utils.DatabaseError("releasing hold", err)
+	}
+	if result.MatchedCount == 0 {
+		return utils.NewError(http.StatusInternalServerError, "held balance is lower than the hold being released")
+	}
+	return nil
+}
+
+// availableAtLeast matches balances whose amount minus held funds, plus any overdraft limit, covers the
+// requested amount
+func availableAtLeast(amount money.Amount) bson.M {
+	return bson.M{"$gte": bson.A{
+		bson.M{"$add": bson.A{
+			bson.M{"$subtract": bson.A{"$amount", bson.M{"$ifNull": bson.A{"$held", 0}}}},
+			bson.M{"$ifNull": bson.A{"$overdraft.limit", 0}},
+		}},
+		int64(amount),
+	}}
+}
+
+// SetOverdraft sets the overdraft terms of a balance, opening the balance if needed. The day charges
+// were last made through is kept.
+func (r *balanceRepository) SetOverdraft(ctx context.Context, accountID primitive.ObjectID, currency string, overdraft models.Overdraft) (*models.Balance, error) {
+	collection := r.db.Collection(models.BalanceCollection)
+
+	filter := bson.M{
+		"account_id": accountID,
+		"currency":   currency,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"overdraft.limit":             int64(overdraft.Limit),
+			"overdraft.interest_rate_bps": overdraft.InterestRateBps,
+			"overdraft.daily_fee":         int64(overdraft.DailyFee),
+			"updated_at":                  time.Now(),
+		},
+		"$setOnInsert": bson.M{"amount": int64(0), "held": int64(0)},
+	}
+
+	balance := &models.Balance{}
+	err := collection.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(balance)
+	if err != nil {
+		return nil, utils.DatabaseError("setting overdraft", err)
+	}
+
+	return balance, nil
+}
+
+// FindOverdraftsDue returns up to limit balances with overdraft terms that have not been charged through
+// the day starting at dayStart, whether or not they are overdrawn now, leaving out the balances in skip
+func (r *balanceRepository) FindOverdraftsDue(ctx context.Context, dayStart time.Time, skip []primitive.ObjectID, limit int) ([]models.Balance, error) {
+	collection := r.db.Collection(models.BalanceCollection)
+
+	filter := bson.M{
+		"overdraft": bson.M{"$exists": true},
+		"$or": bson.A{
+			bson.M{"overdraft.charged_through": bson.M{"$exists": false}},
+			bson.M{"overdraft.charged_through": bson.M{"$lt": dayStart}},
+		},
+	}
+	if len(skip) > 0 {
+		filter["_id"] = bson.M{"$nin": skip}
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, utils.DatabaseError("finding overdrafts due", err)
+	}
+	defer cursor.Close(ctx)
+
+	balances := []models.Balance{}
+	if err := cursor.All(ctx, &balances); err != nil {
+		return nil, utils.DatabaseError("decoding overdrafts due", err)
+	}
+
+	return balances, nil
+}
+
+// MarkOverdraftCharged advances the day the balance was charged through from chargedThrough to the day
+// starting at dayStart. It only matches a balance still charged through chargedThrough, nil meaning never
+// charged, so each day is charged once and in order even across replicas.
+func (r *balanceRepository) MarkOverdraftCharged(ctx context.Context, id primitive.ObjectID, chargedThrough *time.Time, dayStart time.Time) (bool, error) {
+	collection := r.db.Collection(models.BalanceCollection)
+
+	filter := bson.M{
+		"_id":                       id,
+		"overdraft":                 bson.M{"$exists": true},
+		"overdraft.charged_through": bson.M{"$exists": false},
+	}
+	if chargedThrough != nil {
+		filter["overdraft.charged_through"] = *chargedThrough
+	}
+	update := bson.M{
+		"$set": bson.M{"overdraft.charged_through": dayStart, "updated_at": time.Now()},
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, utils.DatabaseError("marking overdraft charged", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type CurrencyRepository interface {
+	ListOverrides(ctx context.Context) ([]models.CurrencyOverride, error)
+	UpsertOverride(ctx context.Context, override *models.CurrencyOverride) (*models.CurrencyOverride, error)
+	DeleteOverride(ctx context.Context, code string) (bool, error)
+	IsInUse(ctx context.Context, code string) (bool, error)
+}
+
+type currencyRepository struct {
+	db *mongo.Database
+}
+
+func NewCurrencyRepository(db *mongo.Database) CurrencyRepository {
+	return &currencyRepository{db: db}
+}
+
+func (r *currencyRepository) ListOverrides(ctx context.Context) ([]models.CurrencyOverride, error) {
+	collection := r.db.Collection(models.CurrencyOverrideCollection)
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, utils.DatabaseError("listing currency overrides", err)
+	}
+	defer cursor.Close(ctx)
+
+	overrides := []models.CurrencyOverride{}
+	if err := cursor.All(ctx, &overrides); err != nil {
+		return nil, utils.DatabaseError("decoding currency overrides", err)
+	}
+
+	return overrides, nil
+}
+
+// UpsertOverride sets the fields of override that are not nil on the currency's override, creating it
+// if needed, and returns the result
+func (r *currencyRepository) UpsertOverride(ctx context.Context, override *models.CurrencyOverride) (*models.CurrencyOverride, error) {
+	collection := r.db.Collection(models.CurrencyOverrideCollection)
+
+	set := bson.M{"updated_at": time.Now()}
+	if override.MinorUnits != nil {
+		set["minor_units"] = *override.MinorUnits
+	}
+	if override.Enabled != nil {
+		set["enabled"] = *override.Enabled
+	}
+	if override.UpdatedBy != nil {
+		set["updated_by"] = *override.UpdatedBy
+	}
+
+	updated := &models.CurrencyOverride{}
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"code": override.Code},
+		bson.M{"$set": set, "$setOnInsert": bson.M{"_id": primitive.NewObjectID()}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(updated)
+	if err != nil {
+		return nil, utils.DatabaseError("updating currency override", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteOverride restores the ISO 4217 values of a currency and reports whether it had an override
+func (r *currencyRepository) DeleteOverride(ctx context.Context, code string) (bool, error) {
+	collection := r.db.Collection(models.CurrencyOverrideCollection)
+
+	result, err := collection.DeleteOne(ctx, bson.M{"code": code})
+	if err != nil {
+		return false, utils.DatabaseError("deleting currency override", err)
+	}
+
+	return result.DeletedCount == 1, nil
+}
+
+// currencyFields lists, per collection, the fields naming the currency of amounts stored in its minor
+// units. Overdraft terms and holds are kept on balances and transactions, and audit events snapshot
+// balances in minor units.
+var currencyFields = []struct {
+	collection string
+	fields     []string
+}{
+	{models.BalanceCollection, []string{"currency"}},
+	{models.TransactionCollection, []string{"currency"}},
+	{models.JournalEntryCollection, []string{"postings.currency"}},
+	{models.FXQuoteCollection, []string{"sell_currency", "buy_currency"}},
+	{models.LimitPolicyCollection, []string{"currency"}},
+	{models.AuditEventCollection, []string{"balances.currency"}},
+}
+
+// IsInUse reports whether any stored amount is in the minor units of the currency
+func (r *currencyRepository) IsInUse(ctx context.Context, code string) (bool, error) {
+	for _, c := range currencyFields {
+		filter := bson.A{}
+		for _, field := range c.fields {
+			filter = append(filter, bson.M{field: code})
+		}
+
+		err := r.db.Collection(c.collection).FindOne(ctx, bson.M{"$or": filter}, options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return false, utils.DatabaseError("checking currency use", err)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
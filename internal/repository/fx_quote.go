@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type FXQuoteRepository interface {
+	Create(ctx context.Context, quote *models.FXQuote) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.FXQuote, error)
+	MarkUsed(ctx context.Context, id, conversionID primitive.ObjectID) (bool, error)
+}
+
+type fxQuoteRepository struct {
+	db *mongo.Database
+}
+
+func NewFXQuoteRepository(db *mongo.Database) FXQuoteRepository {
+	return &fxQuoteRepository{db: db}
+}
+
+func (r *fxQuoteRepository) Create(ctx context.Context, quote *models.FXQuote) error {
+	if quote.ID.IsZero() {
+		quote.ID = primitive.NewObjectID()
+	}
+
+	collection := r.db.Collection(models.FXQuoteCollection)
+	if _, err := collection.InsertOne(ctx, quote); err != nil {
+		return utils.DatabaseError("creating FX quote", err)
+	}
+
+	return nil
+}
+
+func (r *fxQuoteRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.FXQuote, error) {
+	collection := r.db.Collection(models.FXQuoteCollection)
+
+	quote := &models.FXQuote{}
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(quote); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting FX quote", err)
+	}
+
+	return quote, nil
+}
+
+// MarkUsed executes the quote under conversionID. It only matches an unused quote that has not expired,
+// so of two concurrent conversions exactly one wins.
+func (r *fxQuoteRepository) MarkUsed(ctx context.Context, id, conversionID primitive.ObjectID) (bool, error) {
+	collection := r.db.Collection(models.FXQuoteCollection)
+
+	now := time.Now()
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "used_at": nil, "expires_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"used_at": now, "conversion_id": conversionID}},
+	)
+	if err != nil {
+		return false, utils.DatabaseError("using FX quote", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type IdempotencyRepository interface {
+	Find(ctx context.Context, accountID primitive.ObjectID, key string) (*models.IdempotencyKey, error)
+	Create(ctx context.Context, record *models.IdempotencyKey) error
+	SetTransaction(ctx context.Context, id, transactionID primitive.ObjectID) error
+}
+
+type idempotencyRepository struct {
+	db *mongo.Database
+}
+
+func NewIdempotencyRepository(db *mongo.Database) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+func (r *idempotencyRepository) Find(ctx context.Context, accountID primitive.ObjectID, key string) (*models.IdempotencyKey, error) {
+	collection := r.db.Collection(models.IdempotencyKeyCollection)
+
+	record := &models.IdempotencyKey{}
+	err := collection.FindOne(ctx, bson.M{"account_id": accountID, "key": key}).Decode(record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting idempotency key", err)
+	}
+
+	return record, nil
+}
+
+// Create inserts the key record. Inside a transaction a concurrent insert of the same key
+// fails with a write conflict, which serializes duplicate requests.
+func (r *idempotencyRepository) Create(ctx context.Context, record *models.IdempotencyKey) error {
+	if record.ID.IsZero() {
+		record.ID = primitive.NewObjectID()
+	}
+
+	collection := r.db.Collection(models.IdempotencyKeyCollection)
+	_, err := collection.InsertOne(ctx, record)
+	return err
+}
+
+func (r *idempotencyRepository) SetTransaction(ctx context.Context, id, transactionID primitive.ObjectID) error {
+	collection := r.db.Collection(models.IdempotencyKeyCollection)
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"transaction_id": transactionID}},
+	)
+	if err != nil {
+		return utils.DatabaseError("updating idempotency key", err)
+	}
+
+	return nil
+}
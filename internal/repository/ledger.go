@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type LedgerRepository interface {
+	CreateEntry(ctx context.Context, entry *models.JournalEntry) error
+	FindEntry(ctx context.Context, id primitive.ObjectID) (*models.JournalEntry, error)
+	SumCustomerPostings(ctx context.Context, accountID primitive.ObjectID) (map[string]money.Amount, error)
+}
+
+type ledgerRepository struct {
+	db *mongo.Database
+}
+
+func NewLedgerRepository(db *mongo.Database) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+func (r *ledgerRepository) CreateEntry(ctx context.Context, entry *models.JournalEntry) error {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	collection := r.db.Collection(models.JournalEntryCollection)
+	if _, err := collection.InsertOne(ctx, entry); err != nil {
+		return utils.DatabaseError("creating journal entry", err)
+	}
+
+	return nil
+}
+
+func (r *ledgerRepository) FindEntry(ctx context.Context, id primitive.ObjectID) (*models.JournalEntry, error) {
+	collection := r.db.Collection(models.JournalEntryCollection)
+
+	entry := &models.JournalEntry{}
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(entry); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting journal entry", err)
+	}
+
+	return entry, nil
+}
+
+// SumCustomerPostings returns the net of credits minus debits per currency for a customer's ledger account
+func (r *ledgerRepository) SumCustomerPostings(ctx context.Context, accountID primitive.ObjectID) (map[string]money.Amount, error) {
+	collection := r.db.Collection(models.JournalEntryCollection)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"postings.account_id": accountID}}},
+		{{Key: "$unwind", Value: "$postings"}},
+		{{Key: "$match", Value: bson.M{"postings.account_id": accountID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$postings.currency",
+			"net": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$postings.direction", models.PostingDirectionCredit}},
+				"$postings.amount",
+				bson.M{"$multiply": bson.A{"$postings.amount", -1}},
+			}}},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, utils.DatabaseError("summing postings", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Currency string `bson:"_id"`
+		Net      int64  `bson:"net"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, utils.DatabaseError("decoding postings", err)
+	}
+
+	sums := make(map[string]money.Amount, len(rows))
+	for _, row := range rows {
+		sums[row.Currency] = money.Amount(row.Net)
+	}
+
+	return sums, nil
+}
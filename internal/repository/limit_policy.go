@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type LimitPolicyRepository interface {
+	List(ctx context.Context) ([]models.LimitPolicy, error)
+	Find(ctx context.Context, tier models.AccountTier, currency string, direction models.LimitDirection) (*models.LimitPolicy, error)
+	Upsert(ctx context.Context, policy *models.LimitPolicy) (*models.LimitPolicy, error)
+	Delete(ctx context.Context, tier models.AccountTier, currency string, direction models.LimitDirection) (bool, error)
+}
+
+type limitPolicyRepository struct {
+	db *mongo.Database
+}
+
+func NewLimitPolicyRepository(db *mongo.Database) LimitPolicyRepository {
+	return &limitPolicyRepository{db: db}
+}
+
+func (r *limitPolicyRepository) List(ctx context.Context) ([]models.LimitPolicy, error) {
+	collection := r.db.Collection(models.LimitPolicyCollection)
+
+	opts := options.Find().SetSort(bson.D{
+		{Key: "tier", Value: 1},
+		{Key: "currency", Value: 1},
+		{Key: "direction", Value: 1},
+	})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, utils.DatabaseError("listing limit policies", err)
+	}
+	defer cursor.Close(ctx)
+
+	policies := []models.LimitPolicy{}
+	if err := cursor.All(ctx, &policies); err != nil {
+		return nil, utils.DatabaseError("decoding limit policies", err)
+	}
+
+	return policies, nil
+}
+
+// Find returns the policy for tier, currency and direction, or nil if movements there are not limited
+func (r *limitPolicyRepository) Find(ctx context.Context, tier models.AccountTier, currency string, direction models.LimitDirection) (*models.LimitPolicy, error) {
+	collection := r.db.Collection(models.LimitPolicyCollection)
+
+	policy := &models.LimitPolicy{}
+	err := collection.FindOne(ctx, limitPolicyKey(tier, currency, direction)).Decode(policy)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("finding limit policy", err)
+	}
+
+	return policy, nil
+}
+
+// Upsert replaces the limits of the policy's tier, currency and direction, creating the policy if
+// needed, and returns the result
+func (r *limitPolicyRepository) Upsert(ctx context.Context, policy *models.LimitPolicy) (*models.LimitPolicy, error) {
+	collection := r.db.Collection(models.LimitPolicyCollection)
+
+	set := bson.M{
+		"min_per_transaction": policy.MinPerTransaction,
+		"max_per_transaction": policy.MaxPerTransaction,
+		"daily_count":         policy.DailyCount,
+		"daily_amount":        policy.DailyAmount,
+		"monthly_count":       policy.MonthlyCount,
+		"monthly_amount":      policy.MonthlyAmount,
+		"updated_at":          time.Now(),
+	}
+	if policy.UpdatedBy != nil {
+		set["updated_by"] = *policy.UpdatedBy
+	}
+
+	updated := &models.LimitPolicy{}
+	err := collection.FindOneAndUpdate(ctx,
+		limitPolicyKey(policy.Tier, policy.Currency, policy.Direction),
+		bson.M{"$set": set, "$setOnInsert": bson.M{"_id": primitive.NewObjectID()}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(updated)
+	if err != nil {
+		return nil, utils.DatabaseError("updating limit policy", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes the limits of tier, currency and direction and reports whether there were any
+func (r *limitPolicyRepository) Delete(ctx context.Context, tier models.AccountTier, currency string, direction models.LimitDirection) (bool, error) {
+	collection := r.db.Collection(models.LimitPolicyCollection)
+
+	result, err := collection.DeleteOne(ctx, limitPolicyKey(tier, currency, direction))
+	if err != nil {
+		return false, utils.DatabaseError("deleting limit policy", err)
+	}
+
+	return result.DeletedCount == 1, nil
+}
+
+func limitPolicyKey(tier models.AccountTier, currency string, direction models.LimitDirection) bson.M {
+	return bson.M{"tier": tier, "currency": currency, "direction": direction}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error)
+	RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type loginAttemptRepository struct {
+	db *mongo.Database
+}
+
+func NewLoginAttemptRepository(db *mongo.Database) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Get returns the live counter for key, or nil if there is none. Expired counters the TTL monitor has
+// not removed yet are ignored.
+func (r *loginAttemptRepository) Get(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error) {
+	collection := r.db.Collection(models.LoginAttemptCollection)
+
+	attempt := &models.LoginAttempt{}
+	err := collection.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": now}}).Decode(attempt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting login attempts", err)
+	}
+
+	return attempt, nil
+}
+
+// RecordFailure counts a failed login for key and returns the updated counter. The counter starts over
+// when the previous one expired, and lives for window after the latest failure.
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	collection := r.db.Collection(models.LoginAttemptCollection)
+
+	attempt := &models.LoginAttempt{}
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": key, "expires_at": bson.M{"$gt": now}},
+		bson.M{
+			"$inc": bson.M{"failures": 1},
+			"$set": bson.M{"last_failure_at": now},
+			"$max": bson.M{"expires_at": now.Add(window)},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(attempt)
+	if err == nil {
+		return attempt, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, utils.DatabaseError("recording login failure", err)
+	}
+
+	attempt = &models.LoginAttempt{
+		Key:           key,
+		Failures:      1,
+		LastFailureAt: now,
+		ExpiresAt:     now.Add(window),
+	}
+	if _, err := collection.ReplaceOne(ctx, bson.M{"_id": key}, attempt, options.Replace().SetUpsert(true)); err != nil {
+		return nil, utils.DatabaseError("recording login failure", err)
+	}
+
+	return attempt, nil
+}
+
+// Lock blocks logins for key until the given time. The counter is kept at least that long.
+func (r *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	collection := r.db.Collection(models.LoginAttemptCollection)
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": key},
+		bson.M{
+			"$set": bson.M{"locked_until": until},
+			"$max": bson.M{"expires_at": until},
+		},
+	)
+	if err != nil {
+		return utils.DatabaseError("locking logins", err)
+	}
+
+	return nil
+}
+
+// Reset forgets the failures counted for key
+func (r *loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	collection := r.db.Collection(models.LoginAttemptCollection)
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return utils.DatabaseError("resetting login attempts", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/ratelimit"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type rateLimitRepository struct {
+	db *mongo.Database
+}
+
+// NewRateLimitRepository creates a rate limit store that keeps its buckets in MongoDB, so limits hold
+// across replicas
+func NewRateLimitRepository(db *mongo.Database) ratelimit.Store {
+	return &rateLimitRepository{db: db}
+}
+
+// Take refills the bucket for key and draws a token from it in a single pipeline update, so concurrent
+// requests on any replica cannot spend the same token
+func (r *rateLimitRepository) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	collection := r.db.Collection(models.RateLimitBucketCollection)
+
+	burst := float64(limit.Burst)
+	interval := float64(limit.Interval().Milliseconds())
+	update := mongo.Pipeline{
+		// Refill for the milliseconds since the last request; a new bucket starts full
+		{{Key: "$set", Value: bson.M{
+			"tokens": bson.M{"$min": bson.A{burst, bson.M{"$add": bson.A{
+				bson.M{"$ifNull": bson.A{"$tokens", burst}},
+				bson.M{"$divide": bson.A{
+					bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{now, bson.M{"$ifNull": bson.A{"$updated_at", now}}}}}},
+					interval,
+				}},
+			}}}},
+			"updated_at": now,
+		}}},
+		{{Key: "$set", Value: bson.M{"allowed": bson.M{"$gte": bson.A{"$tokens", 1}}}}},
+		{{Key: "$set", Value: bson.M{
+			"tokens":     bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"expires_at": now.Add(limit.Period),
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	bucket := &models.RateLimitBucket{}
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(bucket)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request created the bucket at the same time; it exists now
+		err = collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(bucket)
+	}
+	if err != nil {
+		return ratelimit.Result{}, utils.DatabaseError("taking a rate limit token", err)
+	}
+
+	return ratelimit.NewResult(limit, bucket.Tokens, bucket.Allowed), nil
+}
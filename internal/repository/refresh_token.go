@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	MarkUsed(ctx context.Context, id, replacedBy primitive.ObjectID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID primitive.ObjectID) error
+	RevokeUser(ctx context.Context, userID primitive.ObjectID) error
+	RevokeOtherSessions(ctx context.Context, userID, keepFamilyID primitive.ObjectID) error
+	IsFamilyRevoked(ctx context.Context, familyID primitive.ObjectID) (bool, error)
+}
+
+type refreshTokenRepository struct {
+	db *mongo.Database
+}
+
+func NewRefreshTokenRepository(db *mongo.Database) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	collection := r.db.Collection(models.RefreshTokenCollection)
+	if _, err := collection.InsertOne(ctx, token); err != nil {
+		return utils.DatabaseError("creating refresh token", err)
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	collection := r.db.Collection(models.RefreshTokenCollection)
+
+	token := &models.RefreshToken{}
+	err := collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting refresh token", err)
+	}
+
+	return token, nil
+}
+
+// MarkUsed records that the token was rotated into replacedBy. It only matches a token that is
+// neither used nor revoked, so of two concurrent refreshes with the same token exactly one wins.
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id, replacedBy primitive.ObjectID) (bool, error) {
+	collection := r.db.Collection(models.RefreshTokenCollection)
+
+	now := time.Now()
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "used_at": nil, "revoked_at": nil},
+		bson.M{"$set": bson.M{"used_at": now, "replaced_by": replacedBy}},
+	)
+	if err != nil {
+		return false, utils.DatabaseError("using refresh token", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// RevokeFamily revokes every token issued from the same login
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID primitive.ObjectID) error {
+	collection := r.db.Collection(models.RefreshTokenCollection)
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"family_id": familyID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return utils.DatabaseError("revoking refresh token family", err)
+	}
+
+	return nil
+}
+
+// RevokeUser revokes every session of the user
+func (r *refreshTokenRepository) RevokeUser(ctx context.Context, userID primitive.ObjectID) error {
+	collection := r.db.Collection(models.RefreshTokenCollection)
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return utils.DatabaseError("revoking user sessions", err)
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions revokes every session of the user except keepFamilyID
+func (r *refreshTokenRepository) RevokeOtherSessions(ctx context.Context, userID, keepFamilyID primitive.ObjectID) error {
+	collection := r.db.Collection(models.RefreshTokenCollection)
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "family_id": bson.M{"$ne": keepFamilyID}, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return utils.DatabaseError("revoking other user sessions", err)
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) IsFamilyRevoked(ctx context.Context, familyID primitive.ObjectID) (bool, error) {
+	collection := r.db.Collection(models.RefreshTokenCollection)
+
+	count, err := collection.CountDocuments(ctx, bson.M{"family_id": familyID, "revoked_at": bson.M{"$ne": nil}})
+	if err != nil {
+		return false, utils.DatabaseError("checking refresh token family", err)
+	}
+
+	return count > 0, nil
+}
@@ -6,12 +6,26 @@ import (
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TransactionRepository interface {
 	CreateTransaction(ctx context.Context, dto *dtos.CreateTransactionDTO) (*models.Transaction, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Transaction, error)
+	ListTransactions(ctx context.Context, filter *dtos.TransactionFilter) ([]models.Transaction, error)
+	ResolveHold(ctx context.Context, id primitive.ObjectID, status models.TransactionStatus, amount money.Amount, journalEntryID primitive.ObjectID) (bool, error)
+	FindExpiredHolds(ctx context.Context, before time.Time, limit int64) ([]models.Transaction, error)
+	FindByTransferID(ctx context.Context, transferID primitive.ObjectID) ([]models.Transaction, error)
+	AddReversedAmount(ctx context.Context, id primitive.ObjectID, amount money.Amount) (bool, error)
+	ListChain(ctx context.Context, accountID primitive.ObjectID, afterSequence int64, limit int64) ([]models.Transaction, error)
+	CountUnchained(ctx context.Context, accountID primitive.ObjectID) (int64, error)
+	ListAccountIDs(ctx context.Context) ([]primitive.ObjectID, error)
+	SumLimitUsage(ctx context.Context, accountID primitive.ObjectID, currency string, direction models.LimitDirection, dayStart, monthStart time.Time) (*models.LimitUsage, error)
 }
 
 type transactionRepository struct {
@@ -22,24 +36,386 @@ func NewTransactionRepository(db *mongo.Database) TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
+// CreateTransaction appends a transaction to its account's hash chain. Two writers appending to the
+// same chain at once conflict on the chain position, and one of them fails. Pending holds join the
+// chain when they are resolved, so that their hash covers how they ended.
 func (r *transactionRepository) CreateTransaction(ctx context.Context, dto *dtos.CreateTransactionDTO) (*models.Transaction, error) {
+	// Mongo stores dates with millisecond precision, and the hash must match what is read back
+	now := time.Now().Truncate(time.Millisecond)
 	transaction := &models.Transaction{
 		ID:              primitive.NewObjectID(),
 		AccountID:       dto.AccountID,
 		Type:            models.TransactionType(dto.Type),
 		Amount:          dto.Amount,
 		Currency:        dto.Currency,
+		Description:     dto.Description,
+		TransferID:      dto.TransferID,
+		ConversionID:    dto.ConversionID,
+		JournalEntryID:  dto.JournalEntryID,
 		Status:          models.TransactionStatusCompleted,
-		TransactionDate: time.Now(),
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		HeldAmount:      dto.HeldAmount,
+		ExpiresAt:       dto.ExpiresAt,
+		ReversalOf:      dto.ReversalOf,
+		Charge:          dto.Charge,
+		APIKeyID:        dto.APIKeyID,
+		TransactionDate: now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 
+	if dto.Status != "" {
+		transaction.Status = models.TransactionStatus(dto.Status)
+	}
+
+	if transaction.Status != models.TransactionStatusPending {
+		previous, err := r.chainHead(ctx, transaction.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		transaction.ChainVersion = models.ChainVersionFinalState
+		transaction.ChainTo(previous)
+	}
+
+	collection := r.db.Collection(models.TransactionCollection)
+	if _, err := collection.InsertOne(ctx, transaction); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// chainHead returns the last transaction of the account's hash chain, or nil if it has none
+func (r *transactionRepository) chainHead(ctx context.Context, accountID primitive.ObjectID) (*models.Transaction, error) {
 	collection := r.db.Collection(models.TransactionCollection)
-	_, err := collection.InsertOne(ctx, transaction)
+
+	head := &models.Transaction{}
+	err := collection.FindOne(ctx,
+		bson.M{"account_id": accountID, "chain_sequence": bson.M{"$exists": true}},
+		options.FindOne().SetSort(bson.D{{Key: "chain_sequence", Value: -1}}),
+	).Decode(head)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	return head, nil
+}
+
+func (r *transactionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Transaction, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	transaction := &models.Transaction{}
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(transaction); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, utils.DatabaseError("getting transaction", err)
+	}
+
 	return transaction, nil
 }
+
+// ListTransactions returns an account's transactions newest first, starting after filter.After
+func (r *transactionRepository) ListTransactions(ctx context.Context, filter *dtos.TransactionFilter) ([]models.Transaction, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	query := bson.M{"account_id": filter.AccountID}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Currency != "" {
+		query["currency"] = filter.Currency
+	}
+
+	dateRange := bson.M{}
+	if filter.From != nil {
+		dateRange["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		dateRange["$lte"] = *filter.To
+	}
+	if len(dateRange) > 0 {
+		query["transaction_date"] = dateRange
+	}
+
+	amountRange := bson.M{}
+	if filter.MinAmount != nil {
+		amountRange["$gte"] = int64(*filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		amountRange["$lte"] = int64(*filter.MaxAmount)
+	}
+	if len(amountRange) > 0 {
+		query["amount"] = amountRange
+	}
+
+	// Keyset pagination on (transaction_date, _id), both descending
+	if filter.After != nil {
+		query["$or"] = bson.A{
+			bson.M{"transaction_date": bson.M{"$lt": filter.After.TransactionDate}},
+			bson.M{
+				"transaction_date": filter.After.TransactionDate,
+				"_id":              bson.M{"$lt": filter.After.ID},
+			},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "transaction_date", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, utils.DatabaseError("listing transactions", err)
+	}
+	defer cursor.Close(ctx)
+
+	transactions := []models.Transaction{}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, utils.DatabaseError("decoding transactions", err)
+	}
+
+	return transactions, nil
+}
+
+// ResolveHold moves a pending hold to its final status and appends it to its account's hash chain. It
+// reports false when the hold is no longer pending. Holds chained before their final state was covered
+// keep their place in the chain.
+func (r *transactionRepository) ResolveHold(ctx context.Context, id primitive.ObjectID, status models.TransactionStatus, amount money.Amount, journalEntryID primitive.ObjectID) (bool, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+	filter := bson.M{"_id": id, "status": models.TransactionStatusPending}
+
+	hold := &models.Transaction{}
+	if err := collection.FindOne(ctx, filter).Decode(hold); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, utils.DatabaseError("resolving hold", err)
+	}
+
+	hold.Status = status
+	hold.Amount = amount
+	set := bson.M{
+		"status":     status,
+		"amount":     int64(amount),
+		"updated_at": time.Now(),
+	}
+	if !journalEntryID.IsZero() {
+		hold.JournalEntryID = journalEntryID
+		set["journal_entry_id"] = journalEntryID
+	}
+	if hold.ChainSequence == 0 {
+		previous, err := r.chainHead(ctx, hold.AccountID)
+		if err != nil {
+			return false, utils.DatabaseError("resolving hold", err)
+		}
+		hold.ChainVersion = models.ChainVersionFinalState
+		hold.ChainTo(previous)
+		set["chain_version"] = hold.ChainVersion
+		set["chain_sequence"] = hold.ChainSequence
+		set["previous_hash"] = hold.PreviousHash
+		set["hash"] = hold.Hash
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set, "$unset": bson.M{"expires_at": ""}})
+	if err != nil {
+		return false, utils.DatabaseError("resolving hold", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// FindExpiredHolds returns pending holds whose expiry is before the given time
+func (r *transactionRepository) FindExpiredHolds(ctx context.Context, before time.Time, limit int64) ([]models.Transaction, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	filter := bson.M{
+		"status":     models.TransactionStatusPending,
+		"expires_at": bson.M{"$lte": before},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "expires_at", Value: 1}}).SetLimit(limit)
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, utils.DatabaseError("finding expired holds", err)
+	}
+	defer cursor.Close(ctx)
+
+	holds := []models.Transaction{}
+	if err := cursor.All(ctx, &holds); err != nil {
+		return nil, utils.DatabaseError("decoding expired holds", err)
+	}
+
+	return holds, nil
+}
+
+// FindByTransferID returns both legs of a transfer
+func (r *transactionRepository) FindByTransferID(ctx context.Context, transferID primitive.ObjectID) ([]models.Transaction, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	cursor, err := collection.Find(ctx, bson.M{"transfer_id": transferID, "reversal_of": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, utils.DatabaseError("finding transfer legs", err)
+	}
+	defer cursor.Close(ctx)
+
+	legs := []models.Transaction{}
+	if err := cursor.All(ctx, &legs); err != nil {
+		return nil, utils.DatabaseError("decoding transfer legs", err)
+	}
+
+	return legs, nil
+}
+
+// AddReversedAmount records a reversal against a completed transaction. It reports false when
+// the total reversed would exceed the original amount.
+func (r *transactionRepository) AddReversedAmount(ctx context.Context, id primitive.ObjectID, amount money.Amount) (bool, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	filter := bson.M{
+		"_id":    id,
+		"status": models.TransactionStatusCompleted,
+		"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$reversed_amount", 0}}, int64(amount)}},
+			"$amount",
+		}},
+	}
+	update := bson.M{
+		"$inc": bson.M{"reversed_amount": int64(amount)},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, utils.DatabaseError("recording reversal", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// ListChain returns up to limit transactions of the account's hash chain that follow afterSequence, in chain order
+func (r *transactionRepository) ListChain(ctx context.Context, accountID primitive.ObjectID, afterSequence int64, limit int64) ([]models.Transaction, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "chain_sequence", Value: 1}}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, bson.M{
+		"account_id":     accountID,
+		"chain_sequence": bson.M{"$gt": afterSequence},
+	}, opts)
+	if err != nil {
+		return nil, utils.DatabaseError("listing transaction chain", err)
+	}
+	defer cursor.Close(ctx)
+
+	transactions := []models.Transaction{}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, utils.DatabaseError("listing transaction chain", err)
+	}
+
+	return transactions, nil
+}
+
+// CountUnchained counts the account's transactions that are not part of its hash chain. Pending holds
+// are left out, as they join the chain once resolved.
+func (r *transactionRepository) CountUnchained(ctx context.Context, accountID primitive.ObjectID) (int64, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	count, err := collection.CountDocuments(ctx, bson.M{
+		"account_id":     accountID,
+		"chain_sequence": bson.M{"$exists": false},
+		"status":         bson.M{"$ne": models.TransactionStatusPending},
+	})
+	if err != nil {
+		return 0, utils.DatabaseError("counting unchained transactions", err)
+	}
+
+	return count, nil
+}
+
+// ListAccountIDs returns every account that has transactions
+func (r *transactionRepository) ListAccountIDs(ctx context.Context) ([]primitive.ObjectID, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	values, err := collection.Distinct(ctx, "account_id", bson.M{})
+	if err != nil {
+		return nil, utils.DatabaseError("listing accounts with transactions", err)
+	}
+
+	accountIDs := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		if accountID, ok := value.(primitive.ObjectID); ok {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+
+	return accountIDs, nil
+}
+
+// limitUsageFilters select the transactions that count towards the limits of each direction
+var limitUsageFilters = map[models.LimitDirection]bson.M{
+	models.LimitDirectionDeposit: {
+		"type":          models.TransactionTypeCredit,
+		"status":        models.TransactionStatusCompleted,
+		"transfer_id":   bson.M{"$exists": false},
+		"conversion_id": bson.M{"$exists": false},
+		"reversal_of":   bson.M{"$exists": false},
+	},
+	models.LimitDirectionWithdrawal: {
+		"type":          models.TransactionTypeDebit,
+		"status":        bson.M{"$in": bson.A{models.TransactionStatusPending, models.TransactionStatusCompleted}},
+		"conversion_id": bson.M{"$exists": false},
+		"reversal_of":   bson.M{"$exists": false},
+		"charge":        bson.M{"$ne": true},
+	},
+}
+
+// SumLimitUsage counts and totals the account's transactions in direction since dayStart and since
+// monthStart. Amounts are net of what was reversed; pending holds count at their authorized amount.
+func (r *transactionRepository) SumLimitUsage(ctx context.Context, accountID primitive.ObjectID, currency string, direction models.LimitDirection, dayStart, monthStart time.Time) (*models.LimitUsage, error) {
+	collection := r.db.Collection(models.TransactionCollection)
+
+	match := bson.M{
+		"account_id":       accountID,
+		"currency":         currency,
+		"transaction_date": bson.M{"$gte": monthStart},
+	}
+	for key, value := range limitUsageFilters[direction] {
+		match[key] = value
+	}
+
+	amount := bson.M{"$subtract": bson.A{"$amount", bson.M{"$ifNull": bson.A{"$reversed_amount", 0}}}}
+	today := bson.M{"$gte": bson.A{"$transaction_date", dayStart}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            nil,
+			"monthly_count":  bson.M{"$sum": 1},
+			"monthly_amount": bson.M{"$sum": amount},
+			"daily_count":    bson.M{"$sum": bson.M{"$cond": bson.A{today, 1, 0}}},
+			"daily_amount":   bson.M{"$sum": bson.M{"$cond": bson.A{today, amount, 0}}},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, utils.DatabaseError("summing limit usage", err)
+	}
+	defer cursor.Close(ctx)
+
+	usage := &models.LimitUsage{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(usage); err != nil {
+			return nil, utils.DatabaseError("decoding limit usage", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, utils.DatabaseError("summing limit usage", err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TxRunner runs work inside a Mongo transaction. Repositories called with the context fn receives
+// take part in the transaction.
+type TxRunner interface {
+	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txRunner struct {
+	db *mongo.Database
+}
+
+func NewTxRunner(db *mongo.Database) TxRunner {
+	return &txRunner{db: db}
+}
+
+// RunInTransaction runs fn inside a Mongo transaction, committing on success and aborting on error.
+// fn is run again when the server labels its error transient, such as a write conflict with a
+// concurrent transaction.
+func (r *txRunner) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
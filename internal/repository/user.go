@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, dto *dtos.CreateUserDTO) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	List(ctx context.Context, filter dtos.UserFilter) ([]models.User, error)
+	UpdateRole(ctx context.Context, id primitive.ObjectID, role models.Role) (*models.User, error)
+	SetMFASecret(ctx context.Context, id primitive.ObjectID, secret string) (bool, error)
+	EnableMFA(ctx context.Context, id primitive.ObjectID, step int64, recoveryCodeHashes []string) (bool, error)
+	AdvanceMFAStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, id primitive.ObjectID, codeHash string) (bool, error)
+	SetMFAChallenge(ctx context.Context, id primitive.ObjectID, challengeID string) error
+	ConsumeMFAChallenge(ctx context.Context, id primitive.ObjectID, challengeID string) (bool, error)
+	UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) (bool, error)
+	MarkEmailVerified(ctx context.Context, id primitive.ObjectID, email string) (bool, error)
+	MarkPhoneVerified(ctx context.Context, id primitive.ObjectID, phone string) (bool, error)
+	Update(ctx context.Context, user *models.User) (bool, error)
+	Lock(ctx context.Context, id primitive.ObjectID, event models.LockoutEvent) error
+	Unlock(ctx context.Context, id primitive.ObjectID, event models.LockoutEvent) (*models.User, error)
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, change models.StatusChange) (*models.User, error)
+}
+
+type userRepository struct {
+	db *mongo.Database
+}
+
+func NewUserRepository(db *mongo.Database) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(ctx context.Context, dto *dtos.CreateUserDTO) (*models.User, error) {
+	user := &models.User{
+		ID:          primitive.NewObjectID(),
+		Name:        dto.Name,
+		Email:       dto.Email,
+		PhoneNumber: dto.PhoneNumber,
+		Password:    dto.Password,
+		Status:      models.UserStatus(dto.Status),
+		Role:        models.Role(dto.Role),
+		CreatedAt:   dto.CreatedAt,
+		UpdatedAt:   dto.UpdatedAt,
+	}
+
+	col := r.db.Collection(models.UserCollection)
+	_, err := col.InsertOne(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	col := r.db.Collection(models.UserCollection)
+	user := &models.User{}
+	err := col.FindOne(ctx, bson.M{"email": email}).Decode(user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	col := r.db.Collection(models.UserCollection)
+	user := &models.User{}
+	err := col.FindOne(ctx, bson.M{"_id": id}).Decode(user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// List returns users in ascending ID order, starting after filter.After
+func (r *userRepository) List(ctx context.Context, filter dtos.UserFilter) ([]models.User, error) {
+	query := bson.M{}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.After != nil {
+		query["_id"] = bson.M{"$gt": *filter.After}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(filter.Limit))
+
+	col := r.db.Collection(models.UserCollection)
+	cursor, err := col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateRole sets the user's role and returns the updated user, or nil if it does not exist
+func (r *userRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role models.Role) (*models.User, error) {
+	col := r.db.Collection(models.UserCollection)
+	user := &models.User{}
+	err := col.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"role": role, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetMFASecret stores a new TOTP secret for a user that has not enabled MFA yet. It reports
+// false if MFA is already enabled or the user does not exist.
+func (r *userRepository) SetMFASecret(ctx context.Context, id primitive.ObjectID, secret string) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "mfa.enabled": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"mfa.secret": secret, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// EnableMFA turns on MFA for an enrolled user after their first code was verified at step
+func (r *userRepository) EnableMFA(ctx context.Context, id primitive.ObjectID, step int64, recoveryCodeHashes []string) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "mfa.enabled": bson.M{"$ne": true}, "mfa.secret": bson.M{"$exists": true}},
+		bson.M{"$set": bson.M{
+			"mfa.enabled":        true,
+			"mfa.last_step":      step,
+			"mfa.recovery_codes": recoveryCodeHashes,
+			"updated_at":         time.Now(),
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// AdvanceMFAStep records step as the last accepted TOTP step. It reports false if a code from the
+// same or a later step was already accepted.
+func (r *userRepository) AdvanceMFAStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "mfa.last_step": bson.M{"$not": bson.M{"$gte": step}}},
+		bson.M{"$set": bson.M{"mfa.last_step": step}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// UseRecoveryCode removes a recovery code from the user. It reports false if the code is unknown
+// or was already used.
+func (r *userRepository) UseRecoveryCode(ctx context.Context, id primitive.ObjectID, codeHash string) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "mfa.recovery_codes": codeHash},
+		bson.M{"$pull": bson.M{"mfa.recovery_codes": codeHash}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// SetMFAChallenge records the token ID of the latest login challenge, replacing any earlier one
+func (r *userRepository) SetMFAChallenge(ctx context.Context, id primitive.ObjectID, challengeID string) error {
+	col := r.db.Collection(models.UserCollection)
+	_, err := col.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"mfa.challenge_id": challengeID}},
+	)
+	return err
+}
+
+// ConsumeMFAChallenge clears the login challenge with the given token ID. It reports false if the
+// challenge was already used or replaced by a newer one.
+func (r *userRepository) ConsumeMFAChallenge(ctx context.Context, id primitive.ObjectID, challengeID string) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "mfa.challenge_id": challengeID},
+		bson.M{"$unset": bson.M{"mfa.challenge_id": ""}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// UpdatePassword replaces the user's password hash. It reports false if the user does not exist.
+func (r *userRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"password": passwordHash, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// MarkEmailVerified confirms the user's email. It reports false if the user's email is no longer
+// the address that was verified.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id primitive.ObjectID, email string) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "email": email},
+		bson.M{"$set": bson.M{"email_verified": true, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// MarkPhoneVerified confirms the user's phone number. It reports false if the user's phone number
+// is no longer the number that was verified.
+func (r *userRepository) MarkPhoneVerified(ctx context.Context, id primitive.ObjectID, phone string) (bool, error) {
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "phone_number": phone},
+		bson.M{"$set": bson.M{"phone_verified": true, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// Update saves the user's profile and password, provided the stored user was not modified since
+// it was read, and advances user.UpdatedAt. It reports false if the user changed in between or
+// does not exist.
+func (r *userRepository) Update(ctx context.Context, user *models.User) (bool, error) {
+	// Mongo stores dates with millisecond precision, and the next update must match what is read back
+	now := time.Now().Truncate(time.Millisecond)
+
+	col := r.db.Collection(models.UserCollection)
+	result, err := col.UpdateOne(ctx,
+		bson.M{"_id": user.ID, "updated_at": user.UpdatedAt},
+		bson.M{"$set": bson.M{
+			"name":           user.Name,
+			"email":          user.Email,
+			"email_verified": user.EmailVerified,
+			"phone_number":   user.PhoneNumber,
+			"phone_verified": user.PhoneVerified,
+			"password":       user.Password,
+			"updated_at":     now,
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount == 0 {
+		return false, nil
+	}
+
+	user.UpdatedAt = now
+	return true, nil
+}
+
+// Lock refuses logins to the user until event.Until and records the event
+func (r *userRepository) Lock(ctx context.Context, id primitive.ObjectID, event models.LockoutEvent) error {
+	col := r.db.Collection(models.UserCollection)
+	_, err := col.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set":  bson.M{"locked_until": event.Until, "updated_at": time.Now()},
+			"$push": bson.M{"lockout_events": event},
+		},
+	)
+	return err
+}
+
+// Unlock lifts a lockout, records the event and returns the updated user, or nil if it does not exist
+func (r *userRepository) Unlock(ctx context.Context, id primitive.ObjectID, event models.LockoutEvent) (*models.User, error) {
+	col := r.db.Collection(models.UserCollection)
+	user := &models.User{}
+	err := col.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$unset": bson.M{"locked_until": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$push":  bson.M{"lockout_events": event},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateStatus moves the user from change.From to change.To, records the change and returns the
+// updated user. It returns nil if the user does not exist or their status is no longer change.From.
+func (r *userRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, change models.StatusChange) (*models.User, error) {
+	filter := bson.M{"_id": id, "status": change.From}
+	if change.From.IsActive() {
+		// Users created before statuses were enforced may have no status at all
+		filter["status"] = bson.M{"$in": bson.A{models.UserStatusActive, "", nil}}
+	}
+
+	col := r.db.Collection(models.UserCollection)
+	user := &models.User{}
+	err := col.FindOneAndUpdate(ctx,
+		filter,
+		bson.M{
+			"$set":  bson.M{"status": change.To, "updated_at": time.Now()},
+			"$push": bson.M{"status_history": change},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
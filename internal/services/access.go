@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccessService decides which accounts an authenticated principal may act on. Owners may do anything a
+// holder can on their accounts and joint holders what the owner granted them; on other accounts, and for
+// staff-only permissions such as reversals, principals are limited to the permissions of their role. Principals authenticated with an API key are further limited to the
+// key's scopes.
+type AccessService interface {
+	OwnedAccounts(ctx context.Context, principal *models.Principal) ([]primitive.ObjectID, error)
+	AuthorizeAccount(ctx context.Context, principal *models.Principal, accountID primitive.ObjectID, permission models.Permission) error
+	AuthorizeTransaction(ctx context.Context, principal *models.Principal, transactionID primitive.ObjectID, permission models.Permission) error
+}
+
+type accessService struct {
+	transactionRepo repository.TransactionRepository
+	accountRepo     repository.AccountRepository
+}
+
+func NewAccessService(transactionRepo repository.TransactionRepository, accountRepo repository.AccountRepository) AccessService {
+	return &accessService{
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+	}
+}
+
+// OwnedAccounts resolves the principal to the accounts it holds, as owner or joint holder
+func (s *accessService) OwnedAccounts(ctx context.Context, principal *models.Principal) ([]primitive.ObjectID, error) {
+	if principal == nil || principal.UserID.IsZero() {
+		return nil, utils.ErrInvalidToken
+	}
+
+	accounts, err := s.accountRepo.ListByHolder(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(accounts))
+	for _, account := range accounts {
+		ids = append(ids, account.ID)
+	}
+	return ids, nil
+}
+
+// AuthorizeAccount returns ErrForbidden unless the principal holds accountID with permission or its role
+// grants permission, and ErrAPIKeyScope when the principal's API key was not granted permission
+func (s *accessService) AuthorizeAccount(ctx context.Context, principal *models.Principal, accountID primitive.ObjectID, permission models.Permission) error {
+	if principal == nil || principal.UserID.IsZero() {
+		return utils.ErrInvalidToken
+	}
+	if !principal.Allows(permission) {
+		return utils.ErrAPIKeyScope
+	}
+
+	// Staff-only permissions come from the role alone, whoever holds the account
+	if !models.IsHolderPermission(permission) {
+		if principal.Role.Has(permission) {
+			return nil
+		}
+		return utils.ErrForbidden
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if account != nil {
+		if holder := account.Holder(principal.UserID); holder != nil && holder.Allows(permission) {
+			return nil
+		}
+	}
+	if principal.Role.Has(permission) {
+		return nil
+	}
+	return utils.ErrForbidden
+}
+
+// AuthorizeTransaction applies AuthorizeAccount to the account the transaction was posted to
+func (s *accessService) AuthorizeTransaction(ctx context.Context, principal *models.Principal, transactionID primitive.ObjectID, permission models.Permission) error {
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+	if transaction == nil {
+		return utils.ErrTransactionNotFound
+	}
+
+	return s.AuthorizeAccount(ctx, principal, transaction.AccountID, permission)
+}
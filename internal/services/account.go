@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// AccountService opens accounts and manages their holders. Callers authorize the principal on the
+// account before changing its holders.
+type AccountService interface {
+	OpenAccount(ctx context.Context, principal *models.Principal, input dtos.OpenAccountRequest) (*models.Account, error)
+	ListAccounts(ctx context.Context, principal *models.Principal) (*dtos.AccountListResponse, error)
+	GetAccount(ctx context.Context, id primitive.ObjectID) (*models.Account, error)
+	AddHolder(ctx context.Context, principal *models.Principal, id primitive.ObjectID, input dtos.AddHolderRequest) (*models.Account, error)
+	RemoveHolder(ctx context.Context, principal *models.Principal, id, userID primitive.ObjectID) (*models.Account, error)
+	SetTier(ctx context.Context, principal *models.Principal, id primitive.ObjectID, tier models.AccountTier) (*models.Account, error)
+}
+
+type accountService struct {
+	accountRepo repository.AccountRepository
+	userRepo    repository.UserRepository
+	auditRepo   repository.AuditEventRepository
+}
+
+func NewAccountService(accountRepo repository.AccountRepository, userRepo repository.UserRepository, auditRepo repository.AuditEventRepository) AccountService {
+	return &accountService{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		auditRepo:   auditRepo,
+	}
+}
+
+// OpenAccount opens an account owned by the principal
+func (s *accountService) OpenAccount(ctx context.Context, principal *models.Principal, input dtos.OpenAccountRequest) (*models.Account, error) {
+	if !principal.Allows(models.PermissionAccountManage) {
+		return nil, utils.ErrAPIKeyScope
+	}
+
+	account, err := openAccount(ctx, s.accountRepo, principal.UserID, models.AccountProduct(input.Product), input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	event := withActor(newAuditEvent(ctx, models.AuditActionAccountOpened, &account.ID), principal)
+	event.TargetUserID = &principal.UserID
+	event.Details = map[string]string{"product": string(account.Product)}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	return account, nil
+}
+
+// ListAccounts returns the accounts the principal holds, as owner or joint holder
+func (s *accountService) ListAccounts(ctx context.Context, principal *models.Principal) (*dtos.AccountListResponse, error) {
+	if !principal.Allows(models.PermissionAccountRead) {
+		return nil, utils.ErrAPIKeyScope
+	}
+
+	accounts, err := s.accountRepo.ListByHolder(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.AccountListResponse{Accounts: accounts}, nil
+}
+
+func (s *accountService) GetAccount(ctx context.Context, id primitive.ObjectID) (*models.Account, error) {
+	account, err := s.accountRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, utils.ErrAccountNotFound
+	}
+
+	return account, nil
+}
+
+// AddHolder makes the user with the given email a joint holder limited to the requested permissions. A
+// joint holder managing the account can only grant permissions it holds itself.
+func (s *accountService) AddHolder(ctx context.Context, principal *models.Principal, id primitive.ObjectID, input dtos.AddHolderRequest) (*models.Account, error) {
+	for _, permission := range input.Permissions {
+		if !models.IsHolderPermission(permission) {
+			return nil, utils.ErrInvalidHolderPermission
+		}
+	}
+
+	manager, err := s.jointManager(ctx, principal, id)
+	if err != nil {
+		return nil, err
+	}
+	if manager != nil {
+		for _, permission := range input.Permissions {
+			if !manager.Allows(permission) {
+				return nil, utils.ErrForbidden
+			}
+		}
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	account, err := s.accountRepo.AddHolder(ctx, id, models.AccountHolder{
+		UserID:      user.ID,
+		Role:        models.HolderRoleJoint,
+		Permissions: input.Permissions,
+		AddedAt:     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		// Tell a missing account apart from a user who already holds it
+		if _, err := s.GetAccount(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrAlreadyHolder
+	}
+
+	event := withActor(newAuditEvent(ctx, models.AuditActionHolderAdded, &account.ID), principal)
+	event.TargetUserID = &user.ID
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	return account, nil
+}
+
+// RemoveHolder removes a joint holder. The owner cannot be removed, and a joint holder managing the
+// account can only remove itself.
+func (s *accountService) RemoveHolder(ctx context.Context, principal *models.Principal, id, userID primitive.ObjectID) (*models.Account, error) {
+	manager, err := s.jointManager(ctx, principal, id)
+	if err != nil {
+		return nil, err
+	}
+	if manager != nil && userID != principal.UserID {
+		return nil, utils.ErrForbidden
+	}
+
+	account, err := s.accountRepo.RemoveHolder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		if _, err := s.GetAccount(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrHolderNotFound
+	}
+
+	event := withActor(newAuditEvent(ctx, models.AuditActionHolderRemoved, &account.ID), principal)
+	event.TargetUserID = &userID
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	return account, nil
+}
+
+// jointManager returns the principal's holding when the principal manages the account's holders as a
+// joint holder, and nil for the owner and for staff whose role manages accounts
+func (s *accountService) jointManager(ctx context.Context, principal *models.Principal, id primitive.ObjectID) (*models.AccountHolder, error) {
+	if principal.Role.Has(models.PermissionAccountManage) {
+		return nil, nil
+	}
+
+	account, err := s.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	holder := account.Holder(principal.UserID)
+	if holder == nil {
+		return nil, utils.ErrForbidden
+	}
+	if holder.Role == models.HolderRoleOwner {
+		return nil, nil
+	}
+	return holder, nil
+}
+
+// SetTier moves the account to the tier whose limit policies should apply to it
+func (s *accountService) SetTier(ctx context.Context, principal *models.Principal, id primitive.ObjectID, tier models.AccountTier) (*models.Account, error) {
+	if !models.IsAccountTier(tier) {
+		return nil, utils.ErrInvalidAccountTier
+	}
+
+	before, err := s.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.SetTier(ctx, id, tier)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, utils.ErrAccountNotFound
+	}
+
+	event := withActor(newAuditEvent(ctx, models.AuditActionTierChanged, &account.ID), principal)
+	event.Details = map[string]string{
+		"from": string(before.LimitTier()),
+		"to":   string(account.LimitTier()),
+	}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	return account, nil
+}
+
+// openAccount opens an account of the given product with ownerID as its only holder
+func openAccount(ctx context.Context, accountRepo repository.AccountRepository, ownerID primitive.ObjectID, product models.AccountProduct, name string) (*models.Account, error) {
+	now := time.Now()
+	account := &models.Account{
+		Product: product,
+		Name:    name,
+		Holders: []models.AccountHolder{
+			{UserID: ownerID, Role: models.HolderRoleOwner, AddedAt: now},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := accountRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
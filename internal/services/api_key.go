@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// APIKeyPrefix starts every API key so that leaked keys are easy to recognise
+const APIKeyPrefix = "axk_"
+
+// apiKeyDisplayLength is how many leading characters of a key are stored in clear to tell keys apart
+const apiKeyDisplayLength = 12
+
+// APIKeyService issues and verifies API keys for machine clients
+type APIKeyService interface {
+	Create(ctx context.Context, principal *models.Principal, input dtos.CreateAPIKeyRequest) (*dtos.APIKeySecretResponse, error)
+	List(ctx context.Context, principal *models.Principal) (*dtos.APIKeyListResponse, error)
+	Rotate(ctx context.Context, principal *models.Principal, id primitive.ObjectID) (*dtos.APIKeySecretResponse, error)
+	Revoke(ctx context.Context, principal *models.Principal, id primitive.ObjectID) error
+	Authenticate(ctx context.Context, key string) (*models.Principal, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   repository.UserRepository
+	auditRepo  repository.AuditEventRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, auditRepo repository.AuditEventRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+// Create issues a new key for the principal
+func (s *apiKeyService) Create(ctx context.Context, principal *models.Principal, input dtos.CreateAPIKeyRequest) (*dtos.APIKeySecretResponse, error) {
+	if err := checkAPIKeyManager(principal); err != nil {
+		return nil, err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, utils.ErrInvalidExpiry
+	}
+
+	scopes := make([]models.Permission, 0, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		permission := models.Permission(scope)
+		if !permission.IsValid() {
+			return nil, utils.ErrInvalidScope
+		}
+		scopes = append(scopes, permission)
+	}
+
+	response, err := s.issue(ctx, &models.APIKey{
+		UserID:    principal.UserID,
+		Name:      input.Name,
+		Scopes:    scopes,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordKeyEvent(ctx, principal, models.AuditActionAPIKeyCreated, response.ID)
+	return response, nil
+}
+
+// List returns every key of the principal, newest first, without secrets
+func (s *apiKeyService) List(ctx context.Context, principal *models.Principal) (*dtos.APIKeyListResponse, error) {
+	if err := checkAPIKeyManager(principal); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeyRepo.ListByUser(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.APIKeyListResponse{APIKeys: keys}, nil
+}
+
+// Rotate revokes a key and issues a replacement with the same name, scopes and expiry
+func (s *apiKeyService) Rotate(ctx context.Context, principal *models.Principal, id primitive.ObjectID) (*dtos.APIKeySecretResponse, error) {
+	if err := checkAPIKeyManager(principal); err != nil {
+		return nil, err
+	}
+
+	current, err := s.apiKeyRepo.FindByID(ctx, principal.UserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || !current.IsActive(time.Now()) {
+		return nil, utils.ErrAPIKeyNotFound
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(ctx, principal.UserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, utils.ErrAPIKeyNotFound
+	}
+
+	response, err := s.issue(ctx, &models.APIKey{
+		UserID:      current.UserID,
+		Name:        current.Name,
+		Scopes:      current.Scopes,
+		ExpiresAt:   current.ExpiresAt,
+		RotatedFrom: &current.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordKeyEvent(ctx, principal, models.AuditActionAPIKeyRotated, response.ID)
+	return response, nil
+}
+
+// Revoke stops a key from authenticating any further request
+func (s *apiKeyService) Revoke(ctx context.Context, principal *models.Principal, id primitive.ObjectID) error {
+	if err := checkAPIKeyManager(principal); err != nil {
+		return err
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(ctx, principal.UserID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return utils.ErrAPIKeyNotFound
+	}
+
+	s.recordKeyEvent(ctx, principal, models.AuditActionAPIKeyRevoked, id)
+	return nil
+}
+
+// recordKeyEvent records a change to one of the principal's keys
+func (s *apiKeyService) recordKeyEvent(ctx context.Context, principal *models.Principal, action models.AuditAction, keyID primitive.ObjectID) {
+	event := withActor(newUserAuditEvent(ctx, action, &principal.UserID), principal)
+	event.Details = map[string]string{"api_key_id": keyID.Hex()}
+	recordAuditEvent(ctx, s.auditRepo, event)
+}
+
+// Authenticate resolves a presented key to the principal of the user it belongs to
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*models.Principal, error) {
+	record, err := s.apiKeyRepo.FindByHash(ctx, hashOpaqueToken(key))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if record == nil || !record.IsActive(now) {
+		return nil, utils.ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.FindByID(ctx, record.UserID)
+	if err != nil {
+		return nil, utils.DatabaseError("getting user", err)
+	}
+	if user == nil {
+		return nil, utils.ErrInvalidAPIKey
+	}
+	// Keys outlive sessions, so they are refused while the user is blocked or deactivated
+	if err := userStatusError(user.Status); err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, record.ID, now); err != nil {
+		return nil, err
+	}
+
+	return models.NewAPIKeyPrincipal(user, record), nil
+}
+
+// issue generates the secret for key, stores it hashed and returns the only copy of the secret
+func (s *apiKeyService) issue(ctx context.Context, key *models.APIKey) (*dtos.APIKeySecretResponse, error) {
+	token, _, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	secret := APIKeyPrefix + token
+
+	key.Prefix = secret[:apiKeyDisplayLength]
+	key.KeyHash = hashOpaqueToken(secret)
+	key.CreatedAt = time.Now()
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &dtos.APIKeySecretResponse{APIKey: *key, Key: secret}, nil
+}
+
+// checkAPIKeyManager only lets principals authenticated with a token manage keys, so a leaked key
+// cannot mint more keys
+func checkAPIKeyManager(principal *models.Principal) error {
+	if principal == nil {
+		return utils.ErrInvalidToken
+	}
+	if principal.APIKeyID != nil {
+		return utils.ErrAPIKeyManagement
+	}
+	return nil
+}
+
+// apiKeyIDFromContext returns the ID of the API key the request was authenticated with, if any
+func apiKeyIDFromContext(ctx context.Context) *primitive.ObjectID {
+	if principal := models.PrincipalFromContext(ctx); principal != nil {
+		return principal.APIKeyID
+	}
+	return nil
+}
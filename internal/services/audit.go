@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// DefaultAuditPageSize is the page size of audit event listings without a limit
+const DefaultAuditPageSize = 50
+
+// AuditService reads the audit log on behalf of auditors
+type AuditService interface {
+	ListEvents(ctx context.Context, query dtos.AuditEventListQuery) (*dtos.AuditEventListResponse, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditEventRepository
+}
+
+func NewAuditService(auditRepo repository.AuditEventRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+// ListEvents returns a page of audit events, newest first. The cursor is the ID of the last event of the previous page.
+func (s *auditService) ListEvents(ctx context.Context, query dtos.AuditEventListQuery) (*dtos.AuditEventListResponse, error) {
+	filter := dtos.AuditEventFilter{
+		Action: query.Action,
+		Limit:  query.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultAuditPageSize
+	}
+
+	for _, id := range []struct {
+		value  string
+		target **primitive.ObjectID
+		name   string
+	}{
+		{query.ActorID, &filter.ActorID, "actor"},
+		{query.UserID, &filter.TargetUserID, "user"},
+		{query.AccountID, &filter.TargetAccountID, "account"},
+	} {
+		if id.value == "" {
+			continue
+		}
+		parsed, err := primitive.ObjectIDFromHex(id.value)
+		if err != nil {
+			return nil, utils.NewError(http.StatusBadRequest, "invalid "+id.name+" ID")
+		}
+		*id.target = &parsed
+	}
+	if query.Cursor != "" {
+		before, err := primitive.ObjectIDFromHex(query.Cursor)
+		if err != nil {
+			return nil, utils.ErrInvalidCursor
+		}
+		filter.Before = &before
+	}
+
+	for _, bound := range []struct {
+		value  string
+		target **time.Time
+	}{{query.From, &filter.From}, {query.To, &filter.To}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return nil, utils.NewError(http.StatusBadRequest, "dates must be in RFC 3339 format")
+		}
+		*bound.target = &t
+	}
+
+	// Fetch one extra record to know whether another page exists
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	events, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		events[i].TargetUserID = events[i].TargetUser()
+	}
+
+	response := &dtos.AuditEventListResponse{Events: events}
+	if len(events) > pageSize {
+		response.Events = events[:pageSize]
+		response.NextCursor = events[pageSize-1].ID.Hex()
+	}
+
+	return response, nil
+}
+
+// newAuditEvent starts an event about the target account, attributed to the principal and request carried by ctx
+func newAuditEvent(ctx context.Context, action models.AuditAction, target *primitive.ObjectID) *models.AuditEvent {
+	info := models.RequestInfoFromContext(ctx)
+	event := &models.AuditEvent{
+		Action:          action,
+		TargetAccountID: target,
+		IP:              info.IP,
+		UserAgent:       info.UserAgent,
+		RequestID:       info.RequestID,
+		CreatedAt:       time.Now(),
+	}
+
+	return withActor(event, models.PrincipalFromContext(ctx))
+}
+
+// newUserAuditEvent starts an event about the target user, such as a login or a role change
+func newUserAuditEvent(ctx context.Context, action models.AuditAction, target *primitive.ObjectID) *models.AuditEvent {
+	event := newAuditEvent(ctx, action, nil)
+	event.TargetUserID = target
+	return event
+}
+
+// withActor attributes an event to principal, when there is one
+func withActor(event *models.AuditEvent, principal *models.Principal) *models.AuditEvent {
+	if principal != nil {
+		actorID := principal.UserID
+		event.ActorID = &actorID
+		event.ActorRole = principal.Role
+		event.APIKeyID = principal.APIKeyID
+	}
+	return event
+}
+
+// recordAuditEvent appends a security event that has no transaction to share. A failure to record it
+// is logged rather than undoing what already happened.
+func recordAuditEvent(ctx context.Context, auditRepo repository.AuditEventRepository, event *models.AuditEvent) {
+	if err := auditRepo.Create(ctx, event); err != nil {
+		log.Error().Err(err).Str("action", string(event.Action)).Msg("Failed to record audit event")
+	}
+}
+
+// selfAuditEvent starts an event a user performs on themselves before they are authenticated, such as logging in
+func selfAuditEvent(ctx context.Context, action models.AuditAction, user *models.User) *models.AuditEvent {
+	event := newUserAuditEvent(ctx, action, &user.ID)
+	if event.ActorID == nil {
+		event.ActorID = &user.ID
+		event.ActorRole = models.RoleOf(user)
+	}
+	return event
+}
+
+// snapshotBalances reads the balances an operation is about to change, inside the caller's Mongo transaction
+func (s *transactionService) snapshotBalances(ctx context.Context, currency string, accountIDs ...primitive.ObjectID) ([]models.BalanceChange, error) {
+	changes := make([]models.BalanceChange, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		balance, err := s.balanceRepo.GetBalance(ctx, accountID, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		change := models.BalanceChange{AccountID: accountID, Currency: currency}
+		if balance != nil {
+			change.Before = balance.Amount
+			change.HeldBefore = balance.Held
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// recordMoneyEvent completes a balance snapshot with the balances after the change and appends the event
+// inside the caller's Mongo transaction, so the event is stored if and only if the change is
+func (s *transactionService) recordMoneyEvent(ctx context.Context, event *models.AuditEvent, changes []models.BalanceChange) error {
+	for i := range changes {
+		balance, err := s.balanceRepo.GetBalance(ctx, changes[i].AccountID, changes[i].Currency)
+		if err != nil {
+			return err
+		}
+		if balance != nil {
+			changes[i].After = balance.Amount
+			changes[i].HeldAfter = balance.Held
+		}
+	}
+	event.Balances = changes
+
+	return s.auditRepo.Create(ctx, event)
+}
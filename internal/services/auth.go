@@ -5,39 +5,92 @@ import (
 	"errors"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/jwt"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/sms"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrEmailExists        = errors.New("email already exists")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrEmailExists         = errors.New("email already exists")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	ErrInvalidMFAToken     = errors.New("invalid MFA token")
+	ErrInvalidMFACode      = errors.New("invalid MFA code")
+	ErrMFAAlreadyEnabled   = errors.New("MFA already enabled")
+	ErrMFANotEnrolled      = errors.New("MFA not enrolled")
+	ErrMFAManagement       = errors.New("MFA can only be managed with an access token")
+	ErrInvalidActionToken  = errors.New("invalid or expired token")
+	ErrEmailVerified       = errors.New("email already verified")
+	ErrPhoneVerified       = errors.New("phone number already verified")
+	ErrPhoneExists         = errors.New("phone number already exists")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+	ErrUserModified        = errors.New("user was modified concurrently")
+	ErrProfileManagement   = errors.New("profile can only be changed with an access token")
 )
 
+// RefreshTokenTTL is how long a refresh token can be exchanged for a new token pair
+var RefreshTokenTTL = utils.GetDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+
 type AuthService interface {
 	Register(ctx context.Context, input dtos.RegisterRequest) (*dtos.AuthResponse, error)
 	Login(ctx context.Context, input dtos.LoginRequest) (*dtos.AuthResponse, error)
+	Refresh(ctx context.Context, input dtos.RefreshRequest) (*dtos.AuthResponse, error)
+	Logout(ctx context.Context, input dtos.LogoutRequest) error
+	VerifyMFA(ctx context.Context, input dtos.MFALoginRequest) (*dtos.AuthResponse, error)
+	EnrollMFA(ctx context.Context, principal *models.Principal) (*dtos.MFAEnrollResponse, error)
+	ActivateMFA(ctx context.Context, principal *models.Principal, input dtos.MFAActivateRequest) (*dtos.MFAActivateResponse, error)
+	ForgotPassword(ctx context.Context, input dtos.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, input dtos.ResetPasswordRequest) error
+	VerifyEmail(ctx context.Context, input dtos.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, principal *models.Principal) error
+	VerifyPhone(ctx context.Context, input dtos.VerifyPhoneRequest) error
+	ResendPhoneVerification(ctx context.Context, principal *models.Principal) error
+	GetProfile(ctx context.Context, principal *models.Principal) (*models.User, error)
+	UpdateProfile(ctx context.Context, principal *models.Principal, input dtos.UpdateProfileRequest) (*models.User, error)
+	ChangePassword(ctx context.Context, principal *models.Principal, input dtos.ChangePasswordRequest) error
 }
 
 type authService struct {
-	accountRepo repository.AccountRepository
+	userRepo         repository.UserRepository
+	accountRepo      repository.AccountRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	actionTokenRepo  repository.ActionTokenRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	auditRepo        repository.AuditEventRepository
+	mailer           mailer.Mailer
+	texter           sms.Sender
 }
 
-func NewAuthService(accountRepo repository.AccountRepository) AuthService {
-	return &authService{accountRepo: accountRepo}
+func NewAuthService(userRepo repository.UserRepository, accountRepo repository.AccountRepository, refreshTokenRepo repository.RefreshTokenRepository, actionTokenRepo repository.ActionTokenRepository, loginAttemptRepo repository.LoginAttemptRepository, auditRepo repository.AuditEventRepository, sender mailer.Mailer, texter sms.Sender) AuthService {
+	return &authService{
+		userRepo:         userRepo,
+		accountRepo:      accountRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		actionTokenRepo:  actionTokenRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		auditRepo:        auditRepo,
+		mailer:           sender,
+		texter:           texter,
+	}
 }
 
+// Register creates a user and opens their first account
 func (s *authService) Register(ctx context.Context, input dtos.RegisterRequest) (*dtos.AuthResponse, error) {
 	// Check if email exists
-	existingAccount, err := s.accountRepo.FindByEmail(ctx, input.Email)
+	existingUser, err := s.userRepo.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, err
 	}
-	if existingAccount != nil {
+	if existingUser != nil {
 		return nil, ErrEmailExists
 	}
 
@@ -47,56 +100,197 @@ func (s *authService) Register(ctx context.Context, input dtos.RegisterRequest)
 		return nil, err
 	}
 
-	createAccountDTO := &dtos.CreateAccountDTO{
+	createUserDTO := &dtos.CreateUserDTO{
 		Name:        input.Name,
 		Email:       input.Email,
 		PhoneNumber: input.PhoneNumber,
 		Password:    string(hashedPassword),
-		Status:      string(models.AccountStatusActive),
+		Status:      string(models.UserStatusActive),
+		Role:        string(models.RoleCustomer),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
-	account, err := s.accountRepo.Create(ctx, createAccountDTO)
+	user, err := s.userRepo.Create(ctx, createUserDTO)
 	if err != nil {
 		return nil, err
 	}
+	recordAuditEvent(ctx, s.auditRepo, selfAuditEvent(ctx, models.AuditActionRegister, user))
 
-	// Generate JWT token using timestamp as uint
-	token, err := jwt.GenerateToken(uint(account.ID.Timestamp().Unix()))
+	account, err := openAccount(ctx, s.accountRepo, user.ID, models.AccountProductCurrent, "")
 	if err != nil {
 		return nil, err
 	}
+	event := selfAuditEvent(ctx, models.AuditActionAccountOpened, user)
+	event.TargetAccountID = &account.ID
+	event.Details = map[string]string{"product": string(account.Product)}
+	recordAuditEvent(ctx, s.auditRepo, event)
 
-	return &dtos.AuthResponse{
-		Token: token,
-		User:  account,
-	}, nil
+	// The user stays unverified until the links are followed; they can be sent again later
+	if err := s.sendVerification(ctx, user); err != nil {
+		log.Warn().Err(err).Str("user_id", user.ID.Hex()).Msg("Failed to send verification email")
+	}
+	if err := s.sendPhoneVerification(ctx, user); err != nil {
+		log.Warn().Err(err).Str("user_id", user.ID.Hex()).Msg("Failed to send phone verification")
+	}
+
+	// Start a new session for the user
+	response, err := s.issueTokens(ctx, user, primitive.NewObjectID(), primitive.NewObjectID())
+	if err != nil {
+		return nil, err
+	}
+	response.Account = account
+	return response, nil
 }
 
+// Login checks the password of a user. Failed attempts are counted per email and client IP and
+// lead to growing delays and eventually a lockout, reported as a LoginThrottledError.
 func (s *authService) Login(ctx context.Context, input dtos.LoginRequest) (*dtos.AuthResponse, error) {
-	account, err := s.accountRepo.FindByEmail(ctx, input.Email)
+	now := time.Now()
+	if err := s.checkLoginThrottle(ctx, input.Email, input.IP, now); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, err
 	}
-	if account == nil {
+	if user != nil && user.IsLocked(now) {
+		return nil, &LoginThrottledError{RetryAfter: user.LockedUntil.Sub(now)}
+	}
+
+	// Compare passwords; unknown emails count as failures too, so they cannot be told apart
+	if user == nil || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)) != nil {
+		event := newUserAuditEvent(ctx, models.AuditActionLoginFailed, nil)
+		if user != nil {
+			event.TargetUserID = &user.ID
+		}
+		event.Details = map[string]string{"email": input.Email}
+		recordAuditEvent(ctx, s.auditRepo, event)
+
+		if err := s.recordLoginFailure(ctx, user, input.Email, input.IP, now); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
 
-	// Compare passwords
-	err = bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(input.Password))
+	// The status is only revealed to callers who know the password
+	if err := userStatusError(user.Status); err != nil {
+		return nil, err
+	}
+
+	// Accounts with a second factor get a challenge instead of a session. Their failures are only
+	// forgotten once the second factor is verified, so a known password cannot reset the count of
+	// wrong codes.
+	if user.MFA.Enabled {
+		return s.mfaChallenge(ctx, user)
+	}
+	if err := s.loginAttemptRepo.Reset(ctx, loginEmailKey(input.Email)); err != nil {
+		return nil, err
+	}
+	recordAuditEvent(ctx, s.auditRepo, selfAuditEvent(ctx, models.AuditActionLogin, user))
+
+	// Start a new session for the user
+	return s.issueTokens(ctx, user, primitive.NewObjectID(), primitive.NewObjectID())
+}
+
+// Refresh exchanges a refresh token for a new token pair in the same session. Each refresh token can be
+// used once; presenting one that was already rotated revokes the whole session.
+func (s *authService) Refresh(ctx context.Context, input dtos.RefreshRequest) (*dtos.AuthResponse, error) {
+	current, err := s.refreshTokenRepo.FindByHash(ctx, hashOpaqueToken(input.RefreshToken))
 	if err != nil {
-		return nil, ErrInvalidCredentials
+		return nil, err
+	}
+	if current == nil || current.RevokedAt != nil || time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, current.FamilyID)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err := userStatusError(user.Status); err != nil {
+		return nil, err
+	}
+
+	// Retire the presented token before issuing its replacement
+	replacementID := primitive.NewObjectID()
+	used, err := s.refreshTokenRepo.MarkUsed(ctx, current.ID, replacementID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, s.revokeReusedFamily(ctx, current.FamilyID)
+	}
+
+	return s.issueTokens(ctx, user, current.FamilyID, replacementID)
+}
+
+// Logout revokes the session the refresh token belongs to, including its outstanding access tokens
+func (s *authService) Logout(ctx context.Context, input dtos.LogoutRequest) error {
+	current, err := s.refreshTokenRepo.FindByHash(ctx, hashOpaqueToken(input.RefreshToken))
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return ErrInvalidRefreshToken
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+		return err
+	}
+
+	event := newUserAuditEvent(ctx, models.AuditActionLogout, &current.UserID)
+	if event.ActorID == nil {
+		event.ActorID = &current.UserID
+	}
+	recordAuditEvent(ctx, s.auditRepo, event)
+	return nil
+}
+
+// revokeReusedFamily revokes a session whose refresh token was presented twice, since one of the
+// presenters must have stolen it
+func (s *authService) revokeReusedFamily(ctx context.Context, familyID primitive.ObjectID) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens creates an access token and a refresh token with ID refreshTokenID for the user in session familyID
+func (s *authService) issueTokens(ctx context.Context, user *models.User, familyID, refreshTokenID primitive.ObjectID) (*dtos.AuthResponse, error) {
+	refreshToken, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.refreshTokenRepo.Create(ctx, &models.RefreshToken{
+		ID:        refreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(RefreshTokenTTL),
+	}); err != nil {
+		return nil, err
 	}
 
-	// Generate JWT token using timestamp as uint
-	token, err := jwt.GenerateToken(uint(account.ID.Timestamp().Unix()))
+	token, err := jwt.GenerateToken(user.ID.Hex(), familyID.Hex(), string(models.RoleOf(user)))
 	if err != nil {
 		return nil, err
 	}
 
 	return &dtos.AuthResponse{
-		Token: token,
-		User:  account,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(jwt.AccessTokenTTL / time.Second),
+		User:         user,
 	}, nil
 }
@@ -2,22 +2,32 @@ package services
 
 import (
 	"context"
+	"net/http"
+	"strconv"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type BalanceService struct {
-	db         *mongo.Database
-	repository repository.BalanceRepository
+	txRunner    repository.TxRunner
+	repository  repository.BalanceRepository
+	accountRepo repository.AccountRepository
+	ledgerRepo  repository.LedgerRepository
+	auditRepo   repository.AuditEventRepository
 }
 
-func NewBalanceService(db *mongo.Database) *BalanceService {
+func NewBalanceService(txRunner repository.TxRunner, balanceRepo repository.BalanceRepository, accountRepo repository.AccountRepository, ledgerRepo repository.LedgerRepository, auditRepo repository.AuditEventRepository) *BalanceService {
 	return &BalanceService{
-		db:         db,
-		repository: repository.NewBalanceRepository(db),
+		txRunner:    txRunner,
+		repository:  balanceRepo,
+		accountRepo: accountRepo,
+		ledgerRepo:  ledgerRepo,
+		auditRepo:   auditRepo,
 	}
 }
 
@@ -34,11 +44,121 @@ func (s *BalanceService) GetBalances(ctx context.Context, accountID primitive.Ob
 	}
 
 	for i, balance := range balances {
-		response.Balances[i] = dtos.CurrencyBalance{
-			Currency: balance.Currency,
-			Amount:   balance.Amount,
-		}
+		response.Balances[i] = toCurrencyBalance(&balance)
 	}
 
 	return response, nil
 }
+
+// RebuildBalances recomputes an account's balance projections from its ledger postings. The sum, the
+// projections and the audit event are written in one Mongo transaction, so a movement posted meanwhile
+// conflicts with the rebuild and is not lost from the projection.
+func (s *BalanceService) RebuildBalances(ctx context.Context, accountID primitive.ObjectID) (*dtos.BalanceResponse, error) {
+	err := s.txRunner.RunInTransaction(ctx, func(sc context.Context) error {
+		sums, err := s.ledgerRepo.SumCustomerPostings(sc, accountID)
+		if err != nil {
+			return err
+		}
+
+		// Currencies that no longer have postings are reset to zero
+		balances, err := s.repository.GetBalances(sc, accountID)
+		if err != nil {
+			return err
+		}
+		before := map[string]models.Balance{}
+		for _, balance := range balances {
+			before[balance.Currency] = balance
+			if _, ok := sums[balance.Currency]; !ok {
+				sums[balance.Currency] = 0
+			}
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionBalancesRebuilt, &accountID)
+		for currency, amount := range sums {
+			if err := s.repository.SetBalance(sc, accountID, amount, currency); err != nil {
+				return err
+			}
+			event.Balances = append(event.Balances, models.BalanceChange{
+				AccountID:  accountID,
+				Currency:   currency,
+				Before:     before[currency].Amount,
+				After:      amount,
+				HeldBefore: before[currency].Held,
+				HeldAfter:  before[currency].Held,
+			})
+		}
+		return s.auditRepo.Create(sc, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetBalances(ctx, accountID)
+}
+
+// SetOverdraft sets how far a balance may go below zero and what is charged while it does. The
+// balance is created if needed, so the account must exist and the currency must be enabled.
+func (s *BalanceService) SetOverdraft(ctx context.Context, accountID primitive.ObjectID, currency string, input dtos.SetOverdraftRequest) (*dtos.CurrencyBalance, error) {
+	if _, ok := money.LookupCurrency(currency); !ok {
+		return nil, utils.ErrCurrencyNotFound
+	}
+	if !money.IsEnabled(currency) {
+		return nil, utils.ErrCurrencyDisabled
+	}
+
+	limit, err := input.Limit.Amount(currency)
+	if err != nil {
+		return nil, utils.NewError(http.StatusBadRequest, err.Error())
+	}
+	var fee money.Amount
+	if input.DailyFee != "" {
+		if fee, err = input.DailyFee.Amount(currency); err != nil {
+			return nil, utils.NewError(http.StatusBadRequest, err.Error())
+		}
+	}
+	if limit < 0 || fee < 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, utils.ErrAccountNotFound
+	}
+
+	balance, err := s.repository.SetOverdraft(ctx, accountID, currency, models.Overdraft{
+		Limit:           limit,
+		InterestRateBps: input.InterestRateBps,
+		DailyFee:        fee,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(ctx, models.AuditActionOverdraftSet, &accountID)
+	event.Details = map[string]string{
+		"currency":          currency,
+		"limit":             limit.Format(currency),
+		"interest_rate_bps": strconv.Itoa(input.InterestRateBps),
+		"daily_fee":         fee.Format(currency),
+	}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	response := toCurrencyBalance(balance)
+	return &response, nil
+}
+
+func toCurrencyBalance(balance *models.Balance) dtos.CurrencyBalance {
+	response := dtos.CurrencyBalance{
+		Currency:  balance.Currency,
+		Current:   money.NewDecimal(balance.Amount, balance.Currency),
+		Available: money.NewDecimal(balance.Available(), balance.Currency),
+	}
+	if balance.Overdraft != nil {
+		response.OverdraftLimit = money.NewDecimal(balance.OverdraftLimit(), balance.Currency)
+		response.OverdraftUsed = money.NewDecimal(balance.OverdraftUsed(), balance.Currency)
+	}
+	return response
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+)
+
+// chainPageSize is the number of transactions read at a time while walking a chain
+const chainPageSize = 500
+
+// ChainService verifies the per-account hash chains over transactions
+type ChainService interface {
+	VerifyAccount(ctx context.Context, accountID primitive.ObjectID) (*dtos.ChainVerification, error)
+	VerifyAll(ctx context.Context) ([]dtos.ChainVerification, error)
+}
+
+type chainService struct {
+	transactionRepo repository.TransactionRepository
+}
+
+func NewChainService(transactionRepo repository.TransactionRepository) ChainService {
+	return &chainService{transactionRepo: transactionRepo}
+}
+
+// VerifyAccount walks the account's chain from its first transaction, recomputing every hash, and
+// reports the first break it finds. The amount recorded as reversed on each transaction, which its hash
+// does not cover, must match the chained reversals of it.
+func (s *chainService) VerifyAccount(ctx context.Context, accountID primitive.ObjectID) (*dtos.ChainVerification, error) {
+	result := &dtos.ChainVerification{AccountID: accountID.Hex(), Valid: true}
+	recorded := map[primitive.ObjectID]*models.Transaction{}
+	reversed := map[primitive.ObjectID]money.Amount{}
+	firstReversal := map[primitive.ObjectID]*models.Transaction{}
+
+	for {
+		transactions, err := s.transactionRepo.ListChain(ctx, accountID, result.HeadSequence, chainPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range transactions {
+			transaction := &transactions[i]
+			reason := ""
+			switch {
+			case transaction.ChainSequence != result.HeadSequence+1:
+				reason = dtos.ChainBreakSequenceGap
+			case transaction.PreviousHash != result.HeadHash:
+				reason = dtos.ChainBreakPreviousHashMismatch
+			case transaction.ComputeHash() != transaction.Hash:
+				reason = dtos.ChainBreakHashMismatch
+			}
+			if reason != "" {
+				result.Valid = false
+				result.Break = &dtos.ChainBreak{
+					Sequence:      transaction.ChainSequence,
+					TransactionID: transaction.ID.Hex(),
+					Reason:        reason,
+				}
+				return result, nil
+			}
+
+			result.Verified++
+			result.HeadSequence = transaction.ChainSequence
+			result.HeadHash = transaction.Hash
+
+			if transaction.ReversedAmount != 0 {
+				recorded[transaction.ID] = transaction
+			}
+			if transaction.ReversalOf != nil {
+				reversed[*transaction.ReversalOf] += transaction.Amount
+				if _, ok := firstReversal[*transaction.ReversalOf]; !ok {
+					firstReversal[*transaction.ReversalOf] = transaction
+				}
+			}
+		}
+
+		if len(transactions) < chainPageSize {
+			break
+		}
+	}
+
+	if brk := reversedAmountBreak(recorded, reversed, firstReversal); brk != nil {
+		result.Valid = false
+		result.Break = brk
+		return result, nil
+	}
+
+	// Rows without a chain position were written around the repository, so they are never trusted
+	unchained, err := s.transactionRepo.CountUnchained(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if unchained > 0 {
+		result.Valid = false
+		result.Break = &dtos.ChainBreak{Reason: dtos.ChainBreakUnchained}
+	}
+
+	return result, nil
+}
+
+// reversedAmountBreak returns the earliest transaction whose recorded reversed amount differs from the
+// total of its chained reversals, located at the transaction itself or, when it records nothing, at its
+// first reversal
+func reversedAmountBreak(recorded map[primitive.ObjectID]*models.Transaction, reversed map[primitive.ObjectID]money.Amount, firstReversal map[primitive.ObjectID]*models.Transaction) *dtos.ChainBreak {
+	var brk *dtos.ChainBreak
+	report := func(at *models.Transaction, transactionID primitive.ObjectID) {
+		if brk == nil || at.ChainSequence < brk.Sequence {
+			brk = &dtos.ChainBreak{Sequence: at.ChainSequence, TransactionID: transactionID.Hex(), Reason: dtos.ChainBreakReversedAmountMismatch}
+		}
+	}
+
+	for id, transaction := range recorded {
+		if transaction.ReversedAmount != reversed[id] {
+			report(transaction, id)
+		}
+	}
+	for id, reversal := range firstReversal {
+		if _, ok := recorded[id]; !ok {
+			report(reversal, id)
+		}
+	}
+
+	return brk
+}
+
+// VerifyAll verifies the chain of every account that has transactions
+func (s *chainService) VerifyAll(ctx context.Context) ([]dtos.ChainVerification, error) {
+	accountIDs, err := s.transactionRepo.ListAccountIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dtos.ChainVerification, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		result, err := s.VerifyAccount(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// CurrencyService manages the currency registry: the embedded ISO 4217 table with admin overrides on top
+type CurrencyService interface {
+	ListCurrencies(ctx context.Context) *dtos.CurrencyListResponse
+	UpdateCurrency(ctx context.Context, actor *models.Principal, code string, input dtos.UpdateCurrencyRequest) (*money.Currency, error)
+	ResetCurrency(ctx context.Context, actor *models.Principal, code string) (*money.Currency, error)
+	LoadOverrides(ctx context.Context) error
+}
+
+type currencyService struct {
+	txRunner     repository.TxRunner
+	currencyRepo repository.CurrencyRepository
+	auditRepo    repository.AuditEventRepository
+}
+
+func NewCurrencyService(txRunner repository.TxRunner, currencyRepo repository.CurrencyRepository, auditRepo repository.AuditEventRepository) CurrencyService {
+	return &currencyService{
+		txRunner:     txRunner,
+		currencyRepo: currencyRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+// ListCurrencies returns every registered currency, enabled or not
+func (s *currencyService) ListCurrencies(ctx context.Context) *dtos.CurrencyListResponse {
+	return &dtos.CurrencyListResponse{Currencies: money.Currencies()}
+}
+
+// UpdateCurrency overrides a currency's precision or enablement. Amounts are stored in minor units, so the
+// precision of a currency that any balance, transaction, ledger posting, quote or limit is stored in cannot
+// change. The check and the override are written in one Mongo transaction.
+func (s *currencyService) UpdateCurrency(ctx context.Context, actor *models.Principal, code string, input dtos.UpdateCurrencyRequest) (*money.Currency, error) {
+	code = strings.ToUpper(code)
+	current, ok := money.LookupCurrency(code)
+	if !ok {
+		return nil, utils.ErrCurrencyNotFound
+	}
+
+	override := &models.CurrencyOverride{
+		Code:       code,
+		MinorUnits: input.MinorUnits,
+		Enabled:    input.Enabled,
+	}
+	if actor != nil {
+		override.UpdatedBy = &actor.UserID
+	}
+
+	err := s.txRunner.RunInTransaction(ctx, func(sc context.Context) error {
+		if input.MinorUnits != nil && *input.MinorUnits != current.MinorUnits {
+			if err := s.requireUnused(sc, code); err != nil {
+				return err
+			}
+		}
+		_, err := s.currencyRepo.UpsertOverride(sc, override)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := withActor(newAuditEvent(ctx, models.AuditActionCurrencyUpdated, nil), actor)
+	event.Details = map[string]string{"code": code}
+	if input.MinorUnits != nil {
+		event.Details["minor_units"] = strconv.Itoa(*input.MinorUnits)
+	}
+	if input.Enabled != nil {
+		event.Details["enabled"] = strconv.FormatBool(*input.Enabled)
+	}
+	return s.reload(ctx, code, event)
+}
+
+// ResetCurrency drops a currency's override, restoring its ISO 4217 values
+func (s *currencyService) ResetCurrency(ctx context.Context, actor *models.Principal, code string) (*money.Currency, error) {
+	code = strings.ToUpper(code)
+	if _, ok := money.LookupCurrency(code); !ok {
+		return nil, utils.ErrCurrencyNotFound
+	}
+
+	// The restored precision is refused on the same grounds as a changed one
+	err := s.txRunner.RunInTransaction(ctx, func(sc context.Context) error {
+		overrides, err := s.currencyRepo.ListOverrides(sc)
+		if err != nil {
+			return err
+		}
+		for _, override := range overrides {
+			if override.Code == code && override.MinorUnits != nil {
+				if err := s.requireUnused(sc, code); err != nil {
+					return err
+				}
+			}
+		}
+
+		_, err = s.currencyRepo.DeleteOverride(sc, code)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := withActor(newAuditEvent(ctx, models.AuditActionCurrencyReset, nil), actor)
+	event.Details = map[string]string{"code": code}
+	return s.reload(ctx, code, event)
+}
+
+// requireUnused returns ErrCurrencyInUse if any stored amount is in the currency's minor units
+func (s *currencyService) requireUnused(ctx context.Context, code string) error {
+	inUse, err := s.currencyRepo.IsInUse(ctx, code)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return utils.ErrCurrencyInUse
+	}
+	return nil
+}
+
+// LoadOverrides applies the stored overrides to the registry. It runs at startup and periodically, so
+// changes made through another replica are picked up.
+func (s *currencyService) LoadOverrides(ctx context.Context) error {
+	overrides, err := s.currencyRepo.ListOverrides(ctx)
+	if err != nil {
+		return err
+	}
+
+	registryOverrides := make([]money.CurrencyOverride, len(overrides))
+	for i, override := range overrides {
+		registryOverrides[i] = money.CurrencyOverride{
+			Code:       override.Code,
+			MinorUnits: override.MinorUnits,
+			Enabled:    override.Enabled,
+		}
+	}
+	money.SetOverrides(registryOverrides)
+	return nil
+}
+
+// reload refreshes the registry after a change, records the change and returns the currency's new entry
+func (s *currencyService) reload(ctx context.Context, code string, event *models.AuditEvent) (*money.Currency, error) {
+	if err := s.LoadOverrides(ctx); err != nil {
+		return nil, err
+	}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	currency, _ := money.LookupCurrency(code)
+	return &currency, nil
+}
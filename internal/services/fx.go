@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/fx"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+var (
+	// FXQuoteTTL is how long a quote's price is locked
+	FXQuoteTTL = utils.GetDurationEnv("FX_QUOTE_TTL", 30*time.Second)
+
+	// FXSpreadBasisPoints is the margin taken from the mid-market rate, in hundredths of a percent
+	FXSpreadBasisPoints = utils.GetIntEnv("FX_SPREAD_BPS", 50)
+)
+
+// fxRateDecimals is the precision rates are displayed with
+const fxRateDecimals = 8
+
+// FXRateProvider supplies the mid-market rates quotes are priced from
+type FXRateProvider = fx.RateProvider
+
+type FXService interface {
+	CreateQuote(ctx context.Context, principal *models.Principal, accountID primitive.ObjectID, sellCurrency, buyCurrency string, amount money.Amount) (*dtos.FXQuoteResponse, error)
+	GetQuote(ctx context.Context, principal *models.Principal, quoteID primitive.ObjectID) (*models.FXQuote, error)
+	Convert(ctx context.Context, principal *models.Principal, quoteID primitive.ObjectID) (*dtos.FXConversionResponse, error)
+}
+
+type fxService struct {
+	transactions TransactionService
+	quoteRepo    repository.FXQuoteRepository
+	rates        FXRateProvider
+}
+
+func NewFXService(transactions TransactionService, quoteRepo repository.FXQuoteRepository, rates FXRateProvider) FXService {
+	return &fxService{
+		transactions: transactions,
+		quoteRepo:    quoteRepo,
+		rates:        rates,
+	}
+}
+
+// CreateQuote prices selling amount of the account's sellCurrency balance for buyCurrency and locks the
+// price for FXQuoteTTL. The balance is only checked when the quote is executed.
+func (s *fxService) CreateQuote(ctx context.Context, principal *models.Principal, accountID primitive.ObjectID, sellCurrency, buyCurrency string, amount money.Amount) (*dtos.FXQuoteResponse, error) {
+	if principal == nil {
+		return nil, utils.ErrInvalidToken
+	}
+	if amount <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+	sellCurrency = strings.ToUpper(sellCurrency)
+	buyCurrency = strings.ToUpper(buyCurrency)
+	if sellCurrency == buyCurrency {
+		return nil, utils.ErrSameCurrency
+	}
+	// Funds may be converted out of a disabled currency but not into one
+	if !money.IsEnabled(buyCurrency) {
+		return nil, utils.ErrCurrencyDisabled
+	}
+
+	mid, err := s.rates.Rate(ctx, sellCurrency, buyCurrency)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateUnavailable) {
+			return nil, utils.ErrUnsupportedCurrencyPair
+		}
+		return nil, err
+	}
+
+	rate := customerRate(mid, FXSpreadBasisPoints)
+	gross, err := convertAmount(amount, sellCurrency, buyCurrency, mid)
+	if err != nil {
+		return nil, err
+	}
+	bought, err := convertAmount(amount, sellCurrency, buyCurrency, rate)
+	if err != nil {
+		return nil, err
+	}
+	if bought <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+
+	now := time.Now()
+	quote := &models.FXQuote{
+		AccountID:    accountID,
+		UserID:       principal.UserID,
+		SellCurrency: sellCurrency,
+		BuyCurrency:  buyCurrency,
+		SellAmount:   amount,
+		BuyAmount:    bought,
+		Spread:       gross - bought,
+		MidRate:      formatRate(mid),
+		Rate:         formatRate(rate),
+		ExpiresAt:    now.Add(FXQuoteTTL),
+		CreatedAt:    now,
+	}
+	if err := s.quoteRepo.Create(ctx, quote); err != nil {
+		return nil, err
+	}
+
+	return toFXQuoteResponse(quote), nil
+}
+
+// GetQuote returns a quote requested by the principal. Other users' quotes are reported as not found.
+func (s *fxService) GetQuote(ctx context.Context, principal *models.Principal, quoteID primitive.ObjectID) (*models.FXQuote, error) {
+	if principal == nil {
+		return nil, utils.ErrInvalidToken
+	}
+
+	quote, err := s.quoteRepo.FindByID(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	if quote == nil || quote.UserID != principal.UserID {
+		return nil, utils.ErrQuoteNotFound
+	}
+	return quote, nil
+}
+
+// Convert executes a quote. A quote can be executed once, before it expires.
+func (s *fxService) Convert(ctx context.Context, principal *models.Principal, quoteID primitive.ObjectID) (*dtos.FXConversionResponse, error) {
+	quote, err := s.GetQuote(ctx, principal, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.UsedAt != nil {
+		return nil, utils.ErrQuoteUsed
+	}
+	if !time.Now().Before(quote.ExpiresAt) {
+		return nil, utils.ErrQuoteExpired
+	}
+
+	// Marking the quote used only matches an unused, unexpired quote, so of two concurrent
+	// conversions exactly one moves money
+	return s.transactions.Convert(ctx, quote, func(ctx context.Context, conversionID primitive.ObjectID) error {
+		used, err := s.quoteRepo.MarkUsed(ctx, quote.ID, conversionID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return utils.ErrQuoteUsed
+		}
+		return nil
+	})
+}
+
+// Convert executes quote in a single Mongo transaction: the sell amount is debited from the account,
+// the buy amount credited and the spread posted to the FX revenue ledger account. claim marks the quote
+// used inside the same transaction.
+func (t *transactionService) Convert(ctx context.Context, quote *models.FXQuote, claim func(ctx context.Context, conversionID primitive.ObjectID) error) (*dtos.FXConversionResponse, error) {
+	conversionID := primitive.NewObjectID()
+
+	var debitTx, creditTx *models.Transaction
+	err := t.runInTransaction(ctx, func(sc context.Context) error {
+		if err := claim(sc, conversionID); err != nil {
+			return err
+		}
+
+		accountID := quote.AccountID
+		if err := t.requireActiveAccounts(sc, accountID); err != nil {
+			return err
+		}
+
+		balances, err := t.snapshotBalances(sc, quote.SellCurrency, accountID)
+		if err != nil {
+			return err
+		}
+		bought, err := t.snapshotBalances(sc, quote.BuyCurrency, accountID)
+		if err != nil {
+			return err
+		}
+		balances = append(balances, bought...)
+
+		// The FX position account takes the sold currency and delivers the bought one. It delivers the
+		// mid-market amount; the share kept by the spread is revenue.
+		description := fmt.Sprintf("FX %s to %s", quote.SellCurrency, quote.BuyCurrency)
+		entry := &models.JournalEntry{
+			Kind:        models.JournalEntryKindFXConversion,
+			Description: description,
+			Postings: []models.Posting{
+				customerDebit(accountID, quote.SellAmount, quote.SellCurrency),
+				credit(models.LedgerAccountFXPosition, nil, quote.SellAmount, quote.SellCurrency),
+				debit(models.LedgerAccountFXPosition, nil, quote.BuyAmount+quote.Spread, quote.BuyCurrency),
+				customerCredit(accountID, quote.BuyAmount, quote.BuyCurrency),
+			},
+		}
+		if quote.Spread > 0 {
+			entry.Postings = append(entry.Postings, credit(models.LedgerAccountFXRevenue, nil, quote.Spread, quote.BuyCurrency))
+		}
+		if err := t.postJournalEntry(sc, entry); err != nil {
+			return err
+		}
+
+		debitTx, err = t.transactionRepo.CreateTransaction(sc, &dtos.CreateTransactionDTO{
+			AccountID:      accountID,
+			Amount:         quote.SellAmount,
+			Currency:       quote.SellCurrency,
+			Type:           string(models.TransactionTypeDebit),
+			Description:    description,
+			ConversionID:   &conversionID,
+			JournalEntryID: entry.ID,
+			APIKeyID:       apiKeyIDFromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		creditTx, err = t.transactionRepo.CreateTransaction(sc, &dtos.CreateTransactionDTO{
+			AccountID:      accountID,
+			Amount:         quote.BuyAmount,
+			Currency:       quote.BuyCurrency,
+			Type:           string(models.TransactionTypeCredit),
+			Description:    description,
+			ConversionID:   &conversionID,
+			JournalEntryID: entry.ID,
+			APIKeyID:       apiKeyIDFromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionFXConversion, &accountID)
+		event.TransactionID = &debitTx.ID
+		event.Details = map[string]string{
+			"quote_id":              quote.ID.Hex(),
+			"conversion_id":         conversionID.Hex(),
+			"credit_transaction_id": creditTx.ID.Hex(),
+			"rate":                  quote.Rate,
+			"spread":                quote.Spread.Format(quote.BuyCurrency),
+		}
+		return t.recordMoneyEvent(sc, event, balances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.FXConversionResponse{
+		ConversionID:        conversionID.Hex(),
+		QuoteID:             quote.ID.Hex(),
+		DebitTransactionID:  debitTx.ID.Hex(),
+		CreditTransactionID: creditTx.ID.Hex(),
+		SellAmount:          money.NewDecimal(quote.SellAmount, quote.SellCurrency),
+		SellCurrency:        quote.SellCurrency,
+		BuyAmount:           money.NewDecimal(quote.BuyAmount, quote.BuyCurrency),
+		BuyCurrency:         quote.BuyCurrency,
+	}, nil
+}
+
+// customerRate takes the spread off the mid-market rate
+func customerRate(mid *big.Rat, spreadBasisPoints int) *big.Rat {
+	margin := big.NewRat(int64(10000-spreadBasisPoints), 10000)
+	return new(big.Rat).Mul(mid, margin)
+}
+
+// convertAmount converts amount at rate, rounding down to a minor unit of the target currency so
+// the customer is never credited more than the rate allows
+func convertAmount(amount money.Amount, from, to string, rate *big.Rat) (money.Amount, error) {
+	scale := new(big.Rat).SetFrac(
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(money.MinorUnits(to))), nil),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(money.MinorUnits(from))), nil),
+	)
+	converted := new(big.Rat).SetInt64(int64(amount))
+	converted.Mul(converted, rate).Mul(converted, scale)
+
+	minor := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !minor.IsInt64() {
+		return 0, utils.NewError(http.StatusBadRequest, money.ErrOverflow.Error())
+	}
+	return money.Amount(minor.Int64()), nil
+}
+
+// formatRate renders a rate as a decimal without trailing zeros
+func formatRate(rate *big.Rat) string {
+	formatted := strings.TrimRight(rate.FloatString(fxRateDecimals), "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+func toFXQuoteResponse(quote *models.FXQuote) *dtos.FXQuoteResponse {
+	return &dtos.FXQuoteResponse{
+		QuoteID:      quote.ID.Hex(),
+		AccountID:    quote.AccountID.Hex(),
+		SellCurrency: quote.SellCurrency,
+		BuyCurrency:  quote.BuyCurrency,
+		SellAmount:   money.NewDecimal(quote.SellAmount, quote.SellCurrency),
+		BuyAmount:    money.NewDecimal(quote.BuyAmount, quote.BuyCurrency),
+		Rate:         quote.Rate,
+		ExpiresAt:    quote.ExpiresAt,
+	}
+}
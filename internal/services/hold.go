@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// Hold lifetime limits
+const (
+	DefaultHoldTTL  = 7 * 24 * time.Hour
+	holdExpiryBatch = 100
+)
+
+// Authorize places a hold that reserves amount from the available balance. The hold is recorded
+// as a pending debit transaction and does not touch the ledger until it is captured.
+func (s *transactionService) Authorize(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, description string, ttl time.Duration) (*dtos.HoldResponse, error) {
+	if amount <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+	if ttl <= 0 {
+		ttl = DefaultHoldTTL
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	var hold *models.Transaction
+	err := s.runInTransaction(ctx, func(sc context.Context) error {
+		if err := s.requireActiveAccounts(sc, accountID); err != nil {
+			return err
+		}
+
+		// A hold counts towards withdrawal limits when it is placed, not when it is captured
+		if err := s.checkLimits(sc, accountID, currency, models.LimitDirectionWithdrawal, amount); err != nil {
+			return err
+		}
+
+		balances, err := s.snapshotBalances(sc, currency, accountID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.balanceRepo.PlaceHold(sc, accountID, amount, currency); err != nil {
+			return err
+		}
+
+		hold, err = s.transactionRepo.CreateTransaction(sc, &dtos.CreateTransactionDTO{
+			AccountID:   accountID,
+			Amount:      amount,
+			Currency:    currency,
+			Type:        string(models.TransactionTypeDebit),
+			Description: description,
+			Status:      string(models.TransactionStatusPending),
+			HeldAmount:  amount,
+			ExpiresAt:   &expiresAt,
+			APIKeyID:    apiKeyIDFromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionHoldPlaced, &accountID)
+		event.TransactionID = &hold.ID
+		return s.recordMoneyEvent(sc, event, balances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toHoldResponse(hold), nil
+}
+
+// Capture settles a pending hold. A nil amount captures the full hold; a smaller amount
+// captures partially and releases the remainder.
+func (s *transactionService) Capture(ctx context.Context, holdID primitive.ObjectID, amount *money.Amount) (*dtos.HoldResponse, error) {
+	var hold *models.Transaction
+	err := s.runInTransaction(ctx, func(sc context.Context) error {
+		var err error
+		hold, err = s.findPendingHold(sc, holdID)
+		if err != nil {
+			return err
+		}
+		// An expired hold is voided by the expiry worker and can no longer be captured
+		if hold.ExpiresAt != nil && !time.Now().Before(*hold.ExpiresAt) {
+			return utils.ErrHoldExpired
+		}
+
+		captured := hold.HeldAmount
+		if amount != nil {
+			captured = *amount
+		}
+		if captured <= 0 {
+			return utils.ErrInvalidAmount
+		}
+		if captured > hold.HeldAmount {
+			return utils.ErrCaptureExceedsHold
+		}
+
+		if err := s.requireActiveAccounts(sc, hold.AccountID); err != nil {
+			return err
+		}
+
+		balances, err := s.snapshotBalances(sc, hold.Currency, hold.AccountID)
+		if err != nil {
+			return err
+		}
+
+		// Release the full reservation, then debit what was captured
+		if err := s.balanceRepo.ReleaseHold(sc, hold.AccountID, hold.HeldAmount, hold.Currency); err != nil {
+			return err
+		}
+
+		entry := &models.JournalEntry{
+			Kind:        models.JournalEntryKindHoldCapture,
+			Description: hold.Description,
+			Postings: []models.Posting{
+				customerDebit(hold.AccountID, captured, hold.Currency),
+				credit(models.LedgerAccountCashOut, nil, captured, hold.Currency),
+			},
+		}
+		if err := s.postJournalEntry(sc, entry); err != nil {
+			return err
+		}
+
+		if err := s.resolveHold(sc, hold, models.TransactionStatusCompleted, captured, entry.ID); err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionHoldCaptured, &hold.AccountID)
+		event.TransactionID = &hold.ID
+		return s.recordMoneyEvent(sc, event, balances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toHoldResponse(hold), nil
+}
+
+// Void cancels a pending hold and releases the reserved funds
+func (s *transactionService) Void(ctx context.Context, holdID primitive.ObjectID) (*dtos.HoldResponse, error) {
+	var hold *models.Transaction
+	err := s.runInTransaction(ctx, func(sc context.Context) error {
+		var err error
+		hold, err = s.findPendingHold(sc, holdID)
+		if err != nil {
+			return err
+		}
+
+		balances, err := s.snapshotBalances(sc, hold.Currency, hold.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.balanceRepo.ReleaseHold(sc, hold.AccountID, hold.HeldAmount, hold.Currency); err != nil {
+			return err
+		}
+
+		if err := s.resolveHold(sc, hold, models.TransactionStatusCancelled, hold.Amount, primitive.NilObjectID); err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionHoldVoided, &hold.AccountID)
+		event.TransactionID = &hold.ID
+		return s.recordMoneyEvent(sc, event, balances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toHoldResponse(hold), nil
+}
+
+// ExpireHolds voids every pending hold whose expiry has passed and returns how many were voided
+func (s *transactionService) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	for {
+		holds, err := s.transactionRepo.FindExpiredHolds(ctx, now, holdExpiryBatch)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, hold := range holds {
+			if _, err := s.Void(ctx, hold.ID); err != nil {
+				// A hold captured or voided concurrently is no longer pending
+				if err == utils.ErrHoldNotPending {
+					continue
+				}
+				log.Error().Err(err).Str("transaction_id", hold.ID.Hex()).Msg("Failed to expire hold")
+				return expired, err
+			}
+			expired++
+		}
+
+		if len(holds) < holdExpiryBatch {
+			return expired, nil
+		}
+	}
+}
+
+func (s *transactionService) findPendingHold(ctx context.Context, holdID primitive.ObjectID) (*models.Transaction, error) {
+	hold, err := s.transactionRepo.FindByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold == nil || hold.HeldAmount == 0 {
+		return nil, utils.ErrHoldNotFound
+	}
+	if hold.Status != models.TransactionStatusPending {
+		return nil, utils.ErrHoldNotPending
+	}
+	return hold, nil
+}
+
+func (s *transactionService) resolveHold(ctx context.Context, hold *models.Transaction, status models.TransactionStatus, amount money.Amount, journalEntryID primitive.ObjectID) error {
+	resolved, err := s.transactionRepo.ResolveHold(ctx, hold.ID, status, amount, journalEntryID)
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		return utils.ErrHoldNotPending
+	}
+
+	hold.Status = status
+	hold.Amount = amount
+	hold.ExpiresAt = nil
+	return nil
+}
+
+// runInTransaction runs fn inside a Mongo transaction, committing on success and aborting on error
+func (s *transactionService) runInTransaction(ctx context.Context, fn func(sc context.Context) error) error {
+	return s.txRunner.RunInTransaction(ctx, fn)
+}
+
+func toHoldResponse(hold *models.Transaction) *dtos.HoldResponse {
+	return &dtos.HoldResponse{
+		TransactionID: hold.ID.Hex(),
+		Status:        string(hold.Status),
+		HeldAmount:    money.NewDecimal(hold.HeldAmount, hold.Currency),
+		Amount:        money.NewDecimal(hold.Amount, hold.Currency),
+		Currency:      hold.Currency,
+		ExpiresAt:     hold.ExpiresAt,
+	}
+}
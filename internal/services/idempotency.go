@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// Idempotent operations
+const (
+	IdempotencyOperationDeposit  = "deposit"
+	IdempotencyOperationWithdraw = "withdraw"
+)
+
+// idempotencyHash fingerprints the parsed request so that a replay can be compared to the original
+func idempotencyHash(operation string, accountID primitive.ObjectID, amount money.Amount, currency string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%s", operation, accountID.Hex(), amount, currency)))
+	return hex.EncodeToString(sum[:])
+}
+
+// findIdempotentResult returns the transaction ID stored for key. found is false when the key is unused.
+func (s *transactionService) findIdempotentResult(ctx context.Context, accountID primitive.ObjectID, key, requestHash string) (transactionID string, found bool, err error) {
+	record, err := s.idempotencyRepo.Find(ctx, accountID, key)
+	if err != nil || record == nil {
+		return "", false, err
+	}
+
+	if record.RequestHash != requestHash {
+		return "", true, utils.ErrIdempotencyKeyReused
+	}
+	if record.TransactionID.IsZero() {
+		return "", true, utils.ErrIdempotencyKeyInProgress
+	}
+
+	return record.TransactionID.Hex(), true, nil
+}
+
+// claimIdempotencyKey inserts the key inside the caller's session before any balance is touched,
+// so a concurrent request with the same key conflicts and aborts
+func (s *transactionService) claimIdempotencyKey(ctx context.Context, accountID primitive.ObjectID, key, operation, requestHash string) (*models.IdempotencyKey, error) {
+	now := time.Now()
+	record := &models.IdempotencyKey{
+		AccountID:   accountID,
+		Key:         key,
+		Operation:   operation,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(models.IdempotencyKeyTTL),
+	}
+
+	if err := s.idempotencyRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// isIdempotencyConflict reports whether err was caused by another request claiming the same key. Other
+// write conflicts are retried by the transaction runner and never reach here as conflicts.
+func isIdempotencyConflict(err error) bool {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return false
+	}
+
+	for _, e := range writeErr.WriteErrors {
+		if mongo.IsDuplicateKeyError(e) && strings.Contains(e.Message, models.IdempotencyKeyIndex) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// ErrUnbalancedEntry is returned when a journal entry's debits and credits differ
+var ErrUnbalancedEntry = utils.NewError(http.StatusInternalServerError, "journal entry is not balanced")
+
+// debit builds a debit posting. accountID is nil for system ledger accounts.
+func debit(ledgerAccount string, accountID *primitive.ObjectID, amount money.Amount, currency string) models.Posting {
+	return models.Posting{
+		LedgerAccount: ledgerAccount,
+		AccountID:     accountID,
+		Direction:     models.PostingDirectionDebit,
+		Amount:        amount,
+		Currency:      currency,
+	}
+}
+
+// credit builds a credit posting. accountID is nil for system ledger accounts.
+func credit(ledgerAccount string, accountID *primitive.ObjectID, amount money.Amount, currency string) models.Posting {
+	return models.Posting{
+		LedgerAccount: ledgerAccount,
+		AccountID:     accountID,
+		Direction:     models.PostingDirectionCredit,
+		Amount:        amount,
+		Currency:      currency,
+	}
+}
+
+// customerDebit debits a customer's ledger account
+func customerDebit(accountID primitive.ObjectID, amount money.Amount, currency string) models.Posting {
+	return debit(models.CustomerLedgerAccount(accountID), &accountID, amount, currency)
+}
+
+// customerCredit credits a customer's ledger account
+func customerCredit(accountID primitive.ObjectID, amount money.Amount, currency string) models.Posting {
+	return credit(models.CustomerLedgerAccount(accountID), &accountID, amount, currency)
+}
+
+// validateJournalEntry checks that every posting is positive and that debits equal credits per currency
+func validateJournalEntry(entry *models.JournalEntry) error {
+	if len(entry.Postings) < 2 {
+		return ErrUnbalancedEntry
+	}
+
+	net := map[string]money.Amount{}
+	for _, posting := range entry.Postings {
+		if posting.Amount <= 0 {
+			return utils.ErrInvalidAmount
+		}
+		switch posting.Direction {
+		case models.PostingDirectionDebit:
+			net[posting.Currency] += posting.Amount
+		case models.PostingDirectionCredit:
+			net[posting.Currency] -= posting.Amount
+		default:
+			return ErrUnbalancedEntry
+		}
+	}
+
+	for _, amount := range net {
+		if amount != 0 {
+			return ErrUnbalancedEntry
+		}
+	}
+
+	return nil
+}
+
+// postJournalEntry records a balanced entry and applies its customer postings to the balance
+// projections. It must run inside the caller's Mongo transaction. Debits are applied first so
+// an insufficient balance aborts before anything is credited.
+func (s *transactionService) postJournalEntry(ctx context.Context, entry *models.JournalEntry) error {
+	return s.applyJournalEntry(ctx, entry, s.balanceRepo.CheckAndDeductBalance)
+}
+
+// postChargeEntry records a balanced entry whose customer debits are charges the bank is owed. They are
+// applied even past the available balance and overdraft limit.
+func (s *transactionService) postChargeEntry(ctx context.Context, entry *models.JournalEntry) error {
+	return s.applyJournalEntry(ctx, entry, func(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
+		return s.balanceRepo.UpdateBalance(ctx, accountID, -amount, currency)
+	})
+}
+
+// applyJournalEntry validates and records entry, applying customer debits with deduct and then customer
+// credits to the balance projections
+func (s *transactionService) applyJournalEntry(ctx context.Context, entry *models.JournalEntry, deduct func(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error) error {
+	if err := validateJournalEntry(entry); err != nil {
+		return err
+	}
+
+	for _, direction := range []models.PostingDirection{models.PostingDirectionDebit, models.PostingDirectionCredit} {
+		for _, posting := range entry.Postings {
+			if posting.AccountID == nil || posting.Direction != direction {
+				continue
+			}
+
+			var err error
+			if direction == models.PostingDirectionDebit {
+				err = deduct(ctx, *posting.AccountID, posting.Amount, posting.Currency)
+			} else {
+				err = s.balanceRepo.UpdateBalance(ctx, *posting.AccountID, posting.Amount, posting.Currency)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.ledgerRepo.CreateEntry(ctx, entry)
+}
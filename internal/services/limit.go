@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// limitDescriptions name each limit in limit_exceeded messages
+var limitDescriptions = map[models.LimitName]string{
+	models.LimitMinPerTransaction: "minimum per transaction",
+	models.LimitMaxPerTransaction: "maximum per transaction",
+	models.LimitDailyCount:        "daily transaction count",
+	models.LimitDailyAmount:       "daily amount",
+	models.LimitMonthlyCount:      "monthly transaction count",
+	models.LimitMonthlyAmount:     "monthly amount",
+}
+
+// checkLimits rejects moving amount in direction when it would break the limit policy of the account's
+// tier. It must run inside the caller's Mongo transaction, before the movement's transaction is created.
+// Two movements on one account append to the same hash chain, so concurrent movements cannot both pass
+// on a stale history.
+func (s *transactionService) checkLimits(ctx context.Context, accountID primitive.ObjectID, currency string, direction models.LimitDirection, amount money.Amount) error {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return utils.ErrAccountNotFound
+	}
+
+	policy, err := s.limitRepo.Find(ctx, account.LimitTier(), currency, direction)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	now := time.Now()
+	var usage *models.LimitUsage
+	if policy.HasWindowLimits() {
+		dayStart, monthStart := models.LimitWindows(now)
+		if usage, err = s.transactionRepo.SumLimitUsage(ctx, accountID, currency, direction, dayStart, monthStart); err != nil {
+			return err
+		}
+	}
+
+	if breach := policy.Check(usage, amount); breach != nil {
+		return limitExceededError(policy, breach, now)
+	}
+	return nil
+}
+
+// limitExceededError builds the limit_exceeded error returned for breach
+func limitExceededError(policy *models.LimitPolicy, breach *models.LimitBreach, now time.Time) *utils.CustomError {
+	format := func(value int64) string {
+		if breach.Limit.IsCount() {
+			return strconv.FormatInt(value, 10)
+		}
+		return money.Amount(value).Format(policy.Currency)
+	}
+
+	details := dtos.LimitExceededDetails{
+		Limit:     string(breach.Limit),
+		Direction: string(policy.Direction),
+		Currency:  policy.Currency,
+		Tier:      string(policy.Tier),
+		Allowed:   format(breach.Allowed),
+		ResetsAt:  breach.Limit.ResetsAt(now),
+	}
+	if details.ResetsAt != nil {
+		details.Used = format(breach.Used)
+	}
+
+	message := fmt.Sprintf("%s exceeds the %s limit", policy.Direction, limitDescriptions[breach.Limit])
+	if breach.Limit == models.LimitMinPerTransaction {
+		message = fmt.Sprintf("%s is below the %s", policy.Direction, limitDescriptions[breach.Limit])
+	}
+
+	return &utils.CustomError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: message,
+		Reason:  utils.ReasonLimitExceeded,
+		Details: details,
+	}
+}
+
+// LimitService manages the velocity limit policies enforced on deposits and withdrawals
+type LimitService interface {
+	ListPolicies(ctx context.Context) (*dtos.LimitPolicyListResponse, error)
+	SetPolicy(ctx context.Context, actor *models.Principal, tier, currency, direction string, input dtos.SetLimitPolicyRequest) (*dtos.LimitPolicyResponse, error)
+	DeletePolicy(ctx context.Context, actor *models.Principal, tier, currency, direction string) error
+}
+
+type limitService struct {
+	limitRepo repository.LimitPolicyRepository
+	auditRepo repository.AuditEventRepository
+}
+
+func NewLimitService(limitRepo repository.LimitPolicyRepository, auditRepo repository.AuditEventRepository) LimitService {
+	return &limitService{
+		limitRepo: limitRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *limitService) ListPolicies(ctx context.Context) (*dtos.LimitPolicyListResponse, error) {
+	policies, err := s.limitRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.LimitPolicyListResponse{Policies: make([]dtos.LimitPolicyResponse, len(policies))}
+	for i := range policies {
+		response.Policies[i] = toLimitPolicyResponse(&policies[i])
+	}
+	return response, nil
+}
+
+// SetPolicy replaces the limits of a tier, currency and direction
+func (s *limitService) SetPolicy(ctx context.Context, actor *models.Principal, tier, currency, direction string, input dtos.SetLimitPolicyRequest) (*dtos.LimitPolicyResponse, error) {
+	policy, err := limitPolicyKey(tier, currency, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := []struct {
+		value  money.Decimal
+		target *money.Amount
+	}{
+		{input.MinPerTransaction, &policy.MinPerTransaction},
+		{input.MaxPerTransaction, &policy.MaxPerTransaction},
+		{input.DailyAmount, &policy.DailyAmount},
+		{input.MonthlyAmount, &policy.MonthlyAmount},
+	}
+	for _, amount := range amounts {
+		if amount.value == "" {
+			continue
+		}
+		parsed, err := amount.value.Amount(policy.Currency)
+		if err != nil {
+			return nil, utils.NewError(http.StatusBadRequest, err.Error())
+		}
+		if parsed < 0 {
+			return nil, utils.ErrInvalidAmount
+		}
+		*amount.target = parsed
+	}
+	if policy.MaxPerTransaction > 0 && policy.MinPerTransaction > policy.MaxPerTransaction {
+		return nil, utils.ErrInvalidLimitPolicy
+	}
+	policy.DailyCount = input.DailyCount
+	policy.MonthlyCount = input.MonthlyCount
+	if actor != nil {
+		policy.UpdatedBy = &actor.UserID
+	}
+
+	updated, err := s.limitRepo.Upsert(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toLimitPolicyResponse(updated)
+	event := withActor(newAuditEvent(ctx, models.AuditActionLimitPolicySet, nil), actor)
+	event.Details = map[string]string{
+		"tier":                string(updated.Tier),
+		"currency":            updated.Currency,
+		"direction":           string(updated.Direction),
+		"min_per_transaction": string(response.MinPerTransaction),
+		"max_per_transaction": string(response.MaxPerTransaction),
+		"daily_count":         strconv.FormatInt(updated.DailyCount, 10),
+		"daily_amount":        string(response.DailyAmount),
+		"monthly_count":       strconv.FormatInt(updated.MonthlyCount, 10),
+		"monthly_amount":      string(response.MonthlyAmount),
+	}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	return &response, nil
+}
+
+// DeletePolicy removes every limit of a tier, currency and direction
+func (s *limitService) DeletePolicy(ctx context.Context, actor *models.Principal, tier, currency, direction string) error {
+	key, err := limitPolicyKey(tier, currency, direction)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.limitRepo.Delete(ctx, key.Tier, key.Currency, key.Direction)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return utils.ErrLimitPolicyNotFound
+	}
+
+	event := withActor(newAuditEvent(ctx, models.AuditActionLimitPolicyDeleted, nil), actor)
+	event.Details = map[string]string{
+		"tier":      string(key.Tier),
+		"currency":  key.Currency,
+		"direction": string(key.Direction),
+	}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	return nil
+}
+
+// limitPolicyKey validates the path of a policy and returns an empty policy for it
+func limitPolicyKey(tier, currency, direction string) (*models.LimitPolicy, error) {
+	policy := &models.LimitPolicy{
+		Tier:      models.AccountTier(strings.ToLower(tier)),
+		Currency:  strings.ToUpper(currency),
+		Direction: models.LimitDirection(strings.ToLower(direction)),
+	}
+	if !models.IsAccountTier(policy.Tier) {
+		return nil, utils.ErrInvalidAccountTier
+	}
+	if _, ok := money.LookupCurrency(policy.Currency); !ok {
+		return nil, utils.ErrCurrencyNotFound
+	}
+	if !models.IsLimitDirection(policy.Direction) {
+		return nil, utils.ErrInvalidLimitDirection
+	}
+	return policy, nil
+}
+
+func toLimitPolicyResponse(policy *models.LimitPolicy) dtos.LimitPolicyResponse {
+	decimal := func(amount money.Amount) money.Decimal {
+		if amount == 0 {
+			return ""
+		}
+		return money.NewDecimal(amount, policy.Currency)
+	}
+
+	return dtos.LimitPolicyResponse{
+		Tier:              string(policy.Tier),
+		Currency:          policy.Currency,
+		Direction:         string(policy.Direction),
+		MinPerTransaction: decimal(policy.MinPerTransaction),
+		MaxPerTransaction: decimal(policy.MaxPerTransaction),
+		DailyCount:        policy.DailyCount,
+		DailyAmount:       decimal(policy.DailyAmount),
+		MonthlyCount:      policy.MonthlyCount,
+		MonthlyAmount:     decimal(policy.MonthlyAmount),
+		UpdatedAt:         policy.UpdatedAt,
+	}
+}
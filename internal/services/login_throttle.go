@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+var (
+	// LoginMaxFailures is how many failed logins for one email lock the user out
+	LoginMaxFailures = utils.GetIntEnv("LOGIN_MAX_FAILURES", 5)
+
+	// LoginIPMaxFailures is how many failed logins from one client IP block that IP
+	LoginIPMaxFailures = utils.GetIntEnv("LOGIN_IP_MAX_FAILURES", 20)
+
+	// LoginFailureWindow is how long failures are remembered after the latest one
+	LoginFailureWindow = utils.GetDurationEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute)
+
+	// LoginLockoutDuration is how long a lockout lasts
+	LoginLockoutDuration = utils.GetDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+
+	// LoginDelayBase is the wait imposed after the first failure for an email; it doubles with every further failure
+	LoginDelayBase = utils.GetDurationEnv("LOGIN_DELAY_BASE", time.Second)
+)
+
+// LoginThrottledError is returned when a login is refused because of earlier failures
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %s", e.RetryAfter)
+}
+
+// loginEmailKey and loginIPKey name the failure counters of an email and a client IP
+func loginEmailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// checkLoginThrottle refuses a login while the email or the client IP is locked out, or while the
+// progressive delay after the latest failure for the email has not passed
+func (s *authService) checkLoginThrottle(ctx context.Context, email, ip string, now time.Time) error {
+	retryAt := now
+
+	attempt, err := s.loginAttemptRepo.Get(ctx, loginEmailKey(email), now)
+	if err != nil {
+		return err
+	}
+	if attempt != nil {
+		delay := LoginDelayBase << (attempt.Failures - 1)
+		if delay <= 0 || delay > LoginLockoutDuration {
+			delay = LoginLockoutDuration
+		}
+		retryAt = latest(retryAt, attempt.LastFailureAt.Add(delay))
+		if attempt.LockedUntil != nil {
+			retryAt = latest(retryAt, *attempt.LockedUntil)
+		}
+	}
+
+	if ip != "" {
+		attempt, err := s.loginAttemptRepo.Get(ctx, loginIPKey(ip), now)
+		if err != nil {
+			return err
+		}
+		if attempt != nil && attempt.LockedUntil != nil {
+			retryAt = latest(retryAt, *attempt.LockedUntil)
+		}
+	}
+
+	if retryAt.After(now) {
+		return &LoginThrottledError{RetryAfter: retryAt.Sub(now)}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login against the email and the client IP, and locks whichever
+// reached its limit. Reaching the limit for an email also locks out the user it belongs to, if any.
+func (s *authService) recordLoginFailure(ctx context.Context, user *models.User, email, ip string, now time.Time) error {
+	until := now.Add(LoginLockoutDuration)
+
+	emailKey := loginEmailKey(email)
+	attempt, err := s.loginAttemptRepo.RecordFailure(ctx, emailKey, now, LoginFailureWindow)
+	if err != nil {
+		return err
+	}
+	if attempt.Failures >= LoginMaxFailures {
+		if err := s.loginAttemptRepo.Lock(ctx, emailKey, until); err != nil {
+			return err
+		}
+		if user != nil {
+			reason := fmt.Sprintf("%d failed login attempts", attempt.Failures)
+			if err := s.userRepo.Lock(ctx, user.ID, models.LockoutEvent{
+				Action: models.LockoutActionLocked,
+				At:     now,
+				Until:  &until,
+				Reason: reason,
+			}); err != nil {
+				return err
+			}
+
+			event := newUserAuditEvent(ctx, models.AuditActionAccountLocked, &user.ID)
+			event.Details = map[string]string{"reason": reason, "until": until.Format(time.RFC3339)}
+			recordAuditEvent(ctx, s.auditRepo, event)
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+	ipKey := loginIPKey(ip)
+	attempt, err = s.loginAttemptRepo.RecordFailure(ctx, ipKey, now, LoginFailureWindow)
+	if err != nil {
+		return err
+	}
+	if attempt.Failures >= LoginIPMaxFailures {
+		return s.loginAttemptRepo.Lock(ctx, ipKey, until)
+	}
+	return nil
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/jwt"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/totp"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// MFAIssuer is the name authenticator apps show next to the account
+var MFAIssuer = utils.GetEnv("MFA_ISSUER", "Axis")
+
+// RecoveryCodeCount is how many one-time recovery codes are issued when MFA is enabled
+const RecoveryCodeCount = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// VerifyMFA completes a login started by Login, exchanging the MFA token and a TOTP or recovery code
+// for a new session. Wrong codes count towards the same lockout as wrong passwords, and each MFA token
+// can complete one login only.
+func (s *authService) VerifyMFA(ctx context.Context, input dtos.MFALoginRequest) (*dtos.AuthResponse, error) {
+	now := time.Now()
+	subject, challengeID, err := jwt.ValidateMFAToken(input.MFAToken)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+	userID, err := primitive.ObjectIDFromHex(subject)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.MFA.Enabled || user.MFA.ChallengeID != challengeID {
+		return nil, ErrInvalidMFAToken
+	}
+	if err := s.checkLoginThrottle(ctx, user.Email, input.IP, now); err != nil {
+		return nil, err
+	}
+	if user.IsLocked(now) {
+		return nil, &LoginThrottledError{RetryAfter: user.LockedUntil.Sub(now)}
+	}
+	if err := userStatusError(user.Status); err != nil {
+		return nil, err
+	}
+
+	secondFactor := "totp"
+	if input.RecoveryCode != "" {
+		secondFactor = "recovery_code"
+	}
+
+	if err := s.checkSecondFactor(ctx, user, input); err != nil {
+		if err != ErrInvalidMFACode {
+			return nil, err
+		}
+
+		event := newUserAuditEvent(ctx, models.AuditActionLoginFailed, &user.ID)
+		event.Details = map[string]string{"email": user.Email, "second_factor": secondFactor}
+		recordAuditEvent(ctx, s.auditRepo, event)
+
+		if err := s.recordLoginFailure(ctx, user, user.Email, input.IP, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidMFACode
+	}
+
+	consumed, err := s.userRepo.ConsumeMFAChallenge(ctx, user.ID, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidMFAToken
+	}
+	if err := s.loginAttemptRepo.Reset(ctx, loginEmailKey(user.Email)); err != nil {
+		return nil, err
+	}
+
+	event := selfAuditEvent(ctx, models.AuditActionLogin, user)
+	event.Details = map[string]string{"second_factor": secondFactor}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	// Start a new session for the user
+	return s.issueTokens(ctx, user, primitive.NewObjectID(), primitive.NewObjectID())
+}
+
+// checkSecondFactor accepts the recovery code of input if one is given and its TOTP code otherwise
+func (s *authService) checkSecondFactor(ctx context.Context, user *models.User, input dtos.MFALoginRequest) error {
+	if input.RecoveryCode == "" {
+		return s.checkTOTP(ctx, user, input.Code)
+	}
+
+	used, err := s.userRepo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(input.RecoveryCode))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// EnrollMFA generates a new TOTP secret for the principal. MFA is only enforced once the
+// enrollment is confirmed with ActivateMFA; enrolling again before that replaces the secret.
+func (s *authService) EnrollMFA(ctx context.Context, principal *models.Principal) (*dtos.MFAEnrollResponse, error) {
+	if err := checkMFAManager(principal); err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.userRepo.SetMFASecret(ctx, principal.UserID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	return &dtos.MFAEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(secret, MFAIssuer, principal.Email),
+	}, nil
+}
+
+// ActivateMFA enables MFA once the principal proves the authenticator app produces valid codes, and
+// returns the recovery codes
+func (s *authService) ActivateMFA(ctx context.Context, principal *models.Principal, input dtos.MFAActivateRequest) (*dtos.MFAActivateResponse, error) {
+	if err := checkMFAManager(principal); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if user.MFA.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFA.Secret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := totp.Validate(user.MFA.Secret, input.Code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.userRepo.EnableMFA(ctx, user.ID, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	recordAuditEvent(ctx, s.auditRepo, withActor(newUserAuditEvent(ctx, models.AuditActionMFAEnabled, &user.ID), principal))
+
+	return &dtos.MFAActivateResponse{RecoveryCodes: codes}, nil
+}
+
+// mfaChallenge returns the first login step result for a user with MFA enabled
+func (s *authService) mfaChallenge(ctx context.Context, user *models.User) (*dtos.AuthResponse, error) {
+	token, challengeID, err := jwt.GenerateMFAToken(user.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetMFAChallenge(ctx, user.ID, challengeID); err != nil {
+		return nil, err
+	}
+
+	return &dtos.AuthResponse{
+		MFARequired: true,
+		MFAToken:    token,
+	}, nil
+}
+
+// checkTOTP accepts a code at most once, so a code seen by an attacker cannot be replayed within its period
+func (s *authService) checkTOTP(ctx context.Context, user *models.User, code string) error {
+	step, ok := totp.Validate(user.MFA.Secret, code, time.Now())
+	if !ok || step <= user.MFA.LastStep {
+		return ErrInvalidMFACode
+	}
+
+	advanced, err := s.userRepo.AdvanceMFAStep(ctx, user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// checkMFAManager only lets principals authenticated with a token change the second factor
+func checkMFAManager(principal *models.Principal) error {
+	if principal == nil {
+		return utils.ErrInvalidToken
+	}
+	if principal.APIKeyID != nil {
+		return ErrMFAManagement
+	}
+	return nil
+}
+
+// generateRecoveryCodes returns RecoveryCodeCount codes formatted as xxxxx-xxxxx and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		b := make([]byte, 6)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code regardless of case and separators
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashOpaqueToken(normalized)
+}
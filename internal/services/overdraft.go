@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+const (
+	overdraftChargeBatch       = 100
+	overdraftChargeDescription = "Overdraft interest and fees"
+)
+
+// ChargeOverdrafts charges a day of interest and fees to overdrawn balances for every UTC day since each
+// was last charged, up to and including the day containing now. Days missed while the worker was not
+// running are charged at the balance's current amount. Balances that are not overdrawn are marked as
+// charged for the day without a charge. A balance that fails is logged and skipped for the rest of the
+// run; the errors are returned together once every other balance is charged. It returns the number of
+// charges posted.
+func (s *transactionService) ChargeOverdrafts(ctx context.Context, now time.Time) (int, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	charged := 0
+	var failed []primitive.ObjectID
+	var errs []error
+	for {
+		balances, err := s.balanceRepo.FindOverdraftsDue(ctx, today, failed, overdraftChargeBatch)
+		if err != nil {
+			return charged, errors.Join(append(errs, err)...)
+		}
+
+		for _, balance := range balances {
+			count, err := s.chargeOverdraftDays(ctx, balance, today)
+			charged += count
+			if err != nil {
+				log.Error().Err(err).Str("balance_id", balance.ID.Hex()).Msg("Failed to charge overdraft")
+				failed = append(failed, balance.ID)
+				errs = append(errs, fmt.Errorf("balance %s: %w", balance.ID.Hex(), err))
+			}
+		}
+
+		if len(balances) < overdraftChargeBatch {
+			return charged, errors.Join(errs...)
+		}
+	}
+}
+
+// chargeOverdraftDays charges balance for each day after the one it was charged through, up to today. A
+// balance never charged is only due today. It stops early when another run is charging the balance.
+func (s *transactionService) chargeOverdraftDays(ctx context.Context, balance models.Balance, today time.Time) (int, error) {
+	var chargedThrough *time.Time
+	day := today
+	if balance.Overdraft != nil && balance.Overdraft.ChargedThrough != nil {
+		last := balance.Overdraft.ChargedThrough.UTC()
+		chargedThrough = &last
+		day = last.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	}
+
+	count := 0
+	for ; !day.After(today); day = day.Add(24 * time.Hour) {
+		marked, charged, err := s.chargeOverdraft(ctx, balance, chargedThrough, day)
+		if err != nil || !marked {
+			return count, err
+		}
+		if charged {
+			count++
+		}
+		through := day
+		chargedThrough = &through
+	}
+	return count, nil
+}
+
+// chargeOverdraft advances the balance from chargedThrough to the day starting at dayStart and debits the
+// charge owed at its current amount. It reports whether the day was marked, false when another run marked
+// it first, and whether a charge was posted.
+func (s *transactionService) chargeOverdraft(ctx context.Context, balance models.Balance, chargedThrough *time.Time, dayStart time.Time) (marked, charged bool, err error) {
+	err = s.runInTransaction(ctx, func(sc context.Context) error {
+		marked, charged = false, false
+
+		ok, err := s.balanceRepo.MarkOverdraftCharged(sc, balance.ID, chargedThrough, dayStart)
+		if err != nil || !ok {
+			return err
+		}
+		marked = true
+
+		// The balance may have moved since it was listed
+		current, err := s.balanceRepo.GetBalance(sc, balance.AccountID, balance.Currency)
+		if err != nil || current == nil {
+			return err
+		}
+		interest, fee := current.DailyOverdraftCharge()
+		if interest+fee == 0 {
+			return nil
+		}
+
+		accountID := current.AccountID
+		balances, err := s.snapshotBalances(sc, current.Currency, accountID)
+		if err != nil {
+			return err
+		}
+
+		entry := &models.JournalEntry{
+			Kind:        models.JournalEntryKindOverdraftCharge,
+			Description: overdraftChargeDescription,
+			Postings:    []models.Posting{customerDebit(accountID, interest+fee, current.Currency)},
+		}
+		if interest > 0 {
+			entry.Postings = append(entry.Postings, credit(models.LedgerAccountOverdraftInterest, nil, interest, current.Currency))
+		}
+		if fee > 0 {
+			entry.Postings = append(entry.Postings, credit(models.LedgerAccountFees, nil, fee, current.Currency))
+		}
+		if err := s.postChargeEntry(sc, entry); err != nil {
+			return err
+		}
+		charged = true
+
+		transaction, err := s.transactionRepo.CreateTransaction(sc, &dtos.CreateTransactionDTO{
+			AccountID:      accountID,
+			Amount:         interest + fee,
+			Currency:       current.Currency,
+			Type:           string(models.TransactionTypeDebit),
+			Description:    overdraftChargeDescription,
+			JournalEntryID: entry.ID,
+			Charge:         true,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionOverdraftCharged, &accountID)
+		event.TransactionID = &transaction.ID
+		event.Details = map[string]string{
+			"day":      dayStart.Format(time.DateOnly),
+			"used":     current.OverdraftUsed().Format(current.Currency),
+			"interest": interest.Format(current.Currency),
+			"fee":      fee.Format(current.Currency),
+		}
+		return s.recordMoneyEvent(sc, event, balances)
+	})
+	if err != nil {
+		return false, false, err
+	}
+
+	return marked, charged, nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// GetProfile returns the principal's user
+func (s *authService) GetProfile(ctx context.Context, principal *models.Principal) (*models.User, error) {
+	if principal == nil {
+		return nil, utils.ErrInvalidToken
+	}
+	if !principal.Allows(models.PermissionAccountRead) {
+		return nil, utils.ErrAPIKeyScope
+	}
+
+	user, err := s.userRepo.FindByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, utils.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// UpdateProfile changes the name, email or phone number of the principal. A new email or phone
+// number must be verified again, and the previous email address is told about the change.
+func (s *authService) UpdateProfile(ctx context.Context, principal *models.Principal, input dtos.UpdateProfileRequest) (*models.User, error) {
+	if err := checkProfileManager(principal); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if !user.UpdatedAt.Equal(*input.UpdatedAt) {
+		return nil, ErrUserModified
+	}
+
+	var changed []string
+	nameChanged := input.Name != nil && *input.Name != user.Name
+	emailChanged := input.Email != nil && *input.Email != user.Email
+	phoneChanged := input.PhoneNumber != nil && *input.PhoneNumber != user.PhoneNumber
+	if !nameChanged && !emailChanged && !phoneChanged {
+		return user, nil
+	}
+
+	if emailChanged || phoneChanged {
+		if err := s.checkCurrentPassword(ctx, user, input.CurrentPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	previousEmail := user.Email
+	if nameChanged {
+		user.Name = *input.Name
+		changed = append(changed, "name")
+	}
+	if emailChanged {
+		existing, err := s.userRepo.FindByEmail(ctx, *input.Email)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrEmailExists
+		}
+		user.Email = *input.Email
+		user.EmailVerified = false
+		changed = append(changed, "email")
+	}
+	if phoneChanged {
+		user.PhoneNumber = *input.PhoneNumber
+		user.PhoneVerified = false
+		changed = append(changed, "phone_number")
+	}
+
+	updated, err := s.userRepo.Update(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		if phoneChanged {
+			return nil, ErrPhoneExists
+		}
+		return nil, ErrEmailExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrUserModified
+	}
+
+	if emailChanged {
+		// A reset link mailed to the previous address must not outlive the change
+		if err := s.actionTokenRepo.InvalidateAll(ctx, user.ID, models.ActionPasswordReset); err != nil {
+			return nil, err
+		}
+		if err := s.sendVerification(ctx, user); err != nil {
+			log.Warn().Err(err).Str("user_id", user.ID.Hex()).Msg("Failed to send verification email")
+		}
+		if err := s.mailer.Send(ctx, mailer.Message{
+			To:      previousEmail,
+			Subject: "Your email address was changed",
+			Body: fmt.Sprintf("The email address of your account was changed to %s. If you did not make this change, contact support.",
+				user.Email),
+		}); err != nil {
+			log.Warn().Err(err).Str("user_id", user.ID.Hex()).Msg("Failed to notify previous email address")
+		}
+	}
+	if phoneChanged {
+		if err := s.sendPhoneVerification(ctx, user); err != nil {
+			log.Warn().Err(err).Str("user_id", user.ID.Hex()).Msg("Failed to send phone verification")
+		}
+	}
+
+	event := newUserAuditEvent(ctx, models.AuditActionProfileUpdated, &user.ID)
+	event.Details = map[string]string{"fields": strings.Join(changed, ",")}
+	recordAuditEvent(ctx, s.auditRepo, event)
+
+	return user, nil
+}
+
+// ChangePassword replaces the password of the principal after checking the current one, and
+// signs out every other session of the user
+func (s *authService) ChangePassword(ctx context.Context, principal *models.Principal, input dtos.ChangePasswordRequest) error {
+	if err := checkProfileManager(principal); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return utils.ErrUserNotFound
+	}
+
+	if err := s.checkCurrentPassword(ctx, user, input.CurrentPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+
+	updated, err := s.userRepo.Update(ctx, user)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrUserModified
+	}
+
+	if err := s.refreshTokenRepo.RevokeOtherSessions(ctx, user.ID, principal.SessionID); err != nil {
+		return err
+	}
+	if err := s.actionTokenRepo.InvalidateAll(ctx, user.ID, models.ActionPasswordReset); err != nil {
+		return err
+	}
+
+	recordAuditEvent(ctx, s.auditRepo, newUserAuditEvent(ctx, models.AuditActionPasswordChanged, &user.ID))
+	return nil
+}
+
+// checkCurrentPassword confirms a sensitive change with the user's password. Wrong passwords count
+// as failed logins, so they are throttled and can lock the user out.
+func (s *authService) checkCurrentPassword(ctx context.Context, user *models.User, password string) error {
+	now := time.Now()
+	ip := models.RequestInfoFromContext(ctx).IP
+	if err := s.checkLoginThrottle(ctx, user.Email, ip, now); err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		if err := s.recordLoginFailure(ctx, user, user.Email, ip, now); err != nil {
+			return err
+		}
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+// checkProfileManager allows profile changes only with an access token, so that a leaked API key
+// cannot take over the user
+func checkProfileManager(principal *models.Principal) error {
+	if principal == nil {
+		return utils.ErrInvalidToken
+	}
+	if principal.APIKeyID != nil {
+		return ErrProfileManagement
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// Reverse compensates a completed transaction. A nil amount reverses whatever is left; debits
+// may be refunded partially while credits must be reversed in full. Reversing either leg of a
+// transfer compensates both legs.
+func (s *transactionService) Reverse(ctx context.Context, transactionID primitive.ObjectID, amount *money.Amount, reason string) (*dtos.ReversalResponse, error) {
+	var original, reversal *models.Transaction
+	var reversed money.Amount
+
+	err := s.runInTransaction(ctx, func(sc context.Context) error {
+		var err error
+		original, err = s.transactionRepo.FindByID(sc, transactionID)
+		if err != nil {
+			return err
+		}
+		if original == nil {
+			return utils.ErrTransactionNotFound
+		}
+		if original.Status != models.TransactionStatusCompleted || original.ReversalOf != nil {
+			return utils.ErrTransactionNotReversible
+		}
+		// The legs of a conversion are in different currencies; converting back takes a new quote
+		if original.ConversionID != nil {
+			return utils.ErrConversionNotReversible
+		}
+
+		remaining := original.Amount - original.ReversedAmount
+		if remaining <= 0 {
+			return utils.ErrAlreadyReversed
+		}
+
+		reversed = remaining
+		if amount != nil {
+			reversed = *amount
+		}
+		if reversed <= 0 {
+			return utils.ErrInvalidAmount
+		}
+		if reversed > remaining {
+			return utils.ErrReversalExceedsOriginal
+		}
+		if original.Type == models.TransactionTypeCredit && original.TransferID == nil && reversed != original.Amount {
+			return utils.ErrPartialReversalNotAllowed
+		}
+
+		legs := []models.Transaction{*original}
+		if original.TransferID != nil {
+			if legs, err = s.transactionRepo.FindByTransferID(sc, *original.TransferID); err != nil {
+				return err
+			}
+		}
+
+		accountIDs := make([]primitive.ObjectID, len(legs))
+		for i, leg := range legs {
+			accountIDs[i] = leg.AccountID
+		}
+		balances, err := s.snapshotBalances(sc, original.Currency, accountIDs...)
+		if err != nil {
+			return err
+		}
+
+		entry, err := s.reversalEntry(sc, original, reversed, reason)
+		if err != nil {
+			return err
+		}
+		if err := s.postJournalEntry(sc, entry); err != nil {
+			return err
+		}
+
+		for i := range legs {
+			leg := &legs[i]
+
+			recorded, err := s.transactionRepo.AddReversedAmount(sc, leg.ID, reversed)
+			if err != nil {
+				return err
+			}
+			if !recorded {
+				return utils.ErrReversalExceedsOriginal
+			}
+
+			compensating, err := s.transactionRepo.CreateTransaction(sc, &dtos.CreateTransactionDTO{
+				AccountID:      leg.AccountID,
+				Amount:         reversed,
+				Currency:       leg.Currency,
+				Type:           string(oppositeType(leg.Type)),
+				Description:    reason,
+				JournalEntryID: entry.ID,
+				ReversalOf:     &leg.ID,
+				APIKeyID:       apiKeyIDFromContext(ctx),
+			})
+			if err != nil {
+				return err
+			}
+			if leg.ID == original.ID {
+				reversal = compensating
+			}
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionReversal, &original.AccountID)
+		event.TransactionID = &reversal.ID
+		event.Details = map[string]string{"original_transaction_id": original.ID.Hex()}
+		if reason != "" {
+			event.Details["reason"] = reason
+		}
+		return s.recordMoneyEvent(sc, event, balances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.ReversalResponse{
+		OriginalTransactionID: original.ID.Hex(),
+		ReversalTransactionID: reversal.ID.Hex(),
+		ReversedAmount:        money.NewDecimal(original.ReversedAmount+reversed, original.Currency),
+		RemainingAmount:       money.NewDecimal(original.Amount-original.ReversedAmount-reversed, original.Currency),
+		Currency:              original.Currency,
+	}, nil
+}
+
+// reversalEntry mirrors the postings of the original journal entry with directions swapped.
+// Transactions recorded before the ledger existed are mirrored from their type instead.
+func (s *transactionService) reversalEntry(ctx context.Context, original *models.Transaction, amount money.Amount, reason string) (*models.JournalEntry, error) {
+	entry := &models.JournalEntry{
+		Kind:        models.JournalEntryKindReversal,
+		Description: reason,
+	}
+
+	var source *models.JournalEntry
+	if !original.JournalEntryID.IsZero() {
+		var err error
+		if source, err = s.ledgerRepo.FindEntry(ctx, original.JournalEntryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if source == nil {
+		if original.Type == models.TransactionTypeCredit {
+			entry.Postings = []models.Posting{
+				customerDebit(original.AccountID, amount, original.Currency),
+				credit(models.LedgerAccountCashIn, nil, amount, original.Currency),
+			}
+		} else {
+			entry.Postings = []models.Posting{
+				debit(models.LedgerAccountCashOut, nil, amount, original.Currency),
+				customerCredit(original.AccountID, amount, original.Currency),
+			}
+		}
+		return entry, nil
+	}
+
+	postings, err := scalePostings(source.Postings, amount, original.Amount)
+	if err != nil {
+		return nil, err
+	}
+	for _, posting := range postings {
+		mirrored := posting
+		mirrored.Direction = models.PostingDirectionDebit
+		if posting.Direction == models.PostingDirectionDebit {
+			mirrored.Direction = models.PostingDirectionCredit
+		}
+		entry.Postings = append(entry.Postings, mirrored)
+	}
+
+	return entry, nil
+}
+
+// scalePostings scales every posting by amount/total, rounding down. Within each currency and direction
+// the rounding remainder goes to the largest posting, so each side adds up to its total scaled and an
+// entry that balanced still balances. Postings scaled to nothing are dropped. Reversing the whole total
+// copies the postings unscaled.
+func scalePostings(postings []models.Posting, amount, total money.Amount) ([]models.Posting, error) {
+	if amount == total {
+		return append([]models.Posting(nil), postings...), nil
+	}
+
+	type side struct {
+		currency  string
+		direction models.PostingDirection
+	}
+	sums := map[side]money.Amount{}
+	scaledSums := map[side]money.Amount{}
+	largest := map[side]int{}
+
+	scaled := make([]models.Posting, len(postings))
+	for i, posting := range postings {
+		key := side{posting.Currency, posting.Direction}
+		scaled[i] = posting
+		var err error
+		if scaled[i].Amount, err = scaleAmount(posting.Amount, amount, total); err != nil {
+			return nil, err
+		}
+
+		sums[key] += posting.Amount
+		scaledSums[key] += scaled[i].Amount
+		if j, ok := largest[key]; !ok || posting.Amount > postings[j].Amount {
+			largest[key] = i
+		}
+	}
+
+	for key, i := range largest {
+		sideScaled, err := scaleAmount(sums[key], amount, total)
+		if err != nil {
+			return nil, err
+		}
+		scaled[i].Amount += sideScaled - scaledSums[key]
+	}
+
+	kept := scaled[:0]
+	for _, posting := range scaled {
+		if posting.Amount > 0 {
+			kept = append(kept, posting)
+		}
+	}
+	return kept, nil
+}
+
+// scaleAmount returns value*amount/total rounded down. The product is taken in arbitrary precision, since
+// it overflows an Amount long before the result does.
+func scaleAmount(value, amount, total money.Amount) (money.Amount, error) {
+	scaled := new(big.Int).Mul(big.NewInt(int64(value)), big.NewInt(int64(amount)))
+	scaled.Quo(scaled, big.NewInt(int64(total)))
+	if !scaled.IsInt64() {
+		return 0, utils.NewError(http.StatusBadRequest, money.ErrOverflow.Error())
+	}
+	return money.Amount(scaled.Int64()), nil
+}
+
+func oppositeType(t models.TransactionType) models.TransactionType {
+	if t == models.TransactionTypeDebit {
+		return models.TransactionTypeCredit
+	}
+	return models.TransactionTypeDebit
+}
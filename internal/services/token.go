@@ -0,0 +1,29 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// opaqueTokenBytes is the entropy of tokens handed to clients
+const opaqueTokenBytes = 32
+
+// generateOpaqueToken returns a random URL-safe token and the hash under which it is stored
+func generateOpaqueToken() (token, tokenHash string, err error) {
+	b := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashOpaqueToken(token), nil
+}
+
+// hashOpaqueToken returns the hex SHA-256 of a token. Only the hash is persisted, so a database
+// leak does not expose usable tokens.
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
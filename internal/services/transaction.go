@@ -2,75 +2,153 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 var (
 	ErrInvalidAmount = utils.ErrInvalidAmount
 )
 
-type TransactionService struct {
-	db              *mongo.Database
+// TransactionService moves money. Every movement runs in one Mongo transaction that posts its journal
+// entry, updates the balance projections and appends the audit event.
+type TransactionService interface {
+	GetBalances(ctx context.Context, accountID primitive.ObjectID) (*dtos.BalancesResponse, error)
+	Deposit(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, idempotencyKey string) (string, error)
+	Withdraw(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, idempotencyKey string) (string, error)
+	Transfer(ctx context.Context, sourceID, destinationID primitive.ObjectID, amount money.Amount, currency, description string) (*dtos.TransferResponse, error)
+	ListTransactions(ctx context.Context, accountID primitive.ObjectID, query dtos.TransactionHistoryQuery) (*dtos.TransactionHistoryResponse, error)
+	GetTransaction(ctx context.Context, id primitive.ObjectID) (*dtos.TransactionDetail, error)
+	Authorize(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, description string, ttl time.Duration) (*dtos.HoldResponse, error)
+	Capture(ctx context.Context, holdID primitive.ObjectID, amount *money.Amount) (*dtos.HoldResponse, error)
+	Void(ctx context.Context, holdID primitive.ObjectID) (*dtos.HoldResponse, error)
+	ExpireHolds(ctx context.Context, now time.Time) (int, error)
+	Reverse(ctx context.Context, transactionID primitive.ObjectID, amount *money.Amount, reason string) (*dtos.ReversalResponse, error)
+	Convert(ctx context.Context, quote *models.FXQuote, claim func(ctx context.Context, conversionID primitive.ObjectID) error) (*dtos.FXConversionResponse, error)
+	ChargeOverdrafts(ctx context.Context, now time.Time) (int, error)
+}
+
+type transactionService struct {
+	txRunner        repository.TxRunner
 	transactionRepo repository.TransactionRepository
 	balanceRepo     repository.BalanceRepository
+	idempotencyRepo repository.IdempotencyRepository
+	ledgerRepo      repository.LedgerRepository
+	auditRepo       repository.AuditEventRepository
+	accountRepo     repository.AccountRepository
+	userRepo        repository.UserRepository
+	limitRepo       repository.LimitPolicyRepository
 }
 
-func NewTransactionService(db *mongo.Database) *TransactionService {
-	return &TransactionService{
-		db:              db,
-		transactionRepo: repository.NewTransactionRepository(db),
-		balanceRepo:     repository.NewBalanceRepository(db),
+func NewTransactionService(txRunner repository.TxRunner, transactionRepo repository.TransactionRepository, balanceRepo repository.BalanceRepository, idempotencyRepo repository.IdempotencyRepository, ledgerRepo repository.LedgerRepository, auditRepo repository.AuditEventRepository, accountRepo repository.AccountRepository, userRepo repository.UserRepository, limitRepo repository.LimitPolicyRepository) TransactionService {
+	return &transactionService{
+		txRunner:        txRunner,
+		transactionRepo: transactionRepo,
+		balanceRepo:     balanceRepo,
+		idempotencyRepo: idempotencyRepo,
+		ledgerRepo:      ledgerRepo,
+		auditRepo:       auditRepo,
+		accountRepo:     accountRepo,
+		userRepo:        userRepo,
+		limitRepo:       limitRepo,
 	}
 }
 
-func (s *TransactionService) Deposit(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) (string, error) {
+// Deposit credits the account. When idempotencyKey is set, a replay of the same request returns the
+// original transaction ID instead of moving money again.
+func (s *transactionService) Deposit(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, idempotencyKey string) (string, error) {
 	if amount <= 0 {
 		return "", utils.ErrInvalidAmount
 	}
+	if !money.IsEnabled(currency) {
+		return "", utils.ErrCurrencyDisabled
+	}
 
-	session, err := s.db.Client().StartSession()
-	if err != nil {
-		return "", err
+	requestHash := idempotencyHash(IdempotencyOperationDeposit, accountID, amount, currency)
+	if idempotencyKey != "" {
+		if transactionID, found, err := s.findIdempotentResult(ctx, accountID, idempotencyKey, requestHash); found || err != nil {
+			return transactionID, err
+		}
 	}
-	defer session.EndSession(ctx)
 
 	var transaction *models.Transaction
-	err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		if err := session.StartTransaction(); err != nil {
+	err := s.runInTransaction(ctx, func(sc context.Context) error {
+		// Claim the idempotency key first so concurrent duplicates are serialized
+		var idempotencyRecord *models.IdempotencyKey
+		if idempotencyKey != "" {
+			var err error
+			idempotencyRecord, err = s.claimIdempotencyKey(sc, accountID, idempotencyKey, IdempotencyOperationDeposit, requestHash)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := s.requireActiveAccounts(sc, accountID); err != nil {
+			return err
+		}
+
+		if err := s.checkLimits(sc, accountID, currency, models.LimitDirectionDeposit, amount); err != nil {
 			return err
 		}
 
-		// Update balance
-		if err := s.balanceRepo.UpdateBalance(sc, accountID, amount, currency); err != nil {
+		balances, err := s.snapshotBalances(sc, currency, accountID)
+		if err != nil {
+			return err
+		}
+
+		// Post cash received against the customer's ledger account
+		entry := &models.JournalEntry{
+			Kind: models.JournalEntryKindDeposit,
+			Postings: []models.Posting{
+				debit(models.LedgerAccountCashIn, nil, amount, currency),
+				customerCredit(accountID, amount, currency),
+			},
+		}
+		if err := s.postJournalEntry(sc, entry); err != nil {
 			return err
 		}
 
 		// Create transaction record
 		createDTO := &dtos.CreateTransactionDTO{
-			AccountID: accountID,
-			Amount:    amount,
-			Currency:  currency,
-			Type:      string(models.TransactionTypeCredit),
+			AccountID:      accountID,
+			Amount:         amount,
+			Currency:       currency,
+			Type:           string(models.TransactionTypeCredit),
+			JournalEntryID: entry.ID,
+			APIKeyID:       apiKeyIDFromContext(ctx),
 		}
 
-		var err error
 		transaction, err = s.transactionRepo.CreateTransaction(sc, createDTO)
 		if err != nil {
 			return err
 		}
 
-		return session.CommitTransaction(sc)
-	})
+		event := newAuditEvent(ctx, models.AuditActionDeposit, &accountID)
+		event.TransactionID = &transaction.ID
+		if err := s.recordMoneyEvent(sc, event, balances); err != nil {
+			return err
+		}
+
+		if idempotencyRecord != nil {
+			if err := s.idempotencyRepo.SetTransaction(sc, idempotencyRecord.ID, transaction.ID); err != nil {
+				return err
+			}
+		}
 
+		return nil
+	})
 	if err != nil {
-		if abortErr := session.AbortTransaction(ctx); abortErr != nil {
-			return "", abortErr
+		if idempotencyKey != "" && isIdempotencyConflict(err) {
+			if transactionID, found, lookupErr := s.findIdempotentResult(ctx, accountID, idempotencyKey, requestHash); found || lookupErr != nil {
+				return transactionID, lookupErr
+			}
+			return "", utils.ErrIdempotencyKeyInProgress
 		}
 		return "", err
 	}
@@ -78,7 +156,7 @@ func (s *TransactionService) Deposit(ctx context.Context, accountID primitive.Ob
 	return transaction.ID.Hex(), nil
 }
 
-func (s *TransactionService) GetBalances(ctx context.Context, accountID primitive.ObjectID) (*dtos.BalancesResponse, error) {
+func (s *transactionService) GetBalances(ctx context.Context, accountID primitive.ObjectID) (*dtos.BalancesResponse, error) {
 	balances, err := s.balanceRepo.GetBalances(ctx, accountID)
 	if err != nil {
 		return nil, err
@@ -86,10 +164,7 @@ func (s *TransactionService) GetBalances(ctx context.Context, accountID primitiv
 
 	currencyBalances := make([]dtos.CurrencyBalance, len(balances))
 	for i, balance := range balances {
-		currencyBalances[i] = dtos.CurrencyBalance{
-			Currency: balance.Currency,
-			Amount:   balance.Amount,
-		}
+		currencyBalances[i] = toCurrencyBalance(&balance)
 	}
 
 	return &dtos.BalancesResponse{
@@ -97,51 +172,226 @@ func (s *TransactionService) GetBalances(ctx context.Context, accountID primitiv
 	}, nil
 }
 
-func (s *TransactionService) Withdraw(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) (string, error) {
+// Withdraw debits the account if its balance allows it. When idempotencyKey is set, a replay of the same request returns the
+// original transaction ID instead of moving money again.
+func (s *transactionService) Withdraw(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, idempotencyKey string) (string, error) {
 	if amount <= 0 {
 		return "", utils.ErrInvalidAmount
 	}
 
-	session, err := s.db.Client().StartSession()
-	if err != nil {
-		return "", err
+	requestHash := idempotencyHash(IdempotencyOperationWithdraw, accountID, amount, currency)
+	if idempotencyKey != "" {
+		if transactionID, found, err := s.findIdempotentResult(ctx, accountID, idempotencyKey, requestHash); found || err != nil {
+			return transactionID, err
+		}
 	}
-	defer session.EndSession(ctx)
 
 	var transaction *models.Transaction
-	err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		if err := session.StartTransaction(); err != nil {
+	err := s.runInTransaction(ctx, func(sc context.Context) error {
+		// Claim the idempotency key first so concurrent duplicates are serialized
+		var idempotencyRecord *models.IdempotencyKey
+		if idempotencyKey != "" {
+			var err error
+			idempotencyRecord, err = s.claimIdempotencyKey(sc, accountID, idempotencyKey, IdempotencyOperationWithdraw, requestHash)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := s.requireActiveAccounts(sc, accountID); err != nil {
+			return err
+		}
+
+		if err := s.checkLimits(sc, accountID, currency, models.LimitDirectionWithdrawal, amount); err != nil {
 			return err
 		}
 
-		// Check and update balance
-		if err := s.balanceRepo.CheckAndDeductBalance(sc, accountID, amount, currency); err != nil {
+		balances, err := s.snapshotBalances(sc, currency, accountID)
+		if err != nil {
+			return err
+		}
+
+		// Post cash paid out from the customer's ledger account
+		entry := &models.JournalEntry{
+			Kind: models.JournalEntryKindWithdrawal,
+			Postings: []models.Posting{
+				customerDebit(accountID, amount, currency),
+				credit(models.LedgerAccountCashOut, nil, amount, currency),
+			},
+		}
+		if err := s.postJournalEntry(sc, entry); err != nil {
 			return err
 		}
 
 		// Create transaction record
 		createDTO := &dtos.CreateTransactionDTO{
-			AccountID: accountID,
-			Amount:    amount,
-			Currency:  currency,
-			Type:      string(models.TransactionTypeDebit),
+			AccountID:      accountID,
+			Amount:         amount,
+			Currency:       currency,
+			Type:           string(models.TransactionTypeDebit),
+			JournalEntryID: entry.ID,
+			APIKeyID:       apiKeyIDFromContext(ctx),
 		}
 
-		var err error
 		transaction, err = s.transactionRepo.CreateTransaction(sc, createDTO)
 		if err != nil {
 			return err
 		}
 
-		return session.CommitTransaction(sc)
-	})
+		event := newAuditEvent(ctx, models.AuditActionWithdrawal, &accountID)
+		event.TransactionID = &transaction.ID
+		if err := s.recordMoneyEvent(sc, event, balances); err != nil {
+			return err
+		}
+
+		if idempotencyRecord != nil {
+			if err := s.idempotencyRepo.SetTransaction(sc, idempotencyRecord.ID, transaction.ID); err != nil {
+				return err
+			}
+		}
 
+		return nil
+	})
 	if err != nil {
-		if abortErr := session.AbortTransaction(ctx); abortErr != nil {
-			return "", abortErr
+		if idempotencyKey != "" && isIdempotencyConflict(err) {
+			if transactionID, found, lookupErr := s.findIdempotentResult(ctx, accountID, idempotencyKey, requestHash); found || lookupErr != nil {
+				return transactionID, lookupErr
+			}
+			return "", utils.ErrIdempotencyKeyInProgress
 		}
 		return "", err
 	}
 
 	return transaction.ID.Hex(), nil
 }
+
+// Transfer moves money between two accounts in a single Mongo transaction, writing a debit
+// and a credit record that share the returned transfer ID
+func (s *transactionService) Transfer(ctx context.Context, sourceID, destinationID primitive.ObjectID, amount money.Amount, currency, description string) (*dtos.TransferResponse, error) {
+	if amount <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+	if sourceID == destinationID {
+		return nil, utils.ErrSelfTransfer
+	}
+
+	transferID := primitive.NewObjectID()
+	var debit, credit *models.Transaction
+	err := s.runInTransaction(ctx, func(sc context.Context) error {
+		// Destination must already hold a balance in the transfer currency
+		destinationBalance, err := s.balanceRepo.GetBalance(sc, destinationID, currency)
+		if err != nil {
+			return err
+		}
+		if destinationBalance == nil {
+			return utils.ErrCurrencyMismatch
+		}
+
+		if err := s.requireActiveAccounts(sc, sourceID, destinationID); err != nil {
+			return err
+		}
+
+		// Only the outgoing leg counts towards limits; incoming transfers are not deposits
+		if err := s.checkLimits(sc, sourceID, currency, models.LimitDirectionWithdrawal, amount); err != nil {
+			return err
+		}
+
+		balances, err := s.snapshotBalances(sc, currency, sourceID, destinationID)
+		if err != nil {
+			return err
+		}
+
+		// Debit source and credit destination in one journal entry
+		entry := &models.JournalEntry{
+			Kind:        models.JournalEntryKindTransfer,
+			Description: description,
+			Postings: []models.Posting{
+				customerDebit(sourceID, amount, currency),
+				customerCredit(destinationID, amount, currency),
+			},
+		}
+		if err := s.postJournalEntry(sc, entry); err != nil {
+			return err
+		}
+
+		// Create linked transaction records
+		debit, err = s.transactionRepo.CreateTransaction(sc, &dtos.CreateTransactionDTO{
+			AccountID:      sourceID,
+			Amount:         amount,
+			Currency:       currency,
+			Type:           string(models.TransactionTypeDebit),
+			Description:    description,
+			TransferID:     &transferID,
+			JournalEntryID: entry.ID,
+			APIKeyID:       apiKeyIDFromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		credit, err = s.transactionRepo.CreateTransaction(sc, &dtos.CreateTransactionDTO{
+			AccountID:      destinationID,
+			Amount:         amount,
+			Currency:       currency,
+			Type:           string(models.TransactionTypeCredit),
+			Description:    description,
+			TransferID:     &transferID,
+			JournalEntryID: entry.ID,
+			APIKeyID:       apiKeyIDFromContext(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, models.AuditActionTransfer, &sourceID)
+		event.TransactionID = &debit.ID
+		event.Details = map[string]string{
+			"transfer_id":            transferID.Hex(),
+			"destination_account_id": destinationID.Hex(),
+		}
+		return s.recordMoneyEvent(sc, event, balances)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.TransferResponse{
+		TransferID:          transferID.Hex(),
+		DebitTransactionID:  debit.ID.Hex(),
+		CreditTransactionID: credit.ID.Hex(),
+	}, nil
+}
+
+// requireActiveAccounts refuses to move funds into or out of accounts with a blocked or deactivated
+// owner, or with no owner at all. Joint holders do not freeze an account: a blocked joint holder is
+// already refused at login, and the owner keeps control of the funds.
+func (s *transactionService) requireActiveAccounts(ctx context.Context, accountIDs ...primitive.ObjectID) error {
+	for _, accountID := range accountIDs {
+		account, err := s.accountRepo.FindByID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if account == nil {
+			return utils.ErrAccountNotFound
+		}
+
+		owned := false
+		for _, holder := range account.Holders {
+			if holder.Role != models.HolderRoleOwner {
+				continue
+			}
+			user, err := s.userRepo.FindByID(ctx, holder.UserID)
+			if err != nil {
+				return err
+			}
+			if user == nil || !user.Status.IsActive() {
+				return utils.ErrAccountFrozen
+			}
+			owned = true
+		}
+		if !owned {
+			return utils.ErrAccountFrozen
+		}
+	}
+	return nil
+}
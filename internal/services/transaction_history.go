@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// Page size limits for transaction history
+const (
+	DefaultTransactionPageSize = 20
+	MaxTransactionPageSize     = 100
+)
+
+// ListTransactions returns a page of an account's transactions, newest first
+func (s *transactionService) ListTransactions(ctx context.Context, accountID primitive.ObjectID, query dtos.TransactionHistoryQuery) (*dtos.TransactionHistoryResponse, error) {
+	filter, err := buildTransactionFilter(accountID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra record to know whether another page exists
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	transactions, err := s.transactionRepo.ListTransactions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.TransactionHistoryResponse{
+		Transactions: make([]dtos.TransactionDetail, 0, pageSize),
+	}
+
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		last := transactions[pageSize-1]
+		response.NextCursor = encodeTransactionCursor(dtos.TransactionCursor{
+			TransactionDate: last.TransactionDate,
+			ID:              last.ID,
+		})
+	}
+
+	for _, transaction := range transactions {
+		response.Transactions = append(response.Transactions, toTransactionDetail(&transaction))
+	}
+
+	return response, nil
+}
+
+// GetTransaction returns a single transaction by ID
+func (s *transactionService) GetTransaction(ctx context.Context, id primitive.ObjectID) (*dtos.TransactionDetail, error) {
+	transaction, err := s.transactionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, utils.ErrTransactionNotFound
+	}
+
+	detail := toTransactionDetail(transaction)
+	return &detail, nil
+}
+
+func buildTransactionFilter(accountID primitive.ObjectID, query dtos.TransactionHistoryQuery) (*dtos.TransactionFilter, error) {
+	filter := &dtos.TransactionFilter{
+		AccountID: accountID,
+		Type:      query.Type,
+		Status:    query.Status,
+		Currency:  strings.ToUpper(query.Currency),
+		Limit:     query.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultTransactionPageSize
+	}
+	if filter.Limit > MaxTransactionPageSize {
+		filter.Limit = MaxTransactionPageSize
+	}
+
+	for _, bound := range []struct {
+		value  string
+		target **time.Time
+	}{{query.From, &filter.From}, {query.To, &filter.To}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return nil, utils.NewError(http.StatusBadRequest, "dates must be in RFC 3339 format")
+		}
+		*bound.target = &t
+	}
+
+	if query.MinAmount != "" || query.MaxAmount != "" {
+		if filter.Currency == "" {
+			return nil, utils.NewError(http.StatusBadRequest, "currency is required when filtering by amount")
+		}
+		for _, bound := range []struct {
+			value  money.Decimal
+			target **money.Amount
+		}{{query.MinAmount, &filter.MinAmount}, {query.MaxAmount, &filter.MaxAmount}} {
+			if bound.value == "" {
+				continue
+			}
+			amount, err := bound.value.Amount(filter.Currency)
+			if err != nil {
+				return nil, utils.NewError(http.StatusBadRequest, err.Error())
+			}
+			*bound.target = &amount
+		}
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeTransactionCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+// encodeTransactionCursor renders the keyset position as an opaque token
+func encodeTransactionCursor(cursor dtos.TransactionCursor) string {
+	raw := fmt.Sprintf("%d:%s", cursor.TransactionDate.UnixNano(), cursor.ID.Hex())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTransactionCursor(token string) (*dtos.TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, utils.ErrInvalidCursor
+	}
+
+	nanos, hexID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, utils.ErrInvalidCursor
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, utils.ErrInvalidCursor
+	}
+
+	id, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil {
+		return nil, utils.ErrInvalidCursor
+	}
+
+	// Mongo stores dates with millisecond precision
+	return &dtos.TransactionCursor{
+		TransactionDate: time.Unix(0, unixNano).UTC().Truncate(time.Millisecond),
+		ID:              id,
+	}, nil
+}
+
+func toTransactionDetail(transaction *models.Transaction) dtos.TransactionDetail {
+	detail := dtos.TransactionDetail{
+		ID:              transaction.ID.Hex(),
+		AccountID:       transaction.AccountID.Hex(),
+		Type:            string(transaction.Type),
+		Amount:          money.NewDecimal(transaction.Amount, transaction.Currency),
+		Currency:        transaction.Currency,
+		Status:          string(transaction.Status),
+		Reference:       transaction.Reference,
+		Description:     transaction.Description,
+		TransactionDate: transaction.TransactionDate,
+	}
+	if transaction.TransferID != nil {
+		detail.TransferID = transaction.TransferID.Hex()
+	}
+	if transaction.ReversalOf != nil {
+		detail.ReversalOf = transaction.ReversalOf.Hex()
+	}
+	if transaction.APIKeyID != nil {
+		detail.APIKeyID = transaction.APIKeyID.Hex()
+	}
+	if transaction.ReversedAmount > 0 {
+		detail.ReversedAmount = money.NewDecimal(transaction.ReversedAmount, transaction.Currency)
+	}
+
+	return detail
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/repository"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+// DefaultUserPageSize is the page size of user listings without a limit
+const DefaultUserPageSize = 20
+
+// UserService manages users on behalf of staff
+type UserService interface {
+	ListUsers(ctx context.Context, query dtos.UserListQuery) (*dtos.UserListResponse, error)
+	GetUser(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	UpdateRole(ctx context.Context, actor *models.Principal, id primitive.ObjectID, role models.Role) (*models.User, error)
+	Unlock(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error)
+	Block(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error)
+	Unblock(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error)
+	Deactivate(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error)
+}
+
+type userService struct {
+	userRepo         repository.UserRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditRepo        repository.AuditEventRepository
+}
+
+func NewUserService(userRepo repository.UserRepository, loginAttemptRepo repository.LoginAttemptRepository, refreshTokenRepo repository.RefreshTokenRepository, auditRepo repository.AuditEventRepository) UserService {
+	return &userService{
+		userRepo:         userRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+	}
+}
+
+// ListUsers returns a page of users in creation order. The cursor is the ID of the last user of the previous page.
+func (s *userService) ListUsers(ctx context.Context, query dtos.UserListQuery) (*dtos.UserListResponse, error) {
+	filter := dtos.UserFilter{
+		Role:   query.Role,
+		Status: query.Status,
+		Limit:  query.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultUserPageSize
+	}
+	if query.Cursor != "" {
+		after, err := primitive.ObjectIDFromHex(query.Cursor)
+		if err != nil {
+			return nil, utils.ErrInvalidCursor
+		}
+		filter.After = &after
+	}
+
+	// Fetch one extra record to know whether another page exists
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	users, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, utils.DatabaseError("listing users", err)
+	}
+
+	response := &dtos.UserListResponse{Users: users}
+	if len(users) > pageSize {
+		response.Users = users[:pageSize]
+		response.NextCursor = users[pageSize-1].ID.Hex()
+	}
+
+	return response, nil
+}
+
+func (s *userService) GetUser(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, utils.DatabaseError("getting user", err)
+	}
+	if user == nil {
+		return nil, utils.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// UpdateRole changes a user's role. Tokens issued under the old role stop working until refreshed.
+func (s *userService) UpdateRole(ctx context.Context, actor *models.Principal, id primitive.ObjectID, role models.Role) (*models.User, error) {
+	if !role.IsValid() {
+		return nil, utils.ErrInvalidRole
+	}
+	if actor != nil && actor.UserID == id {
+		return nil, utils.ErrCannotChangeOwnRole
+	}
+
+	user, err := s.userRepo.UpdateRole(ctx, id, role)
+	if err != nil {
+		return nil, utils.DatabaseError("updating user role", err)
+	}
+	if user == nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	event := withActor(newUserAuditEvent(ctx, models.AuditActionRoleChanged, &user.ID), actor)
+	event.Details = map[string]string{"role": string(role)}
+	recordAuditEvent(ctx, s.auditRepo, event)
+	return user, nil
+}
+
+// Unlock lifts a login lockout and forgets the failed attempts counted for the user's email
+func (s *userService) Unlock(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error) {
+	event := models.LockoutEvent{
+		Action: models.LockoutActionUnlocked,
+		At:     time.Now(),
+		Reason: reason,
+	}
+	if actor != nil {
+		event.ActorID = &actor.UserID
+	}
+
+	user, err := s.userRepo.Unlock(ctx, id, event)
+	if err != nil {
+		return nil, utils.DatabaseError("unlocking user", err)
+	}
+	if user == nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	if err := s.loginAttemptRepo.Reset(ctx, loginEmailKey(user.Email)); err != nil {
+		return nil, err
+	}
+
+	auditEvent := withActor(newUserAuditEvent(ctx, models.AuditActionAccountUnlocked, &user.ID), actor)
+	if reason != "" {
+		auditEvent.Details = map[string]string{"reason": reason}
+	}
+	recordAuditEvent(ctx, s.auditRepo, auditEvent)
+	return user, nil
+}
+
+// Block freezes an active user: they can no longer log in or act on their accounts, and their sessions are revoked
+func (s *userService) Block(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error) {
+	return s.changeStatus(ctx, actor, id, models.UserStatusActive, models.UserStatusBlocked, models.AuditActionAccountBlocked, reason)
+}
+
+// Unblock makes a blocked user active again
+func (s *userService) Unblock(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error) {
+	return s.changeStatus(ctx, actor, id, models.UserStatusBlocked, models.UserStatusActive, models.AuditActionAccountUnblocked, reason)
+}
+
+// Deactivate closes an active or blocked user for good and revokes their sessions
+func (s *userService) Deactivate(ctx context.Context, actor *models.Principal, id primitive.ObjectID, reason string) (*models.User, error) {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == models.UserStatusInactive {
+		return nil, utils.ErrInvalidStatusChange
+	}
+	return s.changeStatus(ctx, actor, id, user.Status, models.UserStatusInactive, models.AuditActionAccountDeactivated, reason)
+}
+
+// changeStatus moves a user from one status to another, records the change in the user's status
+// history and the audit log, and signs the user out everywhere unless they became active
+func (s *userService) changeStatus(ctx context.Context, actor *models.Principal, id primitive.ObjectID, from, to models.UserStatus, action models.AuditAction, reason string) (*models.User, error) {
+	if actor != nil && actor.UserID == id {
+		return nil, utils.ErrCannotChangeOwnStatus
+	}
+
+	change := models.StatusChange{
+		From:   from,
+		To:     to,
+		At:     time.Now(),
+		Reason: reason,
+	}
+	if actor != nil {
+		change.ActorID = &actor.UserID
+	}
+
+	user, err := s.userRepo.UpdateStatus(ctx, id, change)
+	if err != nil {
+		return nil, utils.DatabaseError("changing user status", err)
+	}
+	if user == nil {
+		// Tell a missing user apart from one whose status does not allow the change
+		if _, err := s.GetUser(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrInvalidStatusChange
+	}
+
+	if !to.IsActive() {
+		if err := s.refreshTokenRepo.RevokeUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	auditEvent := withActor(newUserAuditEvent(ctx, action, &user.ID), actor)
+	auditEvent.Details = map[string]string{"from": string(from), "reason": reason}
+	recordAuditEvent(ctx, s.auditRepo, auditEvent)
+	return user, nil
+}
+
+// userStatusError returns the error for a user who may not log in or act on accounts, or nil if they are active
+func userStatusError(status models.UserStatus) error {
+	switch status {
+	case models.UserStatusBlocked:
+		return utils.ErrUserBlocked
+	case models.UserStatusInactive:
+		return utils.ErrUserInactive
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/sms"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+)
+
+var (
+	// AppURL is the base of the links mailed to users
+	AppURL = utils.GetEnv("APP_URL", "http://localhost:8080")
+
+	// PasswordResetTTL is how long a password reset link is valid
+	PasswordResetTTL = utils.GetDurationEnv("PASSWORD_RESET_TTL", time.Hour)
+
+	// EmailVerificationTTL is how long an email verification link is valid
+	EmailVerificationTTL = utils.GetDurationEnv("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+
+	// PhoneVerificationTTL is how long a phone verification link is valid
+	PhoneVerificationTTL = utils.GetDurationEnv("PHONE_VERIFICATION_TTL", 24*time.Hour)
+)
+
+// ForgotPassword mails a password reset link. Unknown addresses are ignored without an error, so the
+// endpoint does not reveal which emails are registered.
+func (s *authService) ForgotPassword(ctx context.Context, input dtos.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	token, err := s.issueActionToken(ctx, user, models.ActionPasswordReset, PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Follow this link within %s to choose a new password:\n\n%s\n\nIf you did not ask for a reset, ignore this email.",
+			PasswordResetTTL, actionLink("/reset-password", token)),
+	})
+}
+
+// ResetPassword sets a new password with a reset token and signs the user out everywhere
+func (s *authService) ResetPassword(ctx context.Context, input dtos.ResetPasswordRequest) error {
+	token, err := s.redeemActionToken(ctx, models.ActionPasswordReset, input.Token)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.userRepo.UpdatePassword(ctx, token.UserID, string(hashedPassword))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrInvalidActionToken
+	}
+
+	if err := s.refreshTokenRepo.RevokeUser(ctx, token.UserID); err != nil {
+		return err
+	}
+
+	event := newUserAuditEvent(ctx, models.AuditActionPasswordReset, &token.UserID)
+	event.ActorID = &token.UserID
+	recordAuditEvent(ctx, s.auditRepo, event)
+	return nil
+}
+
+// VerifyEmail confirms the address a verification token was mailed to
+func (s *authService) VerifyEmail(ctx context.Context, input dtos.VerifyEmailRequest) error {
+	token, err := s.redeemActionToken(ctx, models.ActionEmailVerification, input.Token)
+	if err != nil {
+		return err
+	}
+
+	verified, err := s.userRepo.MarkEmailVerified(ctx, token.UserID, token.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrInvalidActionToken
+	}
+	return nil
+}
+
+// ResendVerification mails a new verification link to the principal
+func (s *authService) ResendVerification(ctx context.Context, principal *models.Principal) error {
+	if principal == nil {
+		return utils.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return utils.ErrUserNotFound
+	}
+	if user.EmailVerified {
+		return ErrEmailVerified
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// VerifyPhone confirms the phone number a verification token was texted to
+func (s *authService) VerifyPhone(ctx context.Context, input dtos.VerifyPhoneRequest) error {
+	token, err := s.redeemActionToken(ctx, models.ActionPhoneVerification, input.Token)
+	if err != nil {
+		return err
+	}
+
+	verified, err := s.userRepo.MarkPhoneVerified(ctx, token.UserID, token.Phone)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrInvalidActionToken
+	}
+	return nil
+}
+
+// ResendPhoneVerification texts a new verification link to the principal's phone number
+func (s *authService) ResendPhoneVerification(ctx context.Context, principal *models.Principal) error {
+	if principal == nil {
+		return utils.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return utils.ErrUserNotFound
+	}
+	if user.PhoneVerified {
+		return ErrPhoneVerified
+	}
+
+	return s.sendPhoneVerification(ctx, user)
+}
+
+// sendVerification mails a link that confirms the user's current email address
+func (s *authService) sendVerification(ctx context.Context, user *models.User) error {
+	token, err := s.issueActionToken(ctx, user, models.ActionEmailVerification, EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Follow this link within %s to confirm your email address:\n\n%s",
+			EmailVerificationTTL, actionLink("/verify-email", token)),
+	})
+}
+
+// sendPhoneVerification texts a link that confirms the user's current phone number
+func (s *authService) sendPhoneVerification(ctx context.Context, user *models.User) error {
+	token, err := s.issueActionToken(ctx, user, models.ActionPhoneVerification, PhoneVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.texter.Send(ctx, sms.Message{
+		To:   user.PhoneNumber,
+		Body: fmt.Sprintf("Confirm your phone number for %s: %s", MFAIssuer, actionLink("/verify-phone", token)),
+	})
+}
+
+// issueActionToken replaces the user's outstanding tokens for purpose with a new one and returns it
+func (s *authService) issueActionToken(ctx context.Context, user *models.User, purpose models.ActionPurpose, ttl time.Duration) (string, error) {
+	if err := s.actionTokenRepo.InvalidateAll(ctx, user.ID, purpose); err != nil {
+		return "", err
+	}
+
+	token, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.actionTokenRepo.Create(ctx, &models.ActionToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		Email:     user.Email,
+		Phone:     user.PhoneNumber,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// redeemActionToken marks a token as used and returns it, or ErrInvalidActionToken if it is unknown,
+// expired or was already used
+func (s *authService) redeemActionToken(ctx context.Context, purpose models.ActionPurpose, token string) (*models.ActionToken, error) {
+	record, err := s.actionTokenRepo.FindByHash(ctx, purpose, hashOpaqueToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || !record.IsUsable(time.Now()) {
+		return nil, ErrInvalidActionToken
+	}
+
+	used, err := s.actionTokenRepo.MarkUsed(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrInvalidActionToken
+	}
+
+	return record, nil
+}
+
+// actionLink builds the link for a mailed token
+func actionLink(path, token string) string {
+	return AppURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,47 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// CurrencyLoader applies the stored currency overrides to the registry
+type CurrencyLoader interface {
+	LoadOverrides(ctx context.Context) error
+}
+
+// CurrencyRefreshWorker periodically reloads currency overrides so changes made through other replicas apply
+type CurrencyRefreshWorker struct {
+	loader   CurrencyLoader
+	interval time.Duration
+	log      zerolog.Logger
+}
+
+func NewCurrencyRefreshWorker(loader CurrencyLoader, interval time.Duration, log zerolog.Logger) *CurrencyRefreshWorker {
+	return &CurrencyRefreshWorker{
+		loader:   loader,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *CurrencyRefreshWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.loader.LoadOverrides(ctx); err != nil {
+					w.log.Error().Err(err).Msg("Failed to reload currency overrides")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,56 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// HoldExpirer voids pending holds whose expiry has passed
+type HoldExpirer interface {
+	ExpireHolds(ctx context.Context, now time.Time) (int, error)
+}
+
+// HoldExpiryWorker periodically voids expired authorization holds
+type HoldExpiryWorker struct {
+	expirer  HoldExpirer
+	interval time.Duration
+	log      zerolog.Logger
+}
+
+func NewHoldExpiryWorker(expirer HoldExpirer, interval time.Duration, log zerolog.Logger) *HoldExpiryWorker {
+	return &HoldExpiryWorker{
+		expirer:  expirer,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *HoldExpiryWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				w.run(ctx, now)
+			}
+		}
+	}()
+}
+
+func (w *HoldExpiryWorker) run(ctx context.Context, now time.Time) {
+	expired, err := w.expirer.ExpireHolds(ctx, now)
+	if err != nil {
+		w.log.Error().Err(err).Msg("Failed to expire holds")
+		return
+	}
+	if expired > 0 {
+		w.log.Info().Int("count", expired).Msg("Expired pending holds")
+	}
+}
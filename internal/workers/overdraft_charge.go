@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// OverdraftCharger charges daily interest and fees to overdrawn balances
+type OverdraftCharger interface {
+	ChargeOverdrafts(ctx context.Context, now time.Time) (int, error)
+}
+
+// OverdraftChargeWorker periodically charges overdrawn balances for each day since they were last charged
+type OverdraftChargeWorker struct {
+	charger  OverdraftCharger
+	interval time.Duration
+	log      zerolog.Logger
+}
+
+func NewOverdraftChargeWorker(charger OverdraftCharger, interval time.Duration, log zerolog.Logger) *OverdraftChargeWorker {
+	return &OverdraftChargeWorker{
+		charger:  charger,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *OverdraftChargeWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				w.run(ctx, now)
+			}
+		}
+	}()
+}
+
+func (w *OverdraftChargeWorker) run(ctx context.Context, now time.Time) {
+	charged, err := w.charger.ChargeOverdrafts(ctx, now)
+	if err != nil {
+		w.log.Error().Err(err).Int("count", charged).Msg("Failed to charge some overdrafts")
+	}
+	if charged > 0 {
+		w.log.Info().Int("count", charged).Msg("Posted overdraft charges")
+	}
+}
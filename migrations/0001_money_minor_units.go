@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+)
+
+// legacyAmount is the shape of a document whose amount is still stored as a double
+type legacyAmount struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Amount   float64            `bson:"amount"`
+	Currency string             `bson:"currency"`
+}
+
+// moneyMinorUnits rewrites float amounts in balances and transactions as integer minor units
+func moneyMinorUnits(ctx context.Context, db *mongo.Database) error {
+	for _, name := range []string{models.BalanceCollection, models.TransactionCollection} {
+		if err := convertAmounts(ctx, db.Collection(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertAmounts(ctx context.Context, col *mongo.Collection) error {
+	cursor, err := col.Find(ctx, bson.M{"amount": bson.M{"$type": "double"}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc legacyAmount
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		amount := money.FromFloat(doc.Amount, doc.Currency)
+		_, err := col.UpdateOne(ctx,
+			bson.M{"_id": doc.ID, "amount": doc.Amount},
+			bson.M{"$set": bson.M{"amount": int64(amount)}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// ledgerOpeningBalances posts an opening-balance journal entry for every balance that existed
+// before the ledger, so that each balance equals the sum of its postings
+func ledgerOpeningBalances(ctx context.Context, db *mongo.Database) error {
+	balances := db.Collection(models.BalanceCollection)
+	entries := db.Collection(models.JournalEntryCollection)
+
+	cursor, err := balances.Find(ctx, bson.M{"amount": bson.M{"$ne": 0}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var balance models.Balance
+		if err := cursor.Decode(&balance); err != nil {
+			return err
+		}
+
+		accountID := balance.AccountID
+		customer := models.Posting{
+			LedgerAccount: models.CustomerLedgerAccount(accountID),
+			AccountID:     &accountID,
+			Direction:     models.PostingDirectionCredit,
+			Amount:        balance.Amount,
+			Currency:      balance.Currency,
+		}
+		system := models.Posting{
+			LedgerAccount: models.LedgerAccountOpeningBalance,
+			Direction:     models.PostingDirectionDebit,
+			Amount:        balance.Amount,
+			Currency:      balance.Currency,
+		}
+		if balance.Amount < 0 {
+			customer.Direction, system.Direction = models.PostingDirectionDebit, models.PostingDirectionCredit
+			customer.Amount, system.Amount = -balance.Amount, -balance.Amount
+		}
+
+		entry := models.JournalEntry{
+			Kind:        models.JournalEntryKindOpeningBalance,
+			Description: "Opening balance",
+			Postings:    []models.Posting{system, customer},
+			CreatedAt:   time.Now(),
+		}
+		if _, err := entries.InsertOne(ctx, entry); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// accountRoles assigns the customer role to accounts created before roles existed
+func accountRoles(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(models.AccountCollection).UpdateMany(ctx,
+		bson.M{"role": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"role": models.RoleCustomer}},
+	)
+	return err
+}
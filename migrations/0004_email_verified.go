@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// emailVerified treats accounts created before email verification existed as verified
+func emailVerified(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(models.AccountCollection).UpdateMany(ctx,
+		bson.M{"email_verified": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"email_verified": true}},
+	)
+	return err
+}
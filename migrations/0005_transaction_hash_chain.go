@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// transactionHashChain links the transactions written before the hash chain existed into one chain per
+// account, oldest first. It runs before the server accepts writes, so no account has a chain yet.
+func transactionHashChain(ctx context.Context, db *mongo.Database) error {
+	transactions := db.Collection(models.TransactionCollection)
+
+	opts := options.Find().SetSort(bson.D{
+		{Key: "account_id", Value: 1},
+		{Key: "transaction_date", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+	cursor, err := transactions.Find(ctx, bson.M{"chain_sequence": bson.M{"$exists": false}}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var previous *models.Transaction
+	for cursor.Next(ctx) {
+		transaction := &models.Transaction{}
+		if err := cursor.Decode(transaction); err != nil {
+			return err
+		}
+
+		if previous != nil && previous.AccountID != transaction.AccountID {
+			previous = nil
+		}
+		transaction.ChainTo(previous)
+
+		_, err := transactions.UpdateOne(ctx,
+			bson.M{"_id": transaction.ID},
+			bson.M{"$set": bson.M{
+				"chain_sequence": transaction.ChainSequence,
+				"previous_hash":  transaction.PreviousHash,
+				"hash":           transaction.Hash,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		previous = transaction
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// phoneVerified treats accounts created before phone verification existed as verified
+func phoneVerified(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(models.AccountCollection).UpdateMany(ctx,
+		bson.M{"phone_verified": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"phone_verified": true}},
+	)
+	return err
+}
@@ -0,0 +1,112 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+)
+
+// Server errors returned when dropping an index from a missing collection or an index that does not exist
+const (
+	namespaceNotFoundCode = 26
+	indexNotFoundCode     = 27
+)
+
+// usersAndAccounts splits every account into a user holding its credentials and an account holding its
+// money. Both keep the ID of the original account, so balances, transactions, tokens, API keys and audit
+// events stay valid. The user becomes the owner of a current account.
+func usersAndAccounts(ctx context.Context, db *mongo.Database) error {
+	accounts := db.Collection(models.AccountCollection)
+	users := db.Collection(models.UserCollection)
+
+	// Accounts no longer carry credentials, so their identity indexes go
+	for _, name := range []string{"email_1", "phone_number_1", "role_1"} {
+		if err := dropIndex(ctx, accounts, name); err != nil {
+			return err
+		}
+	}
+
+	cursor, err := accounts.Find(ctx, bson.M{"email": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var identity bson.M
+		if err := cursor.Decode(&identity); err != nil {
+			return err
+		}
+		id, ok := identity["_id"].(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+
+		if _, err := users.ReplaceOne(ctx, bson.M{"_id": id}, identity, options.Replace().SetUpsert(true)); err != nil {
+			return err
+		}
+
+		createdAt, ok := identity["created_at"].(primitive.DateTime)
+		if !ok {
+			createdAt = primitive.NewDateTimeFromTime(time.Now())
+		}
+		account := models.Account{
+			ID:      id,
+			Product: models.AccountProductCurrent,
+			Holders: []models.AccountHolder{
+				{UserID: id, Role: models.HolderRoleOwner, AddedAt: createdAt.Time()},
+			},
+			CreatedAt: createdAt.Time(),
+			UpdatedAt: time.Now(),
+		}
+		if _, err := accounts.ReplaceOne(ctx, bson.M{"_id": id}, account); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	// Tokens and API keys now belong to users
+	renames := []struct {
+		collection string
+		oldIndex   string
+	}{
+		{models.RefreshTokenCollection, "account_id_1"},
+		{models.ActionTokenCollection, "account_id_1_purpose_1"},
+		{models.APIKeyCollection, "account_id_1_created_at_-1"},
+	}
+	for _, r := range renames {
+		col := db.Collection(r.collection)
+		if _, err := col.UpdateMany(ctx,
+			bson.M{"account_id": bson.M{"$exists": true}},
+			bson.M{"$rename": bson.M{"account_id": "user_id"}},
+		); err != nil {
+			return err
+		}
+		if err := dropIndex(ctx, col, r.oldIndex); err != nil {
+			return err
+		}
+	}
+
+	// Audit events are never rewritten; identity events that name the account are read as naming the
+	// user, see AuditEvent.TargetUser
+	return nil
+}
+
+// dropIndex drops the named index, ignoring indexes that were never created
+func dropIndex(ctx context.Context, col *mongo.Collection, name string) error {
+	_, err := col.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == namespaceNotFoundCode || cmdErr.Code == indexNotFoundCode) {
+		return nil
+	}
+	return err
+}
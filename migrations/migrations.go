@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Collection related constants
+const (
+	MigrationCollection = "schema_migrations"
+)
+
+// Migration is a one-time data conversion applied at startup
+type Migration struct {
+	ID string
+	Up func(ctx context.Context, db *mongo.Database) error
+}
+
+// appliedMigration is the record stored once a migration has run
+type appliedMigration struct {
+	ID        string    `bson:"_id"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// all lists every migration in the order it must be applied
+var all = []Migration{
+	{ID: "0001_money_minor_units", Up: moneyMinorUnits},
+	{ID: "0002_ledger_opening_balances", Up: ledgerOpeningBalances},
+	{ID: "0003_account_roles", Up: accountRoles},
+	{ID: "0004_email_verified", Up: emailVerified},
+	{ID: "0005_transaction_hash_chain", Up: transactionHashChain},
+	{ID: "0006_phone_verified", Up: phoneVerified},
+	{ID: "0007_users_and_accounts", Up: usersAndAccounts},
+}
+
+// Run applies every migration that has not been recorded in the schema_migrations collection
+func Run(ctx context.Context, db *mongo.Database) error {
+	col := db.Collection(MigrationCollection)
+
+	for _, m := range all {
+		count, err := col.CountDocuments(ctx, bson.M{"_id": m.ID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := m.Up(ctx, db); err != nil {
+			log.Error().Err(err).Str("migration", m.ID).Msg("Failed to apply migration")
+			return err
+		}
+
+		if _, err := col.InsertOne(ctx, appliedMigration{ID: m.ID, AppliedAt: time.Now()}); err != nil {
+			return err
+		}
+
+		log.Info().Str("migration", m.ID).Msg("Migration applied successfully")
+	}
+
+	return nil
+}
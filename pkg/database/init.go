@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/migrations"
 
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -19,9 +20,21 @@ func InitializeCollections(client *mongo.Client, dbName string) error {
 
 	// Initialize models with their indexes
 	models := []interface{}{
+		&models.User{},
 		&models.Account{},
 		&models.Transaction{},
 		&models.Balance{},
+		&models.IdempotencyKey{},
+		&models.JournalEntry{},
+		&models.RefreshToken{},
+		&models.APIKey{},
+		&models.ActionToken{},
+		&models.LoginAttempt{},
+		&models.RateLimitBucket{},
+		&models.AuditEvent{},
+		&models.FXQuote{},
+		&models.CurrencyOverride{},
+		&models.LimitPolicy{},
 	}
 
 	// Initialize each model's indexes
@@ -33,6 +46,11 @@ func InitializeCollections(client *mongo.Client, dbName string) error {
 		}
 	}
 
+	// Apply pending one-time data migrations
+	if err := migrations.Run(ctx, db); err != nil {
+		return err
+	}
+
 	log.Info().Msg("All collections initialized successfully")
 	return nil
 }
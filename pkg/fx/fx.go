@@ -0,0 +1,154 @@
+// Package fx provides the foreign-exchange rates used to convert money between currencies
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateUnavailable is returned when a provider has no rate for a currency pair
+var ErrRateUnavailable = errors.New("no exchange rate for currency pair")
+
+// RateProvider returns mid-market rates. A rate is the number of units of to bought by one unit of
+// from, in major units. Implementations must be safe for concurrent use.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// defaultRates are indicative rates for local development, used when no rates file is configured
+var defaultRates = map[string]string{
+	"USD/EUR": "0.92",
+	"USD/GBP": "0.79",
+	"USD/JPY": "151.50",
+	"USD/KWD": "0.308",
+	"EUR/GBP": "0.86",
+}
+
+// New returns the provider for source: "static" serves a bundled table of indicative rates, any other
+// value is the path of a JSON file mapping pairs such as "USD/EUR" to decimal rates.
+func New(source string) (RateProvider, error) {
+	if source == "" || source == "static" {
+		return NewStaticProvider(defaultRates)
+	}
+	return NewFileProvider(source)
+}
+
+// Pair returns the key of a currency pair in a rate table
+func Pair(from, to string) string {
+	return strings.ToUpper(from) + "/" + strings.ToUpper(to)
+}
+
+type staticProvider struct {
+	rates map[string]*big.Rat
+}
+
+// NewStaticProvider returns a provider serving the given rates, keyed by pair such as "USD/EUR". The
+// inverse of each pair is derived unless it is listed too.
+func NewStaticProvider(rates map[string]string) (RateProvider, error) {
+	table, err := parseRates(rates)
+	if err != nil {
+		return nil, err
+	}
+	return &staticProvider{rates: table}, nil
+}
+
+func (p *staticProvider) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	return lookup(p.rates, from, to)
+}
+
+type fileProvider struct {
+	path string
+
+	mu       sync.Mutex
+	modified time.Time
+	rates    map[string]*big.Rat
+}
+
+// NewFileProvider returns a provider reading rates from the JSON file at path. The file is read again
+// whenever it changes, so rates can be updated without a restart.
+func NewFileProvider(path string) (RateProvider, error) {
+	p := &fileProvider{path: path}
+	if _, err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *fileProvider) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	rates, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	return lookup(rates, from, to)
+}
+
+// load returns the current rate table, reading the file again if it was modified since the last read
+func (p *fileProvider) load() (map[string]*big.Rat, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FX rates: %w", err)
+	}
+	if p.rates != nil && info.ModTime().Equal(p.modified) {
+		return p.rates, nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FX rates: %w", err)
+	}
+	var rates map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse FX rates: %w", err)
+	}
+	table, err := parseRates(rates)
+	if err != nil {
+		return nil, err
+	}
+
+	p.rates = table
+	p.modified = info.ModTime()
+	return table, nil
+}
+
+// parseRates converts decimal rates into exact fractions and adds the inverse of every pair
+func parseRates(rates map[string]string) (map[string]*big.Rat, error) {
+	table := make(map[string]*big.Rat, 2*len(rates))
+	for pair, value := range rates {
+		from, to, ok := strings.Cut(pair, "/")
+		if !ok || len(from) != 3 || len(to) != 3 {
+			return nil, fmt.Errorf("invalid FX currency pair %q", pair)
+		}
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid FX rate %q for %s", value, pair)
+		}
+		table[Pair(from, to)] = rate
+	}
+
+	for pair := range rates {
+		from, to, _ := strings.Cut(pair, "/")
+		if _, ok := table[Pair(to, from)]; !ok {
+			table[Pair(to, from)] = new(big.Rat).Inv(table[Pair(from, to)])
+		}
+	}
+	return table, nil
+}
+
+func lookup(rates map[string]*big.Rat, from, to string) (*big.Rat, error) {
+	rate, ok := rates[Pair(from, to)]
+	if !ok {
+		return nil, ErrRateUnavailable
+	}
+	// Callers get a copy so the table cannot be changed through the result
+	return new(big.Rat).Set(rate), nil
+}
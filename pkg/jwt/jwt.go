@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -11,22 +13,50 @@ import (
 var (
 	// SecretKey is used to sign and verify JWTs
 	SecretKey = []byte(utils.GetEnv("JWT_SECRET", "your-secret-key"))
+
+	// Issuer is written to and required in the iss claim
+	Issuer = utils.GetEnv("JWT_ISSUER", "axis-be")
+
+	// Audience is written to and required in the aud claim
+	Audience = utils.GetEnv("JWT_AUDIENCE", "axis-api")
+
+	// AccessTokenTTL is how long an access token is valid; refresh tokens are used to get a new one
+	AccessTokenTTL = utils.GetDurationEnv("JWT_EXPIRATION", 15*time.Minute)
+
+	// MFAAudience is the aud claim of MFA challenge tokens, so they are never accepted as access tokens
+	MFAAudience = Audience + ":mfa"
+
+	// MFAChallengeTTL is how long the second login step may take after the password was checked
+	MFAChallengeTTL = utils.GetDurationEnv("MFA_CHALLENGE_TTL", 5*time.Minute)
 )
 
-// Claims represents the claims in the JWT
+// Claims represents the claims in the JWT. The subject is the hex ObjectID of the user and the
+// session ID is the refresh token family the access token was issued from.
 type Claims struct {
-	UserID uint `json:"user_id"`
+	SessionID string `json:"sid"`
+	Role      string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a new JWT token for a given user ID
-func GenerateToken(userID uint) (string, error) {
+// GenerateToken creates a new access token for the user with the given hex ObjectID and role within a session
+func GenerateToken(userID, sessionID, role string) (string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+
+	now := time.Now()
 	claims := Claims{
-		UserID: userID,
+		SessionID: sessionID,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token expires in 24 hours
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   userID,
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{Audience},
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
@@ -46,15 +76,73 @@ func ValidateToken(tokenString string) (*Claims, error) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return SecretKey, nil
-	})
+	}, jwt.WithIssuer(Issuer), jwt.WithAudience(Audience), jwt.WithExpirationRequired())
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Subject != "" && claims.SessionID != "" {
 		return claims, nil
 	}
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// GenerateMFAToken creates a challenge token proving that the user with the given hex ObjectID passed
+// the password check. It is exchanged for an access token together with a second factor. The token ID
+// is returned so the caller can make the token single-use.
+func GenerateMFAToken(userID string) (string, string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   userID,
+		Issuer:    Issuer,
+		Audience:  jwt.ClaimStrings{MFAAudience},
+		ID:        tokenID,
+		ExpiresAt: jwt.NewNumericDate(now.Add(MFAChallengeTTL)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString(SecretKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signedToken, tokenID, nil
+}
+
+// ValidateMFAToken validates a challenge token and returns the hex ObjectID of its user and the token ID
+func ValidateMFAToken(tokenString string) (string, string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return SecretKey, nil
+	}, jwt.WithIssuer(Issuer), jwt.WithAudience(MFAAudience), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid && claims.Subject != "" && claims.ID != "" {
+		return claims.Subject, claims.ID, nil
+	}
+
+	return "", "", fmt.Errorf("invalid token")
+}
+
+// newTokenID returns a random identifier for the jti claim
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
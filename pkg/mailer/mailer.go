@@ -0,0 +1,56 @@
+// Package mailer sends transactional emails such as password reset links
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New returns the mailer for output: "stdout" prints messages, any other value is a file path that
+// messages are appended to. Both are meant for local development.
+func New(output string) (Mailer, error) {
+	if output == "" || output == "stdout" {
+		return NewWriterMailer(os.Stdout), nil
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mail output: %w", err)
+	}
+	return NewWriterMailer(file), nil
+}
+
+type writerMailer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterMailer returns a mailer that writes every message to w
+func NewWriterMailer(w io.Writer) Mailer {
+	return &writerMailer{w: w}
+}
+
+func (m *writerMailer) Send(ctx context.Context, message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.w, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().UTC().Format(time.RFC1123Z), message.To, message.Subject, message.Body)
+	return err
+}
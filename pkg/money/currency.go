@@ -0,0 +1,103 @@
+package money
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// iso4217 lists the active ISO 4217 currencies. Fund codes and withdrawn currencies are disabled;
+// precious metals and other codes without minor units are left out.
+//
+//go:embed iso4217.json
+var iso4217 []byte
+
+// Currency is an entry of the currency registry
+type Currency struct {
+	Code        string `json:"code"`         // ISO 4217 alphabetic code
+	NumericCode string `json:"numeric_code"` // ISO 4217 numeric code
+	Name        string `json:"name"`
+	MinorUnits  int    `json:"minor_units"` // Decimal places amounts may have
+	Enabled     bool   `json:"enabled"`     // Whether new money may be moved in the currency
+}
+
+// CurrencyOverride replaces registry values of a currency. Unset fields keep their ISO 4217 value.
+type CurrencyOverride struct {
+	Code       string
+	MinorUnits *int
+	Enabled    *bool
+}
+
+var registry = struct {
+	mu      sync.RWMutex
+	base    map[string]Currency
+	current map[string]Currency
+}{}
+
+func init() {
+	var currencies []Currency
+	if err := json.Unmarshal(iso4217, &currencies); err != nil {
+		panic("money: invalid embedded ISO 4217 table: " + err.Error())
+	}
+
+	registry.base = make(map[string]Currency, len(currencies))
+	for _, c := range currencies {
+		registry.base[c.Code] = c
+	}
+	registry.current = registry.base
+}
+
+// LookupCurrency returns the registry entry for an upper-case alphabetic code
+func LookupCurrency(code string) (Currency, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	c, ok := registry.current[code]
+	return c, ok
+}
+
+// IsEnabled reports whether code is a registered currency that money may be moved in
+func IsEnabled(code string) bool {
+	c, ok := LookupCurrency(code)
+	return ok && c.Enabled
+}
+
+// Currencies returns the registry ordered by code
+func Currencies() []Currency {
+	registry.mu.RLock()
+	currencies := make([]Currency, 0, len(registry.current))
+	for _, c := range registry.current {
+		currencies = append(currencies, c)
+	}
+	registry.mu.RUnlock()
+
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+	return currencies
+}
+
+// SetOverrides applies overrides on top of the ISO 4217 table, replacing any set before. Overrides
+// for codes missing from the table are ignored.
+func SetOverrides(overrides []CurrencyOverride) {
+	current := make(map[string]Currency, len(registry.base))
+	for code, c := range registry.base {
+		current[code] = c
+	}
+	for _, o := range overrides {
+		c, ok := current[o.Code]
+		if !ok {
+			continue
+		}
+		if o.MinorUnits != nil {
+			c.MinorUnits = *o.MinorUnits
+		}
+		if o.Enabled != nil {
+			c.Enabled = *o.Enabled
+		}
+		current[o.Code] = c
+	}
+
+	registry.mu.Lock()
+	registry.current = current
+	registry.mu.Unlock()
+}
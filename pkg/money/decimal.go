@@ -0,0 +1,59 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Decimal is the exact textual form of an amount as it appears in a request or response.
+// It accepts both JSON numbers and JSON strings so that values such as 0.1 are never
+// routed through a float64.
+type Decimal string
+
+// NewDecimal renders an Amount of the given currency as a Decimal
+func NewDecimal(amount Amount, currency string) Decimal {
+	return Decimal(amount.Format(currency))
+}
+
+// Amount converts the decimal into an Amount in the minor units of currency
+func (d Decimal) Amount(currency string) (Amount, error) {
+	return Parse(string(d), currency)
+}
+
+// UnmarshalJSON keeps the literal digits of a JSON number or string
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = ""
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*d = Decimal(strings.TrimSpace(s))
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return ErrInvalidFormat
+	}
+	*d = Decimal(n.String())
+	return nil
+}
+
+// MarshalJSON writes the decimal as a JSON number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("null"), nil
+	}
+	var n json.Number
+	if strings.HasPrefix(string(d), `"`) || json.Unmarshal([]byte(d), &n) != nil {
+		return json.Marshal(string(d))
+	}
+	return []byte(d), nil
+}
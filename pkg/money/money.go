@@ -0,0 +1,120 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidFormat is returned when a value is not a plain decimal number
+	ErrInvalidFormat = errors.New("invalid amount format")
+	// ErrTooManyDecimals is returned when a value has more decimals than the currency allows
+	ErrTooManyDecimals = errors.New("amount has more decimal places than the currency allows")
+	// ErrOverflow is returned when a value does not fit in an Amount
+	ErrOverflow = errors.New("amount is too large")
+)
+
+// DefaultMinorUnits is the number of decimal places used by currencies missing from the registry
+const DefaultMinorUnits = 2
+
+// Amount is an exact monetary amount expressed in the minor units of its currency
+// (cents for USD, fils for KWD, yen for JPY)
+type Amount int64
+
+// MinorUnits returns the number of decimal places used by the given currency, as set in the registry
+func MinorUnits(currency string) int {
+	if c, ok := LookupCurrency(strings.ToUpper(currency)); ok {
+		return c.MinorUnits
+	}
+	return DefaultMinorUnits
+}
+
+// Parse converts a decimal string such as "10.25" into an Amount in the minor units of currency
+func Parse(value, currency string) (Amount, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, ErrInvalidFormat
+	}
+
+	negative := false
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	whole, frac, _ := strings.Cut(value, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidFormat
+	}
+
+	units := MinorUnits(currency)
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > units {
+		return 0, ErrTooManyDecimals
+	}
+	frac += strings.Repeat("0", units-len(frac))
+
+	digits := strings.TrimLeft(whole+frac, "0")
+	if digits == "" {
+		return 0, nil
+	}
+
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, ErrOverflow
+	}
+	if negative {
+		minor = -minor
+	}
+
+	return Amount(minor), nil
+}
+
+// FromFloat converts a legacy floating point value into an Amount, rounding to the
+// nearest minor unit of currency. It must only be used to migrate stored data.
+func FromFloat(value float64, currency string) Amount {
+	scale := math.Pow10(MinorUnits(currency))
+	return Amount(math.Round(value * scale))
+}
+
+// Format renders the amount as a decimal string using the precision of currency
+func (a Amount) Format(currency string) string {
+	units := MinorUnits(currency)
+	sign := ""
+	minor := int64(a)
+	if minor < 0 {
+		sign = "-"
+	}
+
+	digits := strconv.FormatUint(absInt64(minor), 10)
+	if units == 0 {
+		return sign + digits
+	}
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+
+	return fmt.Sprintf("%s%s.%s", sign, digits[:len(digits)-units], digits[len(digits)-units:])
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func absInt64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
@@ -0,0 +1,70 @@
+// Package ratelimit implements token-bucket rate limiting over pluggable bucket stores.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Limit is a token-bucket budget: up to Burst requests at once, refilled evenly over Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Validate returns an error unless the limit allows at least one request per a positive period
+func (l Limit) Validate() error {
+	if l.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1, got %d", l.Burst)
+	}
+	if l.Period <= 0 {
+		return fmt.Errorf("period must be positive, got %s", l.Period)
+	}
+	return nil
+}
+
+// Interval returns the time it takes to refill one token. The limit must be valid.
+func (l Limit) Interval() time.Duration {
+	return l.Period / time.Duration(l.Burst)
+}
+
+// Result describes the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // Whole tokens left in the bucket
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next token is available, when Allowed is false
+}
+
+// Store keeps token buckets by key. Take must refill and draw from the bucket atomically, so that
+// concurrent requests cannot spend the same token.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Refill returns the tokens in a bucket that held tokens at updatedAt, after refilling it until now
+func Refill(limit Limit, tokens float64, updatedAt, now time.Time) float64 {
+	elapsed := now.Sub(updatedAt)
+	if elapsed <= 0 {
+		return tokens
+	}
+	return math.Min(float64(limit.Burst), tokens+float64(elapsed)/float64(limit.Interval()))
+}
+
+// NewResult describes a bucket left with tokens after a request was allowed or refused
+func NewResult(limit Limit, tokens float64, allowed bool) Result {
+	interval := float64(limit.Interval())
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) * interval),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * interval)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+// Package sms sends transactional text messages such as phone number verification links
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Message is a text message to a phone number in E.164 format
+type Message struct {
+	To   string
+	Body string
+}
+
+// Sender delivers messages. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New returns the sender for output: "stdout" prints messages, any other value is a file path that
+// messages are appended to. Both are meant for local development.
+func New(output string) (Sender, error) {
+	if output == "" || output == "stdout" {
+		return NewWriterSender(os.Stdout), nil
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SMS output: %w", err)
+	}
+	return NewWriterSender(file), nil
+}
+
+type writerSender struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSender returns a sender that writes every message to w
+func NewWriterSender(w io.Writer) Sender {
+	return &writerSender{w: w}
+}
+
+func (s *writerSender) Send(ctx context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.w, "Date: %s\nSMS To: %s\n\n%s\n\n",
+		time.Now().UTC().Format(time.RFC1123Z), message.To, message.Body)
+	return err
+}
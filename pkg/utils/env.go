@@ -1,6 +1,10 @@
 package utils
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 // GetEnv retrieves an environment variable value or returns a default value if not set
 func GetEnv(key, defaultValue string) string {
@@ -9,3 +13,24 @@ func GetEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// GetDurationEnv retrieves an environment variable as a time.Duration (e.g. "30s", "5m")
+// or returns a default value if it is not set or invalid
+func GetDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// GetIntEnv retrieves an environment variable as an int or returns a default value if it is not set or invalid
+func GetIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -5,10 +5,13 @@ import (
 	"net/http"
 )
 
-// CustomError represents a custom error with HTTP status code and message
+// CustomError represents a custom error with HTTP status code and message. Errors clients act on
+// programmatically also carry a Reason and structured Details.
 type CustomError struct {
-	Code    int    `json:"-"`
-	Message string `json:"error"`
+	Code    int         `json:"-"`
+	Message string      `json:"error"`
+	Reason  string      `json:"reason,omitempty"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // Error implements the error interface
@@ -24,6 +27,11 @@ func NewError(code int, message string) *CustomError {
 	}
 }
 
+// Error reasons
+const (
+	ReasonLimitExceeded = "limit_exceeded"
+)
+
 // Common application errors
 var (
 	ErrInvalidAmount = NewError(
@@ -36,6 +44,231 @@ var (
 		"insufficient balance",
 	)
 
+	ErrSelfTransfer = NewError(
+		http.StatusBadRequest,
+		"source and destination accounts must be different",
+	)
+
+	ErrCurrencyMismatch = NewError(
+		http.StatusUnprocessableEntity,
+		"destination account does not hold a balance in this currency",
+	)
+
+	ErrIdempotencyKeyReused = NewError(
+		http.StatusUnprocessableEntity,
+		"idempotency key was already used with a different request",
+	)
+
+	ErrIdempotencyKeyInProgress = NewError(
+		http.StatusConflict,
+		"a request with this idempotency key is still being processed",
+	)
+
+	ErrTransactionNotFound = NewError(
+		http.StatusNotFound,
+		"transaction not found",
+	)
+
+	ErrInvalidCursor = NewError(
+		http.StatusBadRequest,
+		"invalid pagination cursor",
+	)
+
+	ErrHoldNotFound = NewError(
+		http.StatusNotFound,
+		"hold not found",
+	)
+
+	ErrHoldNotPending = NewError(
+		http.StatusConflict,
+		"hold is no longer pending",
+	)
+
+	ErrHoldExpired = NewError(
+		http.StatusConflict,
+		"hold has expired",
+	)
+
+	ErrCaptureExceedsHold = NewError(
+		http.StatusBadRequest,
+		"capture amount exceeds the held amount",
+	)
+
+	ErrTransactionNotReversible = NewError(
+		http.StatusConflict,
+		"only completed transactions can be reversed",
+	)
+
+	ErrAlreadyReversed = NewError(
+		http.StatusConflict,
+		"transaction has already been fully reversed",
+	)
+
+	ErrReversalExceedsOriginal = NewError(
+		http.StatusBadRequest,
+		"reversal amount exceeds the amount left to reverse",
+	)
+
+	ErrPartialReversalNotAllowed = NewError(
+		http.StatusBadRequest,
+		"credits can only be reversed in full",
+	)
+
+	ErrConversionNotReversible = NewError(
+		http.StatusConflict,
+		"currency conversions cannot be reversed",
+	)
+
+	ErrQuoteNotFound = NewError(
+		http.StatusNotFound,
+		"quote not found",
+	)
+
+	ErrQuoteExpired = NewError(
+		http.StatusConflict,
+		"quote has expired",
+	)
+
+	ErrQuoteUsed = NewError(
+		http.StatusConflict,
+		"quote has already been used",
+	)
+
+	ErrUnsupportedCurrencyPair = NewError(
+		http.StatusUnprocessableEntity,
+		"no exchange rate for this currency pair",
+	)
+
+	ErrSameCurrency = NewError(
+		http.StatusBadRequest,
+		"sell and buy currencies must be different",
+	)
+
+	ErrCurrencyNotFound = NewError(
+		http.StatusNotFound,
+		"currency not found",
+	)
+
+	ErrCurrencyDisabled = NewError(
+		http.StatusUnprocessableEntity,
+		"currency is disabled; funds may only be moved out of it",
+	)
+
+	ErrCurrencyInUse = NewError(
+		http.StatusConflict,
+		"the precision of a currency amounts are stored in cannot change",
+	)
+
+	ErrForbidden = NewError(
+		http.StatusForbidden,
+		"you do not have access to this account",
+	)
+
+	ErrAccountNotFound = NewError(
+		http.StatusNotFound,
+		"account not found",
+	)
+
+	ErrInvalidRole = NewError(
+		http.StatusBadRequest,
+		"unknown role",
+	)
+
+	ErrCannotChangeOwnRole = NewError(
+		http.StatusConflict,
+		"admins cannot change their own role",
+	)
+
+	ErrCannotChangeOwnStatus = NewError(
+		http.StatusConflict,
+		"admins cannot change their own status",
+	)
+
+	ErrInvalidStatusChange = NewError(
+		http.StatusConflict,
+		"the user's current status does not allow this change",
+	)
+
+	ErrUserBlocked = NewError(
+		http.StatusForbidden,
+		"user is blocked",
+	)
+
+	ErrUserInactive = NewError(
+		http.StatusForbidden,
+		"user is deactivated",
+	)
+
+	ErrAccountFrozen = NewError(
+		http.StatusForbidden,
+		"account owner is blocked or deactivated",
+	)
+
+	ErrAlreadyHolder = NewError(
+		http.StatusConflict,
+		"user already holds this account",
+	)
+
+	ErrHolderNotFound = NewError(
+		http.StatusNotFound,
+		"user is not a joint holder of this account",
+	)
+
+	ErrInvalidAccountTier = NewError(
+		http.StatusBadRequest,
+		"unknown account tier",
+	)
+
+	ErrInvalidLimitDirection = NewError(
+		http.StatusBadRequest,
+		"limit direction must be deposit or withdrawal",
+	)
+
+	ErrInvalidLimitPolicy = NewError(
+		http.StatusBadRequest,
+		"minimum per transaction exceeds the maximum",
+	)
+
+	ErrLimitPolicyNotFound = NewError(
+		http.StatusNotFound,
+		"limit policy not found",
+	)
+
+	ErrInvalidHolderPermission = NewError(
+		http.StatusBadRequest,
+		"permission cannot be granted to a joint holder",
+	)
+
+	ErrAPIKeyNotFound = NewError(
+		http.StatusNotFound,
+		"API key not found",
+	)
+
+	ErrInvalidAPIKey = NewError(
+		http.StatusUnauthorized,
+		"invalid, expired or revoked API key",
+	)
+
+	ErrAPIKeyScope = NewError(
+		http.StatusForbidden,
+		"API key scope does not allow this operation",
+	)
+
+	ErrAPIKeyManagement = NewError(
+		http.StatusForbidden,
+		"API keys cannot be managed with an API key",
+	)
+
+	ErrInvalidScope = NewError(
+		http.StatusBadRequest,
+		"unknown API key scope",
+	)
+
+	ErrInvalidExpiry = NewError(
+		http.StatusBadRequest,
+		"expires_at must be in the future",
+	)
+
 	ErrInvalidCredentials = NewError(
 		http.StatusUnauthorized,
 		"invalid credentials",
@@ -0,0 +1,209 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockAccessService is a mock implementation of services.AccessService
+type MockAccessService struct {
+	mock.Mock
+}
+
+func (m *MockAccessService) OwnedAccounts(ctx context.Context, principal *models.Principal) ([]primitive.ObjectID, error) {
+	args := m.Called(ctx, principal)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+func (m *MockAccessService) AuthorizeAccount(ctx context.Context, principal *models.Principal, accountID primitive.ObjectID, permission models.Permission) error {
+	args := m.Called(ctx, principal, accountID, permission)
+	return args.Error(0)
+}
+
+func (m *MockAccessService) AuthorizeTransaction(ctx context.Context, principal *models.Principal, transactionID primitive.ObjectID, permission models.Permission) error {
+	args := m.Called(ctx, principal, transactionID, permission)
+	return args.Error(0)
+}
+
+// allowAllAccess returns an access service that lets every principal act on every account
+func allowAllAccess() *MockAccessService {
+	access := new(MockAccessService)
+	access.On("AuthorizeAccount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	access.On("AuthorizeTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return access
+}
+
+var testPrincipal = &models.Principal{UserID: primitive.NewObjectID(), Email: "john@example.com", Status: models.UserStatusActive}
+
+// newAuthenticatedContext builds a context as middleware.Auth leaves it for testPrincipal
+func newAuthenticatedContext(e *echo.Echo, method, target string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
+	var reader *bytes.Buffer
+	if body != nil {
+		jsonBody, _ := json.Marshal(body)
+		reader = bytes.NewBuffer(jsonBody)
+	} else {
+		reader = bytes.NewBuffer(nil)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(middleware.PrincipalKey, testPrincipal)
+	return c, rec
+}
+
+func TestTransactionHandler_AccountOwnership(t *testing.T) {
+	e := echo.New()
+	foreignAccountID := primitive.NewObjectID()
+
+	// The service is never reached when access is denied
+	newHandler := func() (*handlers.TransactionHandler, *MockAccessService) {
+		access := new(MockAccessService)
+		return handlers.NewTransactionHandler(new(MockTransactionService), access), access
+	}
+
+	t.Run("Deposit Into Foreign Account", func(t *testing.T) {
+		handler, access := newHandler()
+		access.On("AuthorizeAccount", mock.Anything, testPrincipal, foreignAccountID, models.PermissionFundsDeposit).Return(utils.ErrForbidden)
+
+		c, rec := newAuthenticatedContext(e, http.MethodPost, "/transactions/deposit", dtos.TransactionRequest{
+			AccountID: foreignAccountID.Hex(),
+			Amount:    "100.00",
+			Currency:  "USD",
+		})
+
+		err := handler.Deposit(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		access.AssertExpectations(t)
+	})
+
+	t.Run("Withdraw From Foreign Account", func(t *testing.T) {
+		handler, access := newHandler()
+		access.On("AuthorizeAccount", mock.Anything, testPrincipal, foreignAccountID, models.PermissionFundsWithdraw).Return(utils.ErrForbidden)
+
+		c, rec := newAuthenticatedContext(e, http.MethodPost, "/transactions/withdraw", dtos.TransactionRequest{
+			AccountID: foreignAccountID.Hex(),
+			Amount:    "100.00",
+			Currency:  "USD",
+		})
+
+		err := handler.Withdraw(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		access.AssertExpectations(t)
+	})
+
+	t.Run("Transfer From Foreign Account", func(t *testing.T) {
+		handler, access := newHandler()
+		access.On("AuthorizeAccount", mock.Anything, testPrincipal, foreignAccountID, models.PermissionFundsWithdraw).Return(utils.ErrForbidden)
+
+		c, rec := newAuthenticatedContext(e, http.MethodPost, "/transactions/transfer", dtos.TransferRequest{
+			SourceAccountID:      foreignAccountID.Hex(),
+			DestinationAccountID: primitive.NewObjectID().Hex(),
+			Amount:               "10.00",
+			Currency:             "USD",
+		})
+
+		err := handler.Transfer(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		access.AssertExpectations(t)
+	})
+
+	t.Run("List Foreign Account Transactions", func(t *testing.T) {
+		handler, access := newHandler()
+		access.On("AuthorizeAccount", mock.Anything, testPrincipal, foreignAccountID, models.PermissionAccountRead).Return(utils.ErrForbidden)
+
+		c, rec := newAuthenticatedContext(e, http.MethodGet, "/", nil)
+		c.SetPath("/accounts/:id/transactions")
+		c.SetParamNames("id")
+		c.SetParamValues(foreignAccountID.Hex())
+
+		err := handler.ListAccountTransactions(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		access.AssertExpectations(t)
+	})
+
+	t.Run("Reverse Foreign Transaction", func(t *testing.T) {
+		handler, access := newHandler()
+		transactionID := primitive.NewObjectID()
+		access.On("AuthorizeTransaction", mock.Anything, testPrincipal, transactionID, models.PermissionTransactionReverse).Return(utils.ErrForbidden)
+
+		c, rec := newAuthenticatedContext(e, http.MethodPost, "/", dtos.ReverseRequest{Reason: "not mine"})
+		c.SetPath("/transactions/:id/reverse")
+		c.SetParamNames("id")
+		c.SetParamValues(transactionID.Hex())
+
+		err := handler.Reverse(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		access.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Transaction", func(t *testing.T) {
+		handler, access := newHandler()
+		transactionID := primitive.NewObjectID()
+		access.On("AuthorizeTransaction", mock.Anything, testPrincipal, transactionID, models.PermissionAccountRead).Return(utils.ErrTransactionNotFound)
+
+		c, rec := newAuthenticatedContext(e, http.MethodGet, "/", nil)
+		c.SetPath("/transactions/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(transactionID.Hex())
+
+		err := handler.GetTransaction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		access.AssertExpectations(t)
+	})
+}
+
+func TestBalanceHandler_AccountOwnership(t *testing.T) {
+	e := echo.New()
+	foreignAccountID := primitive.NewObjectID()
+
+	access := new(MockAccessService)
+	access.On("AuthorizeAccount", mock.Anything, testPrincipal, foreignAccountID, models.PermissionAccountRead).Return(utils.ErrForbidden)
+	handler := handlers.NewBalanceHandler(&services.BalanceService{}, access)
+
+	c, rec := newAuthenticatedContext(e, http.MethodGet, "/", nil)
+	c.SetPath("/balances/:account_id")
+	c.SetParamNames("account_id")
+	c.SetParamValues(foreignAccountID.Hex())
+
+	err := handler.GetBalances(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var response utils.CustomError
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, utils.ErrForbidden.Message, response.Message)
+	access.AssertExpectations(t)
+}
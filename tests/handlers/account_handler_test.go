@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAccountHandler_APIKeyScopes(t *testing.T) {
+	e := echo.New()
+	keyID := primitive.NewObjectID()
+	depositKey := &models.Principal{
+		UserID:   testPrincipal.UserID,
+		Role:     models.RoleCustomer,
+		Status:   models.UserStatusActive,
+		APIKeyID: &keyID,
+		Scopes:   []models.Permission{models.PermissionFundsDeposit},
+	}
+
+	newHandler := func() (*handlers.AccountHandler, *mocks.MockAccountRepository) {
+		accountRepo := new(mocks.MockAccountRepository)
+		auditRepo := new(mocks.MockAuditEventRepository)
+		auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+		accountService := services.NewAccountService(accountRepo, new(mocks.MockUserRepository), auditRepo)
+		return handlers.NewAccountHandler(accountService, allowAllAccess()), accountRepo
+	}
+
+	t.Run("Deposit Key Cannot Open Account", func(t *testing.T) {
+		handler, accountRepo := newHandler()
+
+		c, rec := newAuthenticatedContext(e, http.MethodPost, "/accounts", dtos.OpenAccountRequest{Product: "current"})
+		c.Set(middleware.PrincipalKey, depositKey)
+
+		err := handler.OpenAccount(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		accountRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deposit Key Cannot List Accounts", func(t *testing.T) {
+		handler, accountRepo := newHandler()
+
+		c, rec := newAuthenticatedContext(e, http.MethodGet, "/accounts", nil)
+		c.Set(middleware.PrincipalKey, depositKey)
+
+		err := handler.ListAccounts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		accountRepo.AssertNotCalled(t, "ListByHolder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Manage Key Opens Account", func(t *testing.T) {
+		handler, accountRepo := newHandler()
+		accountRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		manageKey := *depositKey
+		manageKey.Scopes = []models.Permission{models.PermissionAccountManage}
+
+		c, rec := newAuthenticatedContext(e, http.MethodPost, "/accounts", dtos.OpenAccountRequest{Product: "current"})
+		c.Set(middleware.PrincipalKey, &manageKey)
+
+		err := handler.OpenAccount(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("Read Key Lists Accounts", func(t *testing.T) {
+		handler, accountRepo := newHandler()
+		accountRepo.On("ListByHolder", mock.Anything, testPrincipal.UserID).Return([]models.Account{}, nil)
+		readKey := *depositKey
+		readKey.Scopes = []models.Permission{models.PermissionAccountRead}
+
+		c, rec := newAuthenticatedContext(e, http.MethodGet, "/accounts", nil)
+		c.Set(middleware.PrincipalKey, &readKey)
+
+		err := handler.ListAccounts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
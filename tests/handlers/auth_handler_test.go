@@ -7,14 +7,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockAuthService is a mock implementation of services.AuthService
@@ -46,6 +49,94 @@ func (m *MockAuthService) Login(ctx context.Context, input dtos.LoginRequest) (*
 	return args.Get(0).(*dtos.AuthResponse), args.Error(1)
 }
 
+func (m *MockAuthService) Refresh(ctx context.Context, input dtos.RefreshRequest) (*dtos.AuthResponse, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, input dtos.LogoutRequest) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyMFA(ctx context.Context, input dtos.MFALoginRequest) (*dtos.AuthResponse, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.AuthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) EnrollMFA(ctx context.Context, principal *models.Principal) (*dtos.MFAEnrollResponse, error) {
+	args := m.Called(ctx, principal)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.MFAEnrollResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ActivateMFA(ctx context.Context, principal *models.Principal, input dtos.MFAActivateRequest) (*dtos.MFAActivateResponse, error) {
+	args := m.Called(ctx, principal, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.MFAActivateResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ForgotPassword(ctx context.Context, input dtos.ForgotPasswordRequest) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, input dtos.ResetPasswordRequest) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyEmail(ctx context.Context, input dtos.VerifyEmailRequest) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResendVerification(ctx context.Context, principal *models.Principal) error {
+	args := m.Called(ctx, principal)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) VerifyPhone(ctx context.Context, input dtos.VerifyPhoneRequest) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResendPhoneVerification(ctx context.Context, principal *models.Principal) error {
+	args := m.Called(ctx, principal)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) GetProfile(ctx context.Context, principal *models.Principal) (*models.User, error) {
+	args := m.Called(ctx, principal)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) UpdateProfile(ctx context.Context, principal *models.Principal, input dtos.UpdateProfileRequest) (*models.User, error) {
+	args := m.Called(ctx, principal, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) ChangePassword(ctx context.Context, principal *models.Principal, input dtos.ChangePasswordRequest) error {
+	args := m.Called(ctx, principal, input)
+	return args.Error(0)
+}
+
 func TestAuthHandler_Register(t *testing.T) {
 	e := echo.New()
 	mockAuthService := new(MockAuthService)
@@ -63,7 +154,7 @@ func TestAuthHandler_Register(t *testing.T) {
 
 		response := &dtos.AuthResponse{
 			Token: "jwt-token",
-			User: &models.Account{
+			User: &models.User{
 				Name:  input.Name,
 				Email: input.Email,
 			},
@@ -185,6 +276,7 @@ func TestAuthHandler_Login(t *testing.T) {
 		input := dtos.LoginRequest{
 			Email:    "john@example.com",
 			Password: "password123",
+			IP:       "192.0.2.1",
 		}
 
 		jsonBody, _ := json.Marshal(input)
@@ -195,7 +287,7 @@ func TestAuthHandler_Login(t *testing.T) {
 
 		response := &dtos.AuthResponse{
 			Token: "jwt-token",
-			User: &models.Account{
+			User: &models.User{
 				Email: input.Email,
 			},
 		}
@@ -251,6 +343,7 @@ func TestAuthHandler_Login(t *testing.T) {
 		input := dtos.LoginRequest{
 			Email:    "john@example.com",
 			Password: "wrongpassword",
+			IP:       "192.0.2.1",
 		}
 
 		jsonBody, _ := json.Marshal(input)
@@ -268,10 +361,36 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockAuthService.AssertExpectations(t)
 	})
 
+	t.Run("Too Many Attempts", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.LoginRequest{
+			Email:    "john@example.com",
+			Password: "wrongpassword",
+			IP:       "192.0.2.1",
+		}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("Login", c.Request().Context(), input).Return(nil, &services.LoginThrottledError{RetryAfter: 1500 * time.Millisecond})
+
+		err := handler.Login(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		mockAuthService.AssertExpectations(t)
+	})
+
 	t.Run("Internal Server Error", func(t *testing.T) {
 		input := dtos.LoginRequest{
 			Email:    "john@example.com",
 			Password: "password123",
+			IP:       "192.0.2.1",
 		}
 
 		jsonBody, _ := json.Marshal(input)
@@ -294,3 +413,355 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockAuthService.AssertExpectations(t)
 	})
 }
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	e := echo.New()
+
+	t.Run("Successful Refresh", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.RefreshRequest{RefreshToken: "refresh-token"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		response := &dtos.AuthResponse{Token: "jwt-token", RefreshToken: "next-refresh-token"}
+		mockAuthService.On("Refresh", c.Request().Context(), input).Return(response, nil)
+
+		err := handler.Refresh(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var responseBody dtos.AuthResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.Equal(t, response.RefreshToken, responseBody.RefreshToken)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Reused Token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.RefreshRequest{RefreshToken: "rotated-token"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("Refresh", c.Request().Context(), input).Return(nil, services.ErrRefreshTokenReused)
+
+		err := handler.Refresh(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		handler := handlers.NewAuthHandler(new(MockAuthService))
+		req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(`{}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.Refresh(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	e := echo.New()
+
+	t.Run("Successful Logout", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.LogoutRequest{RefreshToken: "refresh-token"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("Logout", c.Request().Context(), input).Return(nil)
+
+		err := handler.Logout(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.LogoutRequest{RefreshToken: "unknown"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("Logout", c.Request().Context(), input).Return(services.ErrInvalidRefreshToken)
+
+		err := handler.Logout(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestAuthHandler_VerifyMFA(t *testing.T) {
+	e := echo.New()
+
+	t.Run("Successful Verification", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.MFALoginRequest{MFAToken: "mfa-token", Code: "123456", IP: "192.0.2.1"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		response := &dtos.AuthResponse{Token: "jwt-token", RefreshToken: "refresh-token"}
+		mockAuthService.On("VerifyMFA", c.Request().Context(), input).Return(response, nil)
+
+		err := handler.VerifyMFA(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Code", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.MFALoginRequest{MFAToken: "mfa-token", RecoveryCode: "abcde-fghij", IP: "192.0.2.1"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("VerifyMFA", c.Request().Context(), input).Return(nil, services.ErrInvalidMFACode)
+
+		err := handler.VerifyMFA(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Too Many Failures", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.MFALoginRequest{MFAToken: "mfa-token", Code: "123456", IP: "192.0.2.1"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("VerifyMFA", c.Request().Context(), input).Return(nil, &services.LoginThrottledError{RetryAfter: 90 * time.Second})
+
+		err := handler.VerifyMFA(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "90", rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("Missing Code", func(t *testing.T) {
+		handler := handlers.NewAuthHandler(new(MockAuthService))
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/mfa", bytes.NewBufferString(`{"mfa_token":"mfa-token"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.VerifyMFA(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	e := echo.New()
+
+	t.Run("Accepted", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.ForgotPasswordRequest{Email: "john@example.com"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/password/forgot", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("ForgotPassword", c.Request().Context(), input).Return(nil)
+
+		err := handler.ForgotPassword(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	e := echo.New()
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.ResetPasswordRequest{Token: "expired", Password: "new-password"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auth/password/reset", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		mockAuthService.On("ResetPassword", c.Request().Context(), input).Return(services.ErrInvalidActionToken)
+
+		err := handler.ResetPassword(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Short Password", func(t *testing.T) {
+		handler := handlers.NewAuthHandler(new(MockAuthService))
+		req := httptest.NewRequest(http.MethodPost, "/auth/password/reset", bytes.NewBufferString(`{"token":"t","password":"short"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ResetPassword(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestAuthHandler_UpdateProfile(t *testing.T) {
+	e := echo.New()
+	principal := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	newContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPatch, "/me", bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(middleware.PrincipalKey, principal)
+		return c, rec
+	}
+
+	t.Run("Successful Update", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		c, rec := newContext(`{"name":"Jane Doe","updated_at":"2026-01-02T03:04:05.678Z"}`)
+
+		mockAuthService.On("UpdateProfile", c.Request().Context(), principal, mock.MatchedBy(func(input dtos.UpdateProfileRequest) bool {
+			return *input.Name == "Jane Doe" && input.UpdatedAt.Equal(time.Date(2026, 1, 2, 3, 4, 5, 678000000, time.UTC))
+		})).Return(&models.User{ID: principal.UserID, Name: "Jane Doe"}, nil)
+
+		err := handler.UpdateProfile(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("Missing Version", func(t *testing.T) {
+		handler := handlers.NewAuthHandler(new(MockAuthService))
+		c, rec := newContext(`{"name":"Jane Doe"}`)
+
+		err := handler.UpdateProfile(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Email Without Current Password", func(t *testing.T) {
+		handler := handlers.NewAuthHandler(new(MockAuthService))
+		c, rec := newContext(`{"email":"jane@example.com","updated_at":"2026-01-02T03:04:05Z"}`)
+
+		err := handler.UpdateProfile(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Modified Concurrently", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		c, rec := newContext(`{"name":"Jane Doe","updated_at":"2026-01-02T03:04:05Z"}`)
+
+		mockAuthService.On("UpdateProfile", c.Request().Context(), principal, mock.Anything).Return(nil, services.ErrUserModified)
+
+		err := handler.UpdateProfile(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	e := echo.New()
+	principal := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	t.Run("Wrong Current Password", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "new-password123"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/me/password", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(middleware.PrincipalKey, principal)
+
+		mockAuthService.On("ChangePassword", c.Request().Context(), principal, input).Return(services.ErrWrongPassword)
+
+		err := handler.ChangePassword(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		handler := handlers.NewAuthHandler(mockAuthService)
+		input := dtos.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "new-password123"}
+
+		jsonBody, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/me/password", bytes.NewBuffer(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(middleware.PrincipalKey, principal)
+
+		mockAuthService.On("ChangePassword", c.Request().Context(), principal, input).Return(&services.LoginThrottledError{RetryAfter: 2 * time.Second})
+
+		err := handler.ChangePassword(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	})
+}
@@ -8,10 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/handlers"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
-	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -19,47 +21,104 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// MockTransactionServiceAdapter adapts a mock to be used as services.TransactionService
-type MockTransactionServiceAdapter struct {
-	Deposit     func(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) (string, error)
-	Withdraw    func(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) (string, error)
-	GetBalances func(ctx context.Context, accountID primitive.ObjectID) (*dtos.BalancesResponse, error)
+// MockTransactionService is a mock implementation of services.TransactionService
+type MockTransactionService struct {
+	mock.Mock
 }
 
-func (m *MockTransactionServiceAdapter) Deposit(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) (string, error) {
-	return m.Deposit(ctx, accountID, amount, currency)
+func (m *MockTransactionService) GetBalances(ctx context.Context, accountID primitive.ObjectID) (*dtos.BalancesResponse, error) {
+	args := m.Called(ctx, accountID)
+	return args.Get(0).(*dtos.BalancesResponse), args.Error(1)
 }
 
-func (m *MockTransactionServiceAdapter) Withdraw(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) (string, error) {
-	return m.Withdraw(ctx, accountID, amount, currency)
+func (m *MockTransactionService) Deposit(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, idempotencyKey string) (string, error) {
+	args := m.Called(ctx, accountID, amount, currency, idempotencyKey)
+	return args.String(0), args.Error(1)
 }
 
-func (m *MockTransactionServiceAdapter) GetBalances(ctx context.Context, accountID primitive.ObjectID) (*dtos.BalancesResponse, error) {
-	return m.GetBalances(ctx, accountID)
+func (m *MockTransactionService) Withdraw(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, idempotencyKey string) (string, error) {
+	args := m.Called(ctx, accountID, amount, currency, idempotencyKey)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTransactionService) Transfer(ctx context.Context, sourceID, destinationID primitive.ObjectID, amount money.Amount, currency, description string) (*dtos.TransferResponse, error) {
+	args := m.Called(ctx, sourceID, destinationID, amount, currency, description)
+	return args.Get(0).(*dtos.TransferResponse), args.Error(1)
+}
+
+func (m *MockTransactionService) ListTransactions(ctx context.Context, accountID primitive.ObjectID, query dtos.TransactionHistoryQuery) (*dtos.TransactionHistoryResponse, error) {
+	args := m.Called(ctx, accountID, query)
+	return args.Get(0).(*dtos.TransactionHistoryResponse), args.Error(1)
+}
+
+func (m *MockTransactionService) GetTransaction(ctx context.Context, id primitive.ObjectID) (*dtos.TransactionDetail, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dtos.TransactionDetail), args.Error(1)
+}
+
+func (m *MockTransactionService) Authorize(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency, description string, ttl time.Duration) (*dtos.HoldResponse, error) {
+	args := m.Called(ctx, accountID, amount, currency, description, ttl)
+	return args.Get(0).(*dtos.HoldResponse), args.Error(1)
+}
+
+func (m *MockTransactionService) Capture(ctx context.Context, holdID primitive.ObjectID, amount *money.Amount) (*dtos.HoldResponse, error) {
+	args := m.Called(ctx, holdID, amount)
+	return args.Get(0).(*dtos.HoldResponse), args.Error(1)
+}
+
+func (m *MockTransactionService) Void(ctx context.Context, holdID primitive.ObjectID) (*dtos.HoldResponse, error) {
+	args := m.Called(ctx, holdID)
+	return args.Get(0).(*dtos.HoldResponse), args.Error(1)
+}
+
+func (m *MockTransactionService) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTransactionService) Reverse(ctx context.Context, transactionID primitive.ObjectID, amount *money.Amount, reason string) (*dtos.ReversalResponse, error) {
+	args := m.Called(ctx, transactionID, amount, reason)
+	return args.Get(0).(*dtos.ReversalResponse), args.Error(1)
+}
+
+func (m *MockTransactionService) Convert(ctx context.Context, quote *models.FXQuote, claim func(ctx context.Context, conversionID primitive.ObjectID) error) (*dtos.FXConversionResponse, error) {
+	args := m.Called(ctx, quote, claim)
+	return args.Get(0).(*dtos.FXConversionResponse), args.Error(1)
+}
+
+func (m *MockTransactionService) ChargeOverdrafts(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+// mustAmount converts the request amount the same way the handler does
+func mustAmount(input dtos.TransactionRequest) money.Amount {
+	amount, err := input.Amount.Amount(input.Currency)
+	if err != nil {
+		panic(err)
+	}
+	return amount
 }
 
 func TestTransactionHandler_Deposit(t *testing.T) {
 	e := echo.New()
-	
+
 	// Create mock service
-	mockService := &services.TransactionService{}
-	
-	// Create adapter with mock implementations
-	adapter := &MockTransactionServiceAdapter{}
-	
-	handler := handlers.NewTransactionHandler(mockService)
+	mockService := new(MockTransactionService)
+
+	handler := handlers.NewTransactionHandler(mockService, allowAllAccess())
 
 	t.Run("Successful Deposit", func(t *testing.T) {
 		// Setup
 		accountID := primitive.NewObjectID()
 		input := dtos.TransactionRequest{
 			AccountID: accountID.Hex(),
-			Amount:    100.0,
+			Amount:    "100.00",
 			Currency:  "USD",
 		}
 
 		transactionID := primitive.NewObjectID().Hex()
-		mockService.On("Deposit", mock.Anything, accountID, input.Amount, input.Currency).Return(transactionID, nil)
+		mockService.On("Deposit", mock.Anything, accountID, mustAmount(input), input.Currency, "").Return(transactionID, nil)
 
 		// Create request
 		jsonBody, _ := json.Marshal(input)
@@ -127,7 +186,7 @@ func TestTransactionHandler_Deposit(t *testing.T) {
 		// Setup
 		input := dtos.TransactionRequest{
 			AccountID: "invalid-id",
-			Amount:    100.0,
+			Amount:    "100.00",
 			Currency:  "USD",
 		}
 
@@ -155,12 +214,12 @@ func TestTransactionHandler_Deposit(t *testing.T) {
 		accountID := primitive.NewObjectID()
 		input := dtos.TransactionRequest{
 			AccountID: accountID.Hex(),
-			Amount:    100.0,
+			Amount:    "100.00",
 			Currency:  "USD",
 		}
 
 		customErr := utils.NewError(http.StatusBadRequest, "insufficient funds")
-		mockService.On("Deposit", mock.Anything, accountID, input.Amount, input.Currency).Return("", customErr)
+		mockService.On("Deposit", mock.Anything, accountID, mustAmount(input), input.Currency, "").Return("", customErr)
 
 		// Create request
 		jsonBody, _ := json.Marshal(input)
@@ -183,11 +242,11 @@ func TestTransactionHandler_Deposit(t *testing.T) {
 		accountID := primitive.NewObjectID()
 		input := dtos.TransactionRequest{
 			AccountID: accountID.Hex(),
-			Amount:    100.0,
+			Amount:    "100.00",
 			Currency:  "USD",
 		}
 
-		mockService.On("Deposit", mock.Anything, accountID, input.Amount, input.Currency).Return("", errors.New("database error"))
+		mockService.On("Deposit", mock.Anything, accountID, mustAmount(input), input.Currency, "").Return("", errors.New("database error"))
 
 		// Create request
 		jsonBody, _ := json.Marshal(input)
@@ -208,26 +267,23 @@ func TestTransactionHandler_Deposit(t *testing.T) {
 
 func TestTransactionHandler_Withdraw(t *testing.T) {
 	e := echo.New()
-	
+
 	// Create mock service
-	mockService := &services.TransactionService{}
-	
-	// Create adapter with mock implementations
-	adapter := &MockTransactionServiceAdapter{}
-	
-	handler := handlers.NewTransactionHandler(mockService)
+	mockService := new(MockTransactionService)
+
+	handler := handlers.NewTransactionHandler(mockService, allowAllAccess())
 
 	t.Run("Successful Withdrawal", func(t *testing.T) {
 		// Setup
 		accountID := primitive.NewObjectID()
 		input := dtos.TransactionRequest{
 			AccountID: accountID.Hex(),
-			Amount:    50.0,
+			Amount:    "50.00",
 			Currency:  "USD",
 		}
 
 		transactionID := primitive.NewObjectID().Hex()
-		mockService.On("Withdraw", mock.Anything, accountID, input.Amount, input.Currency).Return(transactionID, nil)
+		mockService.On("Withdraw", mock.Anything, accountID, mustAmount(input), input.Currency, "").Return(transactionID, nil)
 
 		// Create request
 		jsonBody, _ := json.Marshal(input)
@@ -295,7 +351,7 @@ func TestTransactionHandler_Withdraw(t *testing.T) {
 		// Setup
 		input := dtos.TransactionRequest{
 			AccountID: "invalid-id",
-			Amount:    50.0,
+			Amount:    "50.00",
 			Currency:  "USD",
 		}
 
@@ -323,12 +379,12 @@ func TestTransactionHandler_Withdraw(t *testing.T) {
 		accountID := primitive.NewObjectID()
 		input := dtos.TransactionRequest{
 			AccountID: accountID.Hex(),
-			Amount:    1000.0,
+			Amount:    "1000.00",
 			Currency:  "USD",
 		}
 
 		customErr := utils.NewError(http.StatusBadRequest, "insufficient funds")
-		mockService.On("Withdraw", mock.Anything, accountID, input.Amount, input.Currency).Return("", customErr)
+		mockService.On("Withdraw", mock.Anything, accountID, mustAmount(input), input.Currency, "").Return("", customErr)
 
 		// Create request
 		jsonBody, _ := json.Marshal(input)
@@ -351,11 +407,11 @@ func TestTransactionHandler_Withdraw(t *testing.T) {
 		accountID := primitive.NewObjectID()
 		input := dtos.TransactionRequest{
 			AccountID: accountID.Hex(),
-			Amount:    50.0,
+			Amount:    "50.00",
 			Currency:  "USD",
 		}
 
-		mockService.On("Withdraw", mock.Anything, accountID, input.Amount, input.Currency).Return("", errors.New("database error"))
+		mockService.On("Withdraw", mock.Anything, accountID, mustAmount(input), input.Currency, "").Return("", errors.New("database error"))
 
 		// Create request
 		jsonBody, _ := json.Marshal(input)
@@ -376,26 +432,23 @@ func TestTransactionHandler_Withdraw(t *testing.T) {
 
 func TestTransactionHandler_GetBalances(t *testing.T) {
 	e := echo.New()
-	
+
 	// Create mock service
-	mockService := &services.TransactionService{}
-	
-	// Create adapter with mock implementations
-	adapter := &MockTransactionServiceAdapter{}
-	
-	handler := handlers.NewTransactionHandler(mockService)
+	mockService := new(MockTransactionService)
+
+	handler := handlers.NewTransactionHandler(mockService, allowAllAccess())
 
 	t.Run("Successful Get Balances", func(t *testing.T) {
 		// Setup
 		accountID := primitive.NewObjectID()
-		
+
 		balances := &dtos.BalancesResponse{
 			Balances: []dtos.CurrencyBalance{
-				{Currency: "USD", Amount: 100.0},
-				{Currency: "EUR", Amount: 50.0},
+				{Currency: "USD", Current: "100.00", Available: "100.00"},
+				{Currency: "EUR", Current: "50.00", Available: "50.00"},
 			},
 		}
-		
+
 		mockService.On("GetBalances", mock.Anything, accountID).Return(balances, nil)
 
 		// Create request
@@ -418,7 +471,7 @@ func TestTransactionHandler_GetBalances(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(response.Balances))
 		assert.Equal(t, "USD", response.Balances[0].Currency)
-		assert.Equal(t, 100.0, response.Balances[0].Amount)
+		assert.Equal(t, money.Decimal("100.00"), response.Balances[0].Current)
 		mockService.AssertExpectations(t)
 	})
 
@@ -443,7 +496,7 @@ func TestTransactionHandler_GetBalances(t *testing.T) {
 		// Setup
 		accountID := primitive.NewObjectID()
 		customErr := utils.NewError(http.StatusNotFound, "account not found")
-		
+
 		mockService.On("GetBalances", mock.Anything, accountID).Return((*dtos.BalancesResponse)(nil), customErr)
 
 		// Create request
@@ -466,7 +519,7 @@ func TestTransactionHandler_GetBalances(t *testing.T) {
 	t.Run("Service Error - Generic Error", func(t *testing.T) {
 		// Setup
 		accountID := primitive.NewObjectID()
-		
+
 		mockService.On("GetBalances", mock.Anything, accountID).Return((*dtos.BalancesResponse)(nil), errors.New("database error"))
 
 		// Create request
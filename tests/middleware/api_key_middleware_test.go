@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	e := echo.New()
+
+	// serve runs APIKeyAuth and Auth in front of a handler that records the principal it sees,
+	// both on the echo context and on the request context
+	serve := func(apiKeyRepo *mocks.MockAPIKeyRepository, userRepo *mocks.MockUserRepository, apiKey string) (*httptest.ResponseRecorder, *models.Principal, *models.Principal) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if apiKey != "" {
+			req.Header.Set(middleware.APIKeyHeader, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		var principal, fromRequest *models.Principal
+		apiKeyAuth := middleware.APIKeyAuth(services.NewAPIKeyService(apiKeyRepo, userRepo, &mocks.MockAuditEventRepository{}))
+		auth := middleware.Auth(userRepo, &mocks.MockRefreshTokenRepository{})
+		handler := apiKeyAuth(auth(func(c echo.Context) error {
+			principal = middleware.GetUserID(c)
+			fromRequest = models.PrincipalFromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		}))
+		_ = handler(c)
+		return rec, principal, fromRequest
+	}
+
+	t.Run("Valid Key", func(t *testing.T) {
+		user := &models.User{ID: primitive.NewObjectID(), Email: "bot@example.com", Role: models.RoleCustomer}
+		key := &models.APIKey{ID: primitive.NewObjectID(), UserID: user.ID, Scopes: []models.Permission{models.PermissionFundsDeposit}}
+		apiKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyRepo.On("FindByHash", mock.Anything, mock.AnythingOfType("string")).Return(key, nil)
+		apiKeyRepo.On("TouchLastUsed", mock.Anything, key.ID, mock.AnythingOfType("time.Time")).Return(nil)
+		userRepo := &mocks.MockUserRepository{}
+		userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+		rec, principal, fromRequest := serve(apiKeyRepo, userRepo, "axk_secret")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.NotNil(t, principal) {
+			assert.Equal(t, user.ID, principal.UserID)
+			assert.Equal(t, key.ID, *principal.APIKeyID)
+		}
+		assert.Same(t, principal, fromRequest)
+	})
+
+	t.Run("Revoked Key", func(t *testing.T) {
+		apiKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyRepo.On("FindByHash", mock.Anything, mock.AnythingOfType("string")).Return(nil, nil)
+
+		rec, principal, _ := serve(apiKeyRepo, &mocks.MockUserRepository{}, "axk_revoked")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, principal)
+	})
+
+	t.Run("No Key Falls Back To Token", func(t *testing.T) {
+		apiKeyRepo := &mocks.MockAPIKeyRepository{}
+
+		rec, principal, _ := serve(apiKeyRepo, &mocks.MockUserRepository{}, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Authorization header is required")
+		assert.Nil(t, principal)
+		apiKeyRepo.AssertNotCalled(t, "FindByHash", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRequireScope(t *testing.T) {
+	e := echo.New()
+	keyID := primitive.NewObjectID()
+
+	for _, tc := range []struct {
+		name      string
+		principal *models.Principal
+		expected  int
+	}{
+		{"Token", &models.Principal{Role: models.RoleAdmin}, http.StatusOK},
+		{"Key With Scope", &models.Principal{Role: models.RoleAdmin, APIKeyID: &keyID, Scopes: []models.Permission{models.PermissionAccountManage}}, http.StatusOK},
+		{"Key Without Scope", &models.Principal{Role: models.RoleAdmin, APIKeyID: &keyID, Scopes: []models.Permission{models.PermissionAccountRead}}, http.StatusForbidden},
+		{"No Principal", nil, http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+			if tc.principal != nil {
+				c.Set(middleware.PrincipalKey, tc.principal)
+			}
+
+			handler := middleware.RequireScope(models.PermissionAccountManage)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			_ = handler(c)
+
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/jwt"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAuth(t *testing.T) {
+	e := echo.New()
+
+	// serve runs the middleware in front of a handler that records the principal it sees
+	serve := func(userRepo *mocks.MockUserRepository, refreshTokenRepo *mocks.MockRefreshTokenRepository, authorization string) (*httptest.ResponseRecorder, *models.Principal) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		var principal *models.Principal
+		handler := middleware.Auth(userRepo, refreshTokenRepo)(func(c echo.Context) error {
+			principal = middleware.GetUserID(c)
+			return c.NoContent(http.StatusOK)
+		})
+		_ = handler(c)
+		return rec, principal
+	}
+
+	t.Run("Valid Token", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		user := &models.User{ID: primitive.NewObjectID(), Email: "john@example.com", Status: models.UserStatusActive, Role: models.RoleCustomer}
+		userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+		refreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		sessionID := primitive.NewObjectID()
+		refreshTokenRepo.On("IsFamilyRevoked", mock.Anything, sessionID).Return(false, nil)
+
+		token, err := jwt.GenerateToken(user.ID.Hex(), sessionID.Hex(), "customer")
+		assert.NoError(t, err)
+
+		rec, principal := serve(userRepo, refreshTokenRepo, "Bearer "+token)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.NotNil(t, principal) {
+			assert.Equal(t, user.ID, principal.UserID)
+			assert.Equal(t, user.Email, principal.Email)
+			assert.NotEmpty(t, principal.TokenID)
+			assert.Equal(t, sessionID, principal.SessionID)
+			assert.Equal(t, models.RoleCustomer, principal.Role)
+		}
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("Claims Carry Subject Issuer Audience And ID", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		sessionID := primitive.NewObjectID()
+		token, err := jwt.GenerateToken(userID.Hex(), sessionID.Hex(), "customer")
+		assert.NoError(t, err)
+
+		claims, err := jwt.ValidateToken(token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, userID.Hex(), claims.Subject)
+		assert.Equal(t, sessionID.Hex(), claims.SessionID)
+		assert.Equal(t, jwt.Issuer, claims.Issuer)
+		assert.Contains(t, claims.Audience, jwt.Audience)
+		assert.Equal(t, "customer", claims.Role)
+		assert.NotEmpty(t, claims.ID)
+	})
+
+	t.Run("Unknown User", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		userID := primitive.NewObjectID()
+		userRepo.On("FindByID", mock.Anything, userID).Return(nil, nil)
+		refreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		refreshTokenRepo.On("IsFamilyRevoked", mock.Anything, mock.Anything).Return(false, nil)
+
+		token, err := jwt.GenerateToken(userID.Hex(), primitive.NewObjectID().Hex(), "customer")
+		assert.NoError(t, err)
+
+		rec, principal := serve(userRepo, refreshTokenRepo, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, principal)
+	})
+
+	t.Run("Wrong Audience", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		original := jwt.Audience
+		jwt.Audience = "another-api"
+		token, err := jwt.GenerateToken(primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex(), "customer")
+		jwt.Audience = original
+		assert.NoError(t, err)
+
+		rec, principal := serve(userRepo, &mocks.MockRefreshTokenRepository{}, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, principal)
+		userRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Revoked Session", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		refreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		sessionID := primitive.NewObjectID()
+		refreshTokenRepo.On("IsFamilyRevoked", mock.Anything, sessionID).Return(true, nil)
+
+		token, err := jwt.GenerateToken(primitive.NewObjectID().Hex(), sessionID.Hex(), "customer")
+		assert.NoError(t, err)
+
+		rec, principal := serve(userRepo, refreshTokenRepo, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, principal)
+		userRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Outdated Role", func(t *testing.T) {
+		userRepo := &mocks.MockUserRepository{}
+		user := &models.User{ID: primitive.NewObjectID(), Role: models.RoleCustomer}
+		userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+		refreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		refreshTokenRepo.On("IsFamilyRevoked", mock.Anything, mock.Anything).Return(false, nil)
+
+		// Token minted while the user was still an admin
+		token, err := jwt.GenerateToken(user.ID.Hex(), primitive.NewObjectID().Hex(), "admin")
+		assert.NoError(t, err)
+
+		rec, principal := serve(userRepo, refreshTokenRepo, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, principal)
+	})
+
+	t.Run("Missing Header", func(t *testing.T) {
+		rec, principal := serve(&mocks.MockUserRepository{}, &mocks.MockRefreshTokenRepository{}, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, principal)
+	})
+}
+
+func TestRequireRole(t *testing.T) {
+	e := echo.New()
+
+	// serve runs RequireRole(roles...) for a request authenticated as principal
+	serve := func(principal *models.Principal, roles ...models.Role) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if principal != nil {
+			c.Set(middleware.PrincipalKey, principal)
+		}
+
+		handler := middleware.RequireRole(roles...)(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		_ = handler(c)
+		return rec
+	}
+
+	t.Run("Allowed Role", func(t *testing.T) {
+		rec := serve(&models.Principal{Role: models.RoleAuditor}, models.RoleAuditor, models.RoleAdmin)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Insufficient Role", func(t *testing.T) {
+		rec := serve(&models.Principal{Role: models.RoleCustomer}, models.RoleAdmin)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Missing Principal", func(t *testing.T) {
+		rec := serve(nil, models.RoleAdmin)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
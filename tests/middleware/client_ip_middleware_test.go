@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIPExtractor(t *testing.T) {
+	// request comes from remoteAddr and claims to be forwarded for a made-up client
+	request := func(remoteAddr string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1")
+		req.Header.Set(echo.HeaderXRealIP, "198.51.100.1")
+		return req
+	}
+
+	t.Run("Ignores Forwarding Headers Without Trusted Proxies", func(t *testing.T) {
+		extract, err := middleware.ClientIPExtractor("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "203.0.113.7", extract(request("203.0.113.7:1234")))
+	})
+
+	t.Run("Follows Forwarding Headers From Trusted Proxy", func(t *testing.T) {
+		extract, err := middleware.ClientIPExtractor("10.0.0.0/8, 192.0.2.0/24")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "198.51.100.1", extract(request("10.1.2.3:1234")))
+		assert.Equal(t, "203.0.113.7", extract(request("203.0.113.7:1234")), "untrusted peers cannot claim a forwarded address")
+	})
+
+	t.Run("Private Networks Are Not Trusted By Default", func(t *testing.T) {
+		extract, err := middleware.ClientIPExtractor("192.0.2.0/24")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "172.16.0.5", extract(request("172.16.0.5:1234")))
+	})
+
+	t.Run("Invalid Range", func(t *testing.T) {
+		extract, err := middleware.ClientIPExtractor("10.0.0.1")
+
+		assert.Error(t, err)
+		assert.Nil(t, extract)
+	})
+}
@@ -0,0 +1,140 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/ratelimit"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// failingStore is a rate limit store whose backend is unavailable
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimit(t *testing.T) {
+	e := echo.New()
+	limit := ratelimit.Limit{Burst: 2, Period: time.Minute}
+
+	// serve sends one request from the given address, authenticated as principal if it is set
+	serve := func(store ratelimit.Store, remoteAddr string, principal *models.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if principal != nil {
+			c.Set(middleware.PrincipalKey, principal)
+		}
+
+		handler := middleware.RateLimit(store, "money", limit)(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		_ = handler(c)
+		return rec
+	}
+
+	t.Run("Allows Burst Then Refuses", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+
+		rec := serve(store, "203.0.113.7:1234", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+
+		rec = serve(store, "203.0.113.7:1234", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+		rec = serve(store, "203.0.113.7:1234", nil)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("Separate Budgets Per Client", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		customer := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+		keyID := primitive.NewObjectID()
+		apiKey := &models.Principal{UserID: customer.UserID, Role: models.RoleCustomer, APIKeyID: &keyID}
+
+		// The account and its API key share an IP address but are counted apart
+		for i := 0; i < 2; i++ {
+			assert.Equal(t, http.StatusOK, serve(store, "203.0.113.7:1234", customer).Code)
+		}
+		assert.Equal(t, http.StatusTooManyRequests, serve(store, "203.0.113.7:1234", customer).Code)
+		assert.Equal(t, http.StatusOK, serve(store, "203.0.113.7:1234", apiKey).Code)
+		assert.Equal(t, http.StatusOK, serve(store, "203.0.113.7:1234", nil).Code)
+		assert.Equal(t, http.StatusOK, serve(store, "198.51.100.4:1234", nil).Code)
+	})
+
+	t.Run("Forged Forwarding Headers Do Not Reset The Budget", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		direct := echo.New()
+		direct.IPExtractor, _ = middleware.ClientIPExtractor("")
+		handler := middleware.RateLimit(store, "auth", limit)(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		codes := []int{}
+		for _, forged := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = "203.0.113.7:1234"
+			req.Header.Set(echo.HeaderXForwardedFor, forged)
+			rec := httptest.NewRecorder()
+			_ = handler(direct.NewContext(req, rec))
+			codes = append(codes, rec.Code)
+		}
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	})
+
+	t.Run("Store Failure Lets Requests Through", func(t *testing.T) {
+		rec := serve(failingStore{}, "203.0.113.7:1234", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestLimit_Validate(t *testing.T) {
+	assert.NoError(t, ratelimit.Limit{Burst: 1, Period: time.Minute}.Validate())
+	assert.Error(t, ratelimit.Limit{Burst: 0, Period: time.Minute}.Validate())
+	assert.Error(t, ratelimit.Limit{Burst: -5, Period: time.Minute}.Validate())
+	assert.Error(t, ratelimit.Limit{Burst: 10}.Validate())
+}
+
+func TestMemoryStore_Refill(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Burst: 2, Period: time.Minute}
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Take(ctx, "k", limit, now)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	result, _ := store.Take(ctx, "k", limit, now.Add(10*time.Second))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 20*time.Second, result.RetryAfter)
+
+	// A token is back after half the period
+	result, _ = store.Take(ctx, "k", limit, now.Add(30*time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Minute, result.Reset)
+}
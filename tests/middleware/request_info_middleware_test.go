@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/api/middleware"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestInfo(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor, _ = middleware.ClientIPExtractor("")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("User-Agent", "curl/8.0")
+	req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var info models.RequestInfo
+	handler := echomw.RequestID()(middleware.RequestInfo()(func(c echo.Context) error {
+		info = models.RequestInfoFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	}))
+	_ = handler(c)
+
+	assert.Equal(t, "203.0.113.7", info.IP, "a forwarding header from an untrusted peer is not recorded")
+	assert.Equal(t, "curl/8.0", info.UserAgent)
+	assert.NotEmpty(t, info.RequestID)
+	assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), info.RequestID)
+}
+
+func TestRequestInfo_TrustedProxy(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor, _ = middleware.ClientIPExtractor("10.0.0.0/8")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.1.2.3:1234"
+	req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	var info models.RequestInfo
+	handler := middleware.RequestInfo()(func(c echo.Context) error {
+		info = models.RequestInfoFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+	_ = handler(c)
+
+	assert.Equal(t, "198.51.100.1", info.IP)
+}
@@ -3,25 +3,54 @@ package mocks
 import (
 	"context"
 
-	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type MockAccountRepository struct {
 	mock.Mock
 }
 
-func (m *MockAccountRepository) Create(ctx context.Context, dto *dtos.CreateAccountDTO) (*models.Account, error) {
-	args := m.Called(ctx, dto)
+func (m *MockAccountRepository) Create(ctx context.Context, account *models.Account) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Account, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) ListByHolder(ctx context.Context, userID primitive.ObjectID) ([]models.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) AddHolder(ctx context.Context, id primitive.ObjectID, holder models.AccountHolder) (*models.Account, error) {
+	args := m.Called(ctx, id, holder)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) RemoveHolder(ctx context.Context, id, userID primitive.ObjectID) (*models.Account, error) {
+	args := m.Called(ctx, id, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Account), args.Error(1)
 }
 
-func (m *MockAccountRepository) FindByEmail(ctx context.Context, email string) (*models.Account, error) {
-	args := m.Called(ctx, email)
+func (m *MockAccountRepository) SetTier(ctx context.Context, id primitive.ObjectID, tier models.AccountTier) (*models.Account, error) {
+	args := m.Called(ctx, id, tier)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockActionTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockActionTokenRepository) Create(ctx context.Context, token *models.ActionToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockActionTokenRepository) FindByHash(ctx context.Context, purpose models.ActionPurpose, tokenHash string) (*models.ActionToken, error) {
+	args := m.Called(ctx, purpose, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ActionToken), args.Error(1)
+}
+
+func (m *MockActionTokenRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockActionTokenRepository) InvalidateAll(ctx context.Context, userID primitive.ObjectID, purpose models.ActionPurpose) error {
+	args := m.Called(ctx, userID, purpose)
+	return args.Error(0)
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditEventRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditEventRepository) List(ctx context.Context, filter dtos.AuditEventFilter) ([]models.AuditEvent, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditEvent), args.Error(1)
+}
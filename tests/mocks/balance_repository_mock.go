@@ -2,8 +2,10 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -20,12 +22,56 @@ func (m *MockBalanceRepository) GetBalances(ctx context.Context, accountID primi
 	return args.Get(0).([]models.Balance), args.Error(1)
 }
 
-func (m *MockBalanceRepository) UpdateBalance(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) error {
+func (m *MockBalanceRepository) GetBalance(ctx context.Context, accountID primitive.ObjectID, currency string) (*models.Balance, error) {
+	args := m.Called(ctx, accountID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Balance), args.Error(1)
+}
+
+func (m *MockBalanceRepository) UpdateBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
+	args := m.Called(ctx, accountID, amount, currency)
+	return args.Error(0)
+}
+
+func (m *MockBalanceRepository) CheckAndDeductBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
+	args := m.Called(ctx, accountID, amount, currency)
+	return args.Error(0)
+}
+
+func (m *MockBalanceRepository) SetBalance(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
+	args := m.Called(ctx, accountID, amount, currency)
+	return args.Error(0)
+}
+
+func (m *MockBalanceRepository) PlaceHold(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
 	args := m.Called(ctx, accountID, amount, currency)
 	return args.Error(0)
 }
 
-func (m *MockBalanceRepository) CheckAndDeductBalance(ctx context.Context, accountID primitive.ObjectID, amount float64, currency string) error {
+func (m *MockBalanceRepository) ReleaseHold(ctx context.Context, accountID primitive.ObjectID, amount money.Amount, currency string) error {
 	args := m.Called(ctx, accountID, amount, currency)
 	return args.Error(0)
 }
+
+func (m *MockBalanceRepository) SetOverdraft(ctx context.Context, accountID primitive.ObjectID, currency string, overdraft models.Overdraft) (*models.Balance, error) {
+	args := m.Called(ctx, accountID, currency, overdraft)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Balance), args.Error(1)
+}
+
+func (m *MockBalanceRepository) FindOverdraftsDue(ctx context.Context, dayStart time.Time, skip []primitive.ObjectID, limit int) ([]models.Balance, error) {
+	args := m.Called(ctx, dayStart, skip, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Balance), args.Error(1)
+}
+
+func (m *MockBalanceRepository) MarkOverdraftCharged(ctx context.Context, id primitive.ObjectID, chargedThrough *time.Time, dayStart time.Time) (bool, error) {
+	args := m.Called(ctx, id, chargedThrough, dayStart)
+	return args.Bool(0), args.Error(1)
+}
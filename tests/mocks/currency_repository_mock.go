@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCurrencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyRepository) ListOverrides(ctx context.Context) ([]models.CurrencyOverride, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CurrencyOverride), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) UpsertOverride(ctx context.Context, override *models.CurrencyOverride) (*models.CurrencyOverride, error) {
+	args := m.Called(ctx, override)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CurrencyOverride), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) DeleteOverride(ctx context.Context, code string) (bool, error) {
+	args := m.Called(ctx, code)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) IsInUse(ctx context.Context, code string) (bool, error) {
+	args := m.Called(ctx, code)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockFXQuoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockFXQuoteRepository) Create(ctx context.Context, quote *models.FXQuote) error {
+	args := m.Called(ctx, quote)
+	return args.Error(0)
+}
+
+func (m *MockFXQuoteRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.FXQuote, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FXQuote), args.Error(1)
+}
+
+func (m *MockFXQuoteRepository) MarkUsed(ctx context.Context, id, conversionID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, id, conversionID)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Find(ctx context.Context, accountID primitive.ObjectID, key string) (*models.IdempotencyKey, error) {
+	args := m.Called(ctx, accountID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Create(ctx context.Context, record *models.IdempotencyKey) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) SetTransaction(ctx context.Context, id, transactionID primitive.ObjectID) error {
+	args := m.Called(ctx, id, transactionID)
+	return args.Error(0)
+}
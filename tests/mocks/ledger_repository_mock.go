@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) CreateEntry(ctx context.Context, entry *models.JournalEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) FindEntry(ctx context.Context, id primitive.ObjectID) (*models.JournalEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.JournalEntry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) SumCustomerPostings(ctx context.Context, accountID primitive.ObjectID) (map[string]money.Amount, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]money.Amount), args.Error(1)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLimitPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockLimitPolicyRepository) List(ctx context.Context) ([]models.LimitPolicy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LimitPolicy), args.Error(1)
+}
+
+func (m *MockLimitPolicyRepository) Find(ctx context.Context, tier models.AccountTier, currency string, direction models.LimitDirection) (*models.LimitPolicy, error) {
+	args := m.Called(ctx, tier, currency, direction)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LimitPolicy), args.Error(1)
+}
+
+func (m *MockLimitPolicyRepository) Upsert(ctx context.Context, policy *models.LimitPolicy) (*models.LimitPolicy, error) {
+	args := m.Called(ctx, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LimitPolicy), args.Error(1)
+}
+
+func (m *MockLimitPolicyRepository) Delete(ctx context.Context, tier models.AccountTier, currency string, direction models.LimitDirection) (bool, error) {
+	args := m.Called(ctx, tier, currency, direction)
+	return args.Bool(0), args.Error(1)
+}
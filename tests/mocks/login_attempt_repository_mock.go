@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) Get(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error) {
+	args := m.Called(ctx, key, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	args := m.Called(ctx, key, now, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	args := m.Called(ctx, key, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
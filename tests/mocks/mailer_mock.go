@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(ctx context.Context, message mailer.Message) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/sms"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSMSSender struct {
+	mock.Mock
+}
+
+func (m *MockSMSSender) Send(ctx context.Context, message sms.Message) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
@@ -2,10 +2,13 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type MockTransactionRepository struct {
@@ -19,3 +22,74 @@ func (m *MockTransactionRepository) CreateTransaction(ctx context.Context, dto *
 	}
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
+
+func (m *MockTransactionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Transaction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListTransactions(ctx context.Context, filter *dtos.TransactionFilter) ([]models.Transaction, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ResolveHold(ctx context.Context, id primitive.ObjectID, status models.TransactionStatus, amount money.Amount, journalEntryID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, id, status, amount, journalEntryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindExpiredHolds(ctx context.Context, before time.Time, limit int64) ([]models.Transaction, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindByTransferID(ctx context.Context, transferID primitive.ObjectID) ([]models.Transaction, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) AddReversedAmount(ctx context.Context, id primitive.ObjectID, amount money.Amount) (bool, error) {
+	args := m.Called(ctx, id, amount)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListChain(ctx context.Context, accountID primitive.ObjectID, afterSequence int64, limit int64) ([]models.Transaction, error) {
+	args := m.Called(ctx, accountID, afterSequence, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) CountUnchained(ctx context.Context, accountID primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, accountID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListAccountIDs(ctx context.Context) ([]primitive.ObjectID, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+func (m *MockTransactionRepository) SumLimitUsage(ctx context.Context, accountID primitive.ObjectID, currency string, direction models.LimitDirection, dayStart, monthStart time.Time) (*models.LimitUsage, error) {
+	args := m.Called(ctx, accountID, currency, direction, dayStart, monthStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LimitUsage), args.Error(1)
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+)
+
+// MockTxRunner runs transactional work inline, without a Mongo session. StartErr fails the
+// transaction before fn runs and CommitErr fails it after fn succeeds.
+type MockTxRunner struct {
+	StartErr  error
+	CommitErr error
+	Runs      int
+}
+
+func (m *MockTxRunner) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Runs++
+	if m.StartErr != nil {
+		return m.StartErr
+	}
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return m.CommitErr
+}
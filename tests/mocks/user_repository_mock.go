@@ -0,0 +1,125 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, dto *dtos.CreateUserDTO) (*models.User, error) {
+	args := m.Called(ctx, dto)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filter dtos.UserFilter) ([]models.User, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role models.Role) (*models.User, error) {
+	args := m.Called(ctx, id, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SetMFASecret(ctx context.Context, id primitive.ObjectID, secret string) (bool, error) {
+	args := m.Called(ctx, id, secret)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) EnableMFA(ctx context.Context, id primitive.ObjectID, step int64, recoveryCodeHashes []string) (bool, error) {
+	args := m.Called(ctx, id, step, recoveryCodeHashes)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) AdvanceMFAStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	args := m.Called(ctx, id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UseRecoveryCode(ctx context.Context, id primitive.ObjectID, codeHash string) (bool, error) {
+	args := m.Called(ctx, id, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) SetMFAChallenge(ctx context.Context, id primitive.ObjectID, challengeID string) error {
+	args := m.Called(ctx, id, challengeID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ConsumeMFAChallenge(ctx context.Context, id primitive.ObjectID, challengeID string) (bool, error) {
+	args := m.Called(ctx, id, challengeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) (bool, error) {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id primitive.ObjectID, email string) (bool, error) {
+	args := m.Called(ctx, id, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkPhoneVerified(ctx context.Context, id primitive.ObjectID, phone string) (bool, error) {
+	args := m.Called(ctx, id, phone)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *models.User) (bool, error) {
+	args := m.Called(ctx, user)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Lock(ctx context.Context, id primitive.ObjectID, event models.LockoutEvent) error {
+	args := m.Called(ctx, id, event)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Unlock(ctx context.Context, id primitive.ObjectID, event models.LockoutEvent) (*models.User, error) {
+	args := m.Called(ctx, id, event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, change models.StatusChange) (*models.User, error) {
+	args := m.Called(ctx, id, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAccessService_AuthorizeAccount(t *testing.T) {
+	ctx := context.Background()
+	mockAccountRepo := &mocks.MockAccountRepository{}
+	accessService := services.NewAccessService(&mocks.MockTransactionRepository{}, mockAccountRepo)
+	customer := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+	joint := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+	account := &models.Account{
+		ID: primitive.NewObjectID(),
+		Holders: []models.AccountHolder{
+			{UserID: customer.UserID, Role: models.HolderRoleOwner},
+			{UserID: joint.UserID, Role: models.HolderRoleJoint, Permissions: []models.Permission{models.PermissionAccountRead, models.PermissionFundsDeposit}},
+		},
+	}
+
+	mockAccountRepo.On("FindByID", ctx, account.ID).Return(account, nil)
+	mockAccountRepo.On("FindByID", ctx, mock.Anything).Return(nil, nil)
+
+	t.Run("Owner", func(t *testing.T) {
+		err := accessService.AuthorizeAccount(ctx, customer, account.ID, models.PermissionFundsWithdraw)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Owner Cannot Reverse", func(t *testing.T) {
+		err := accessService.AuthorizeAccount(ctx, customer, account.ID, models.PermissionTransactionReverse)
+
+		assert.Equal(t, utils.ErrForbidden, err)
+	})
+
+	t.Run("Joint Holder Limited To Granted Permissions", func(t *testing.T) {
+		assert.NoError(t, accessService.AuthorizeAccount(ctx, joint, account.ID, models.PermissionFundsDeposit))
+		assert.Equal(t, utils.ErrForbidden, accessService.AuthorizeAccount(ctx, joint, account.ID, models.PermissionFundsWithdraw))
+		assert.Equal(t, utils.ErrForbidden, accessService.AuthorizeAccount(ctx, joint, account.ID, models.PermissionAccountManage))
+	})
+
+	t.Run("Foreign Account", func(t *testing.T) {
+		err := accessService.AuthorizeAccount(ctx, customer, primitive.NewObjectID(), models.PermissionAccountRead)
+
+		assert.Equal(t, utils.ErrForbidden, err)
+	})
+
+	t.Run("Missing Principal", func(t *testing.T) {
+		err := accessService.AuthorizeAccount(ctx, nil, account.ID, models.PermissionAccountRead)
+
+		assert.Equal(t, utils.ErrInvalidToken, err)
+	})
+
+	t.Run("Teller Deposits Into Customer Account", func(t *testing.T) {
+		teller := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleTeller}
+
+		assert.NoError(t, accessService.AuthorizeAccount(ctx, teller, account.ID, models.PermissionFundsDeposit))
+		assert.Equal(t, utils.ErrForbidden, accessService.AuthorizeAccount(ctx, teller, account.ID, models.PermissionFundsWithdraw))
+	})
+
+	t.Run("Auditor Is Read Only", func(t *testing.T) {
+		auditor := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAuditor}
+
+		assert.NoError(t, accessService.AuthorizeAccount(ctx, auditor, account.ID, models.PermissionAccountRead))
+		assert.Equal(t, utils.ErrForbidden, accessService.AuthorizeAccount(ctx, auditor, account.ID, models.PermissionFundsDeposit))
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+		assert.NoError(t, accessService.AuthorizeAccount(ctx, admin, account.ID, models.PermissionTransactionReverse))
+	})
+
+	t.Run("API Key Limited To Scopes", func(t *testing.T) {
+		keyID := primitive.NewObjectID()
+		keyPrincipal := &models.Principal{UserID: customer.UserID, Role: models.RoleCustomer, APIKeyID: &keyID, Scopes: []models.Permission{models.PermissionAccountRead}}
+
+		assert.NoError(t, accessService.AuthorizeAccount(ctx, keyPrincipal, account.ID, models.PermissionAccountRead))
+		assert.Equal(t, utils.ErrAPIKeyScope, accessService.AuthorizeAccount(ctx, keyPrincipal, account.ID, models.PermissionFundsWithdraw))
+	})
+}
+
+func TestAccessService_AuthorizeTransaction(t *testing.T) {
+	ctx := context.Background()
+	principal := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	t.Run("Own Transaction", func(t *testing.T) {
+		mockTransactionRepo := &mocks.MockTransactionRepository{}
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accessService := services.NewAccessService(mockTransactionRepo, mockAccountRepo)
+		transactionID := primitive.NewObjectID()
+		accountID := primitive.NewObjectID()
+
+		mockTransactionRepo.On("FindByID", ctx, transactionID).Return(&models.Transaction{ID: transactionID, AccountID: accountID}, nil)
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(&models.Account{
+			ID:      accountID,
+			Holders: []models.AccountHolder{{UserID: principal.UserID, Role: models.HolderRoleOwner}},
+		}, nil)
+
+		err := accessService.AuthorizeTransaction(ctx, principal, transactionID, models.PermissionAccountRead)
+
+		assert.NoError(t, err)
+		mockTransactionRepo.AssertExpectations(t)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("Foreign Transaction", func(t *testing.T) {
+		mockTransactionRepo := &mocks.MockTransactionRepository{}
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accessService := services.NewAccessService(mockTransactionRepo, mockAccountRepo)
+		transactionID := primitive.NewObjectID()
+
+		mockTransactionRepo.On("FindByID", ctx, transactionID).Return(&models.Transaction{ID: transactionID, AccountID: primitive.NewObjectID()}, nil)
+		mockAccountRepo.On("FindByID", ctx, mock.Anything).Return(nil, nil)
+
+		err := accessService.AuthorizeTransaction(ctx, principal, transactionID, models.PermissionAccountRead)
+
+		assert.Equal(t, utils.ErrForbidden, err)
+	})
+
+	t.Run("Transaction Not Found", func(t *testing.T) {
+		mockTransactionRepo := &mocks.MockTransactionRepository{}
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accessService := services.NewAccessService(mockTransactionRepo, mockAccountRepo)
+		transactionID := primitive.NewObjectID()
+
+		mockTransactionRepo.On("FindByID", ctx, transactionID).Return(nil, nil)
+
+		err := accessService.AuthorizeTransaction(ctx, principal, transactionID, models.PermissionAccountRead)
+
+		assert.Equal(t, utils.ErrTransactionNotFound, err)
+	})
+}
@@ -0,0 +1,305 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAccountService_OpenAccount(t *testing.T) {
+	ctx := context.Background()
+	mockAccountRepo := &mocks.MockAccountRepository{}
+	accountService := services.NewAccountService(mockAccountRepo, &mocks.MockUserRepository{}, recordAudit())
+	principal := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	mockAccountRepo.On("Create", ctx, mock.MatchedBy(func(account *models.Account) bool {
+		return account.Product == models.AccountProductSavings && account.Name == "Holiday" &&
+			len(account.Holders) == 1 &&
+			account.Holders[0].UserID == principal.UserID &&
+			account.Holders[0].Role == models.HolderRoleOwner
+	})).Return(nil)
+
+	account, err := accountService.OpenAccount(ctx, principal, dtos.OpenAccountRequest{Product: "savings", Name: "Holiday"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AccountProductSavings, account.Product)
+	mockAccountRepo.AssertExpectations(t)
+}
+
+// heldAccount returns an account owned by ownerID with the given joint holders
+func heldAccount(accountID, ownerID primitive.ObjectID, joint ...models.AccountHolder) *models.Account {
+	holders := append([]models.AccountHolder{{UserID: ownerID, Role: models.HolderRoleOwner}}, joint...)
+	return &models.Account{ID: accountID, Holders: holders}
+}
+
+func TestAccountService_AddHolder(t *testing.T) {
+	ctx := context.Background()
+	owner := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+	input := dtos.AddHolderRequest{Email: "jane@example.com", Permissions: []models.Permission{models.PermissionAccountRead, models.PermissionFundsDeposit}}
+
+	t.Run("Successful Add", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, mockUserRepo, recordAudit())
+		accountID := primitive.NewObjectID()
+		jane := &models.User{ID: primitive.NewObjectID(), Email: input.Email}
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID), nil)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(jane, nil)
+		mockAccountRepo.On("AddHolder", ctx, accountID, mock.MatchedBy(func(holder models.AccountHolder) bool {
+			return holder.UserID == jane.ID && holder.Role == models.HolderRoleJoint && len(holder.Permissions) == 2
+		})).Return(&models.Account{ID: accountID}, nil)
+
+		account, err := accountService.AddHolder(ctx, owner, accountID, input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, accountID, account.ID)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("Permission Not Grantable", func(t *testing.T) {
+		accountService := services.NewAccountService(&mocks.MockAccountRepository{}, &mocks.MockUserRepository{}, recordAudit())
+
+		account, err := accountService.AddHolder(ctx, owner, primitive.NewObjectID(), dtos.AddHolderRequest{
+			Email:       input.Email,
+			Permissions: []models.Permission{models.PermissionTransactionReverse},
+		})
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrInvalidHolderPermission, err)
+	})
+
+	t.Run("User Not Found", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, mockUserRepo, recordAudit())
+		accountID := primitive.NewObjectID()
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID), nil)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(nil, nil)
+
+		account, err := accountService.AddHolder(ctx, owner, accountID, input)
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrUserNotFound, err)
+	})
+
+	t.Run("Already Holder", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, mockUserRepo, recordAudit())
+		accountID := primitive.NewObjectID()
+
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(&models.User{ID: primitive.NewObjectID()}, nil)
+		mockAccountRepo.On("AddHolder", ctx, accountID, mock.Anything).Return(nil, nil)
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID), nil)
+
+		account, err := accountService.AddHolder(ctx, owner, accountID, input)
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrAlreadyHolder, err)
+	})
+
+	t.Run("Account Not Found", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, mockUserRepo, recordAudit())
+		accountID := primitive.NewObjectID()
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(nil, nil)
+
+		account, err := accountService.AddHolder(ctx, owner, accountID, input)
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrAccountNotFound, err)
+		mockAccountRepo.AssertNotCalled(t, "AddHolder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Joint Manager Cannot Grant What It Lacks", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, mockUserRepo, recordAudit())
+		accountID := primitive.NewObjectID()
+		manager := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID, models.AccountHolder{
+			UserID:      manager.UserID,
+			Role:        models.HolderRoleJoint,
+			Permissions: []models.Permission{models.PermissionAccountRead, models.PermissionAccountManage},
+		}), nil)
+
+		account, err := accountService.AddHolder(ctx, manager, accountID, dtos.AddHolderRequest{
+			Email:       input.Email,
+			Permissions: []models.Permission{models.PermissionAccountRead, models.PermissionFundsWithdraw},
+		})
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrForbidden, err)
+		mockAccountRepo.AssertNotCalled(t, "AddHolder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Joint Manager Grants What It Holds", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, mockUserRepo, recordAudit())
+		accountID := primitive.NewObjectID()
+		manager := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+		jane := &models.User{ID: primitive.NewObjectID(), Email: input.Email}
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID, models.AccountHolder{
+			UserID:      manager.UserID,
+			Role:        models.HolderRoleJoint,
+			Permissions: []models.Permission{models.PermissionAccountRead, models.PermissionFundsDeposit, models.PermissionAccountManage},
+		}), nil)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(jane, nil)
+		mockAccountRepo.On("AddHolder", ctx, accountID, mock.Anything).Return(&models.Account{ID: accountID}, nil)
+
+		_, err := accountService.AddHolder(ctx, manager, accountID, input)
+
+		assert.NoError(t, err)
+		mockAccountRepo.AssertExpectations(t)
+	})
+
+	t.Run("Admin Grants Any Holder Permission", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, mockUserRepo, recordAudit())
+		accountID := primitive.NewObjectID()
+		admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(&models.User{ID: primitive.NewObjectID()}, nil)
+		mockAccountRepo.On("AddHolder", ctx, accountID, mock.Anything).Return(&models.Account{ID: accountID}, nil)
+
+		_, err := accountService.AddHolder(ctx, admin, accountID, dtos.AddHolderRequest{
+			Email:       input.Email,
+			Permissions: []models.Permission{models.PermissionFundsWithdraw},
+		})
+
+		assert.NoError(t, err)
+		mockAccountRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountService_RemoveHolder(t *testing.T) {
+	ctx := context.Background()
+	owner := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	t.Run("Successful Remove", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, &mocks.MockUserRepository{}, recordAudit())
+		accountID := primitive.NewObjectID()
+		janeID := primitive.NewObjectID()
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID, models.AccountHolder{UserID: janeID, Role: models.HolderRoleJoint}), nil)
+		mockAccountRepo.On("RemoveHolder", ctx, accountID, janeID).Return(&models.Account{ID: accountID}, nil)
+
+		account, err := accountService.RemoveHolder(ctx, owner, accountID, janeID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, accountID, account.ID)
+	})
+
+	t.Run("Owner Cannot Be Removed", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, &mocks.MockUserRepository{}, recordAudit())
+		accountID := primitive.NewObjectID()
+
+		mockAccountRepo.On("RemoveHolder", ctx, accountID, owner.UserID).Return(nil, nil)
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID), nil)
+
+		account, err := accountService.RemoveHolder(ctx, owner, accountID, owner.UserID)
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrHolderNotFound, err)
+	})
+
+	t.Run("Joint Manager Cannot Remove Other Holders", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, &mocks.MockUserRepository{}, recordAudit())
+		accountID := primitive.NewObjectID()
+		manager := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+		janeID := primitive.NewObjectID()
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID,
+			models.AccountHolder{UserID: manager.UserID, Role: models.HolderRoleJoint, Permissions: []models.Permission{models.PermissionAccountManage}},
+			models.AccountHolder{UserID: janeID, Role: models.HolderRoleJoint, Permissions: []models.Permission{models.PermissionAccountRead}},
+		), nil)
+
+		account, err := accountService.RemoveHolder(ctx, manager, accountID, janeID)
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrForbidden, err)
+		mockAccountRepo.AssertNotCalled(t, "RemoveHolder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Joint Manager Leaves Account", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, &mocks.MockUserRepository{}, recordAudit())
+		accountID := primitive.NewObjectID()
+		manager := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(heldAccount(accountID, owner.UserID,
+			models.AccountHolder{UserID: manager.UserID, Role: models.HolderRoleJoint, Permissions: []models.Permission{models.PermissionAccountManage}},
+		), nil)
+		mockAccountRepo.On("RemoveHolder", ctx, accountID, manager.UserID).Return(&models.Account{ID: accountID}, nil)
+
+		_, err := accountService.RemoveHolder(ctx, manager, accountID, manager.UserID)
+
+		assert.NoError(t, err)
+		mockAccountRepo.AssertExpectations(t)
+	})
+}
+
+func TestAccountService_SetTier(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+	t.Run("Successful Change", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, &mocks.MockUserRepository{}, mockAuditRepo)
+		accountID := primitive.NewObjectID()
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(&models.Account{ID: accountID}, nil)
+		mockAccountRepo.On("SetTier", ctx, accountID, models.AccountTierBusiness).Return(&models.Account{ID: accountID, Tier: models.AccountTierBusiness}, nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionTierChanged && event.Details["from"] == "standard" && event.Details["to"] == "business"
+		})).Return(nil)
+
+		account, err := accountService.SetTier(ctx, admin, accountID, models.AccountTierBusiness)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.AccountTierBusiness, account.LimitTier())
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Tier", func(t *testing.T) {
+		accountService := services.NewAccountService(&mocks.MockAccountRepository{}, &mocks.MockUserRepository{}, recordAudit())
+
+		account, err := accountService.SetTier(ctx, admin, primitive.NewObjectID(), models.AccountTier("gold"))
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrInvalidAccountTier, err)
+	})
+
+	t.Run("Account Not Found", func(t *testing.T) {
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		accountService := services.NewAccountService(mockAccountRepo, &mocks.MockUserRepository{}, recordAudit())
+		accountID := primitive.NewObjectID()
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(nil, nil)
+
+		account, err := accountService.SetTier(ctx, admin, accountID, models.AccountTierPremium)
+
+		assert.Nil(t, account)
+		assert.Equal(t, utils.ErrAccountNotFound, err)
+	})
+}
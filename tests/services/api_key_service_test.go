@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAPIKeyService_Create(t *testing.T) {
+	ctx := context.Background()
+	owner := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	t.Run("Secret Shown Once And Stored Hashed", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+
+		var stored *models.APIKey
+		mockAPIKeyRepo.On("Create", ctx, mock.AnythingOfType("*models.APIKey")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.APIKey)
+		}).Return(nil)
+
+		response, err := apiKeyService.Create(ctx, owner, dtos.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"account:read"}})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(response.Key, services.APIKeyPrefix))
+		if assert.NotNil(t, stored) {
+			assert.Equal(t, owner.UserID, stored.UserID)
+			assert.Equal(t, []models.Permission{models.PermissionAccountRead}, stored.Scopes)
+			assert.NotEqual(t, response.Key, stored.KeyHash)
+			assert.True(t, strings.HasPrefix(response.Key, stored.Prefix))
+		}
+		mockAPIKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Expiry In The Past", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+		past := time.Now().Add(-time.Hour)
+
+		response, err := apiKeyService.Create(ctx, owner, dtos.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"account:read"}, ExpiresAt: &past})
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrInvalidExpiry, err)
+		mockAPIKeyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Key Cannot Create Keys", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+		keyID := primitive.NewObjectID()
+		keyPrincipal := &models.Principal{UserID: owner.UserID, APIKeyID: &keyID}
+
+		response, err := apiKeyService.Create(ctx, keyPrincipal, dtos.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"account:read"}})
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrAPIKeyManagement, err)
+	})
+}
+
+func TestAPIKeyService_Rotate(t *testing.T) {
+	ctx := context.Background()
+	owner := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	t.Run("Replaces Key", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+		current := &models.APIKey{ID: primitive.NewObjectID(), UserID: owner.UserID, Name: "ci", Scopes: []models.Permission{models.PermissionFundsDeposit}}
+
+		mockAPIKeyRepo.On("FindByID", ctx, owner.UserID, current.ID).Return(current, nil)
+		mockAPIKeyRepo.On("Revoke", ctx, owner.UserID, current.ID).Return(true, nil)
+		mockAPIKeyRepo.On("Create", ctx, mock.MatchedBy(func(key *models.APIKey) bool {
+			return key.Name == "ci" && key.RotatedFrom != nil && *key.RotatedFrom == current.ID
+		})).Return(nil)
+
+		response, err := apiKeyService.Rotate(ctx, owner, current.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, current.Scopes, response.Scopes)
+		mockAPIKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revoked Key", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+		revokedAt := time.Now().Add(-time.Minute)
+		current := &models.APIKey{ID: primitive.NewObjectID(), UserID: owner.UserID, RevokedAt: &revokedAt}
+
+		mockAPIKeyRepo.On("FindByID", ctx, owner.UserID, current.ID).Return(current, nil)
+
+		response, err := apiKeyService.Rotate(ctx, owner, current.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrAPIKeyNotFound, err)
+		mockAPIKeyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	owner := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	t.Run("Unknown Key", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+		keyID := primitive.NewObjectID()
+
+		mockAPIKeyRepo.On("Revoke", ctx, owner.UserID, keyID).Return(false, nil)
+
+		err := apiKeyService.Revoke(ctx, owner, keyID)
+
+		assert.Equal(t, utils.ErrAPIKeyNotFound, err)
+		mockAPIKeyRepo.AssertExpectations(t)
+	})
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Active Key", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, mockUserRepo, recordAudit())
+		user := &models.User{ID: primitive.NewObjectID(), Email: "bot@example.com"}
+		key := &models.APIKey{ID: primitive.NewObjectID(), UserID: user.ID, Scopes: []models.Permission{models.PermissionAccountRead}}
+
+		mockAPIKeyRepo.On("FindByHash", ctx, mock.AnythingOfType("string")).Return(key, nil)
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockAPIKeyRepo.On("TouchLastUsed", ctx, key.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+		principal, err := apiKeyService.Authenticate(ctx, "axk_secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, principal.UserID)
+		assert.Equal(t, key.ID, *principal.APIKeyID)
+		assert.True(t, principal.Allows(models.PermissionAccountRead))
+		assert.False(t, principal.Allows(models.PermissionFundsWithdraw))
+		mockAPIKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Expired Key", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+		expiredAt := time.Now().Add(-time.Minute)
+
+		mockAPIKeyRepo.On("FindByHash", ctx, mock.AnythingOfType("string")).Return(&models.APIKey{ID: primitive.NewObjectID(), ExpiresAt: &expiredAt}, nil)
+
+		principal, err := apiKeyService.Authenticate(ctx, "axk_secret")
+
+		assert.Nil(t, principal)
+		assert.Equal(t, utils.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("Unknown Key", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, &mocks.MockUserRepository{}, recordAudit())
+
+		mockAPIKeyRepo.On("FindByHash", ctx, mock.AnythingOfType("string")).Return(nil, nil)
+
+		principal, err := apiKeyService.Authenticate(ctx, "axk_unknown")
+
+		assert.Nil(t, principal)
+		assert.Equal(t, utils.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("Blocked User", func(t *testing.T) {
+		mockAPIKeyRepo := &mocks.MockAPIKeyRepository{}
+		mockUserRepo := &mocks.MockUserRepository{}
+		apiKeyService := services.NewAPIKeyService(mockAPIKeyRepo, mockUserRepo, recordAudit())
+		user := &models.User{ID: primitive.NewObjectID(), Status: models.UserStatusBlocked}
+		key := &models.APIKey{ID: primitive.NewObjectID(), UserID: user.ID}
+
+		mockAPIKeyRepo.On("FindByHash", ctx, mock.AnythingOfType("string")).Return(key, nil)
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		principal, err := apiKeyService.Authenticate(ctx, "axk_secret")
+
+		assert.Nil(t, principal)
+		assert.Equal(t, utils.ErrUserBlocked, err)
+	})
+}
@@ -0,0 +1,200 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// recordAudit returns an audit event repository that accepts every event
+func recordAudit() *mocks.MockAuditEventRepository {
+	mockAuditRepo := &mocks.MockAuditEventRepository{}
+	mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	return mockAuditRepo
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Filters And Next Page", func(t *testing.T) {
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		auditService := services.NewAuditService(mockAuditRepo)
+		accountID := primitive.NewObjectID()
+		events := []models.AuditEvent{
+			{ID: primitive.NewObjectID(), Action: models.AuditActionDeposit},
+			{ID: primitive.NewObjectID(), Action: models.AuditActionDeposit},
+			{ID: primitive.NewObjectID(), Action: models.AuditActionDeposit},
+		}
+
+		mockAuditRepo.On("List", ctx, mock.MatchedBy(func(filter dtos.AuditEventFilter) bool {
+			return *filter.TargetAccountID == accountID &&
+				filter.ActorID == nil &&
+				filter.Action == string(models.AuditActionDeposit) &&
+				filter.From.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+				filter.To == nil &&
+				filter.Limit == 3
+		})).Return(events, nil)
+
+		response, err := auditService.ListEvents(ctx, dtos.AuditEventListQuery{
+			AccountID: accountID.Hex(),
+			Action:    string(models.AuditActionDeposit),
+			From:      "2026-01-01T00:00:00Z",
+			Limit:     2,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Events, 2)
+		assert.Equal(t, events[1].ID.Hex(), response.NextCursor)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		auditService := services.NewAuditService(mockAuditRepo)
+		before := primitive.NewObjectID()
+
+		mockAuditRepo.On("List", ctx, mock.MatchedBy(func(filter dtos.AuditEventFilter) bool {
+			return *filter.Before == before && filter.Limit == services.DefaultAuditPageSize+1
+		})).Return([]models.AuditEvent{}, nil)
+
+		response, err := auditService.ListEvents(ctx, dtos.AuditEventListQuery{Cursor: before.Hex()})
+
+		assert.NoError(t, err)
+		assert.Empty(t, response.Events)
+		assert.Empty(t, response.NextCursor)
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		auditService := services.NewAuditService(&mocks.MockAuditEventRepository{})
+
+		response, err := auditService.ListEvents(ctx, dtos.AuditEventListQuery{Cursor: "not-an-id"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrInvalidCursor, err)
+	})
+
+	t.Run("User Filter", func(t *testing.T) {
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		auditService := services.NewAuditService(mockAuditRepo)
+		userID := primitive.NewObjectID()
+
+		mockAuditRepo.On("List", ctx, mock.MatchedBy(func(filter dtos.AuditEventFilter) bool {
+			return *filter.TargetUserID == userID && filter.TargetAccountID == nil
+		})).Return([]models.AuditEvent{}, nil)
+
+		_, err := auditService.ListEvents(ctx, dtos.AuditEventListQuery{UserID: userID.Hex()})
+
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Events From Before Users Name The User", func(t *testing.T) {
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		auditService := services.NewAuditService(mockAuditRepo)
+		userID := primitive.NewObjectID()
+		events := []models.AuditEvent{
+			{ID: primitive.NewObjectID(), Action: models.AuditActionLogin, TargetAccountID: &userID},
+			{ID: primitive.NewObjectID(), Action: models.AuditActionDeposit, TargetAccountID: &userID},
+		}
+
+		mockAuditRepo.On("List", ctx, mock.Anything).Return(events, nil)
+
+		response, err := auditService.ListEvents(ctx, dtos.AuditEventListQuery{UserID: userID.Hex()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &userID, response.Events[0].TargetUserID)
+		assert.Equal(t, &userID, response.Events[0].TargetAccountID, "the stored event is returned as recorded")
+		assert.Nil(t, response.Events[1].TargetUserID, "money events stay about the account")
+	})
+
+	t.Run("Invalid Actor ID", func(t *testing.T) {
+		auditService := services.NewAuditService(&mocks.MockAuditEventRepository{})
+
+		response, err := auditService.ListEvents(ctx, dtos.AuditEventListQuery{ActorID: "not-an-id"})
+
+		assert.Nil(t, response)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthService_LoginAudit(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &models.User{ID: primitive.NewObjectID(), Email: "john@example.com", Password: string(hashedPassword), Role: models.RoleCustomer}
+	ctx := models.ContextWithRequestInfo(context.Background(), models.RequestInfo{IP: "203.0.113.7", UserAgent: "curl/8.0", RequestID: "req-1"})
+
+	t.Run("Successful Login", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, allowLogins(), mockAuditRepo, &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		mockUserRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+		mockRefreshTokenRepo.On("Create", ctx, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionLogin &&
+				*event.ActorID == user.ID &&
+				*event.TargetUserID == user.ID &&
+				event.ActorRole == models.RoleCustomer &&
+				event.IP == "203.0.113.7" &&
+				event.UserAgent == "curl/8.0" &&
+				event.RequestID == "req-1"
+		})).Return(nil)
+
+		_, err := authService.Login(ctx, dtos.LoginRequest{Email: user.Email, Password: "password123", IP: "203.0.113.7"})
+
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed Login", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, allowLogins(), mockAuditRepo, &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		mockUserRepo.On("FindByEmail", ctx, "nobody@example.com").Return(nil, nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionLoginFailed &&
+				event.ActorID == nil &&
+				event.TargetUserID == nil &&
+				event.Details["email"] == "nobody@example.com"
+		})).Return(nil)
+
+		_, err := authService.Login(ctx, dtos.LoginRequest{Email: "nobody@example.com", Password: "password123", IP: "203.0.113.7"})
+
+		assert.Equal(t, services.ErrInvalidCredentials, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserService_UpdateRoleAudit(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := &mocks.MockUserRepository{}
+	mockAuditRepo := &mocks.MockAuditEventRepository{}
+	userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, mockAuditRepo)
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+	userID := primitive.NewObjectID()
+
+	mockUserRepo.On("UpdateRole", ctx, userID, models.RoleTeller).Return(&models.User{ID: userID, Role: models.RoleTeller}, nil)
+	mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+		return event.Action == models.AuditActionRoleChanged &&
+			*event.ActorID == admin.UserID &&
+			event.ActorRole == models.RoleAdmin &&
+			*event.TargetUserID == userID &&
+			event.Details["role"] == "teller"
+	})).Return(nil)
+
+	_, err := userService.UpdateRole(ctx, admin, userID, models.RoleTeller)
+
+	assert.NoError(t, err)
+	mockAuditRepo.AssertExpectations(t)
+}
@@ -2,12 +2,18 @@ package services_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/sms"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,11 +22,27 @@ import (
 )
 
 func TestAuthService_Register(t *testing.T) {
+	mockUserRepo := &mocks.MockUserRepository{}
 	mockAccountRepo := &mocks.MockAccountRepository{}
-	authService := services.NewAuthService(mockAccountRepo)
+	mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+	mockRefreshTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+	mockActionTokenRepo.On("InvalidateAll", mock.Anything, mock.Anything, models.ActionEmailVerification).Return(nil)
+	mockActionTokenRepo.On("InvalidateAll", mock.Anything, mock.Anything, models.ActionPhoneVerification).Return(nil)
+	mockActionTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.ActionToken")).Return(nil)
+	mockMailer := &mocks.MockMailer{}
+	mockTexter := &mocks.MockSMSSender{}
+	authService := services.NewAuthService(mockUserRepo, mockAccountRepo, mockRefreshTokenRepo, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), mockMailer, mockTexter)
 	ctx := context.Background()
 
 	t.Run("Successful Registration", func(t *testing.T) {
+		mockMailer.On("Send", ctx, mock.MatchedBy(func(message mailer.Message) bool {
+			return message.To == "john@example.com" && strings.Contains(message.Body, "/verify-email?token=")
+		})).Return(nil).Once()
+		mockTexter.On("Send", ctx, mock.MatchedBy(func(message sms.Message) bool {
+			return message.To == "+1234567890" && strings.Contains(message.Body, "/verify-phone?token=")
+		})).Return(nil).Once()
+
 		input := dtos.RegisterRequest{
 			Name:        "John Doe",
 			Email:       "john@example.com",
@@ -29,27 +51,41 @@ func TestAuthService_Register(t *testing.T) {
 		}
 
 		// Mock FindByEmail to return nil (no existing user)
-		mockAccountRepo.On("FindByEmail", ctx, input.Email).Return(nil, nil)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(nil, nil)
 
-		// Mock Create to return a new account
-		mockAccountRepo.On("Create", ctx, mock.AnythingOfType("*dtos.CreateAccountDTO")).Return(&models.Account{
+		// Mock Create to return a new user
+		mockUserRepo.On("Create", ctx, mock.AnythingOfType("*dtos.CreateUserDTO")).Return(&models.User{
 			ID:          primitive.NewObjectID(),
 			Name:        input.Name,
 			Email:       input.Email,
 			PhoneNumber: input.PhoneNumber,
-			Status:      models.AccountStatusActive,
+			Status:      models.UserStatusActive,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}, nil)
 
+		// The user is the owner of a current account opened with them
+		mockAccountRepo.On("Create", ctx, mock.MatchedBy(func(account *models.Account) bool {
+			return account.Product == models.AccountProductCurrent &&
+				len(account.Holders) == 1 &&
+				account.Holders[0].Role == models.HolderRoleOwner
+		})).Return(nil)
+
 		response, err := authService.Register(ctx, input)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
 		assert.NotEmpty(t, response.Token)
+		assert.NotEmpty(t, response.RefreshToken)
 		assert.Equal(t, input.Email, response.User.Email)
 		assert.Equal(t, input.Name, response.User.Name)
+		assert.False(t, response.User.EmailVerified)
+		assert.False(t, response.User.PhoneVerified)
+		assert.Equal(t, response.User.ID, response.Account.Holders[0].UserID)
+		mockUserRepo.AssertExpectations(t)
 		mockAccountRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+		mockTexter.AssertExpectations(t)
 	})
 
 	t.Run("Email Already Exists", func(t *testing.T) {
@@ -58,8 +94,8 @@ func TestAuthService_Register(t *testing.T) {
 			Password: "password123",
 		}
 
-		// Mock FindByEmail to return an existing account
-		mockAccountRepo.On("FindByEmail", ctx, input.Email).Return(&models.Account{
+		// Mock FindByEmail to return an existing user
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(&models.User{
 			Email: input.Email,
 		}, nil)
 
@@ -68,7 +104,7 @@ func TestAuthService_Register(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrEmailExists, err)
 		assert.Nil(t, response)
-		mockAccountRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
 
 	t.Run("Repository Error", func(t *testing.T) {
@@ -78,20 +114,31 @@ func TestAuthService_Register(t *testing.T) {
 		}
 
 		// Mock FindByEmail to return an error
-		mockAccountRepo.On("FindByEmail", ctx, input.Email).Return(nil, assert.AnError)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(nil, assert.AnError)
 
 		response, err := authService.Register(ctx, input)
 
 		assert.Error(t, err)
 		assert.Equal(t, assert.AnError, err)
 		assert.Nil(t, response)
-		mockAccountRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
 }
 
+// allowLogins returns a login attempt repository with no recorded failures that counts new ones without locking
+func allowLogins() *mocks.MockLoginAttemptRepository {
+	mockLoginAttemptRepo := &mocks.MockLoginAttemptRepository{}
+	mockLoginAttemptRepo.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockLoginAttemptRepo.On("RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.LoginAttempt{Failures: 1}, nil)
+	mockLoginAttemptRepo.On("Reset", mock.Anything, mock.Anything).Return(nil)
+	return mockLoginAttemptRepo
+}
+
 func TestAuthService_Login(t *testing.T) {
-	mockAccountRepo := &mocks.MockAccountRepository{}
-	authService := services.NewAuthService(mockAccountRepo)
+	mockUserRepo := &mocks.MockUserRepository{}
+	mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+	mockRefreshTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
 	ctx := context.Background()
 
 	t.Run("Successful Login", func(t *testing.T) {
@@ -103,8 +150,8 @@ func TestAuthService_Login(t *testing.T) {
 		// Hash the password as it would be stored in the database
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 
-		// Mock FindByEmail to return an account with the hashed password
-		mockAccountRepo.On("FindByEmail", ctx, input.Email).Return(&models.Account{
+		// Mock FindByEmail to return a user with the hashed password
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(&models.User{
 			ID:       primitive.NewObjectID(),
 			Email:    input.Email,
 			Password: string(hashedPassword),
@@ -115,8 +162,9 @@ func TestAuthService_Login(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
 		assert.NotEmpty(t, response.Token)
+		assert.NotEmpty(t, response.RefreshToken)
 		assert.Equal(t, input.Email, response.User.Email)
-		mockAccountRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
 
 	t.Run("Invalid Credentials - User Not Found", func(t *testing.T) {
@@ -126,14 +174,14 @@ func TestAuthService_Login(t *testing.T) {
 		}
 
 		// Mock FindByEmail to return nil (no user found)
-		mockAccountRepo.On("FindByEmail", ctx, input.Email).Return(nil, nil)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(nil, nil)
 
 		response, err := authService.Login(ctx, input)
 
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInvalidCredentials, err)
 		assert.Nil(t, response)
-		mockAccountRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
 
 	t.Run("Invalid Credentials - Wrong Password", func(t *testing.T) {
@@ -145,8 +193,8 @@ func TestAuthService_Login(t *testing.T) {
 		// Hash a different password
 		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.DefaultCost)
 
-		// Mock FindByEmail to return an account with a different password
-		mockAccountRepo.On("FindByEmail", ctx, input.Email).Return(&models.Account{
+		// Mock FindByEmail to return a user with a different password
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(&models.User{
 			Email:    input.Email,
 			Password: string(hashedPassword),
 		}, nil)
@@ -156,7 +204,7 @@ func TestAuthService_Login(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, services.ErrInvalidCredentials, err)
 		assert.Nil(t, response)
-		mockAccountRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
 	})
 
 	t.Run("Repository Error", func(t *testing.T) {
@@ -166,13 +214,161 @@ func TestAuthService_Login(t *testing.T) {
 		}
 
 		// Mock FindByEmail to return an error
-		mockAccountRepo.On("FindByEmail", ctx, input.Email).Return(nil, assert.AnError)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(nil, assert.AnError)
 
 		response, err := authService.Login(ctx, input)
 
 		assert.Error(t, err)
 		assert.Equal(t, assert.AnError, err)
 		assert.Nil(t, response)
-		mockAccountRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+	t.Run("Blocked User", func(t *testing.T) {
+		input := dtos.LoginRequest{
+			Email:    "blocked@example.com",
+			Password: "password123",
+		}
+
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(&models.User{
+			ID:       primitive.NewObjectID(),
+			Email:    input.Email,
+			Password: string(hashedPassword),
+			Status:   models.UserStatusBlocked,
+		}, nil)
+
+		response, err := authService.Login(ctx, input)
+
+		assert.Equal(t, utils.ErrUserBlocked, err)
+		assert.Nil(t, response)
+	})
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	user := &models.User{ID: primitive.NewObjectID(), Email: "john@example.com"}
+
+	// storedToken returns a refresh token record for plain as the repository would hold it
+	storedToken := func(plain string) *models.RefreshToken {
+		sum := sha256.Sum256([]byte(plain))
+		return &models.RefreshToken{
+			ID:        primitive.NewObjectID(),
+			UserID:    user.ID,
+			FamilyID:  primitive.NewObjectID(),
+			TokenHash: hex.EncodeToString(sum[:]),
+			CreatedAt: time.Now(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("Successful Rotation", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		current := storedToken("current-token")
+
+		mockRefreshTokenRepo.On("FindByHash", ctx, current.TokenHash).Return(current, nil)
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockRefreshTokenRepo.On("MarkUsed", ctx, current.ID, mock.AnythingOfType("primitive.ObjectID")).Return(true, nil)
+		mockRefreshTokenRepo.On("Create", ctx, mock.MatchedBy(func(token *models.RefreshToken) bool {
+			return token.FamilyID == current.FamilyID && token.TokenHash != current.TokenHash
+		})).Return(nil)
+
+		response, err := authService.Refresh(ctx, dtos.RefreshRequest{RefreshToken: "current-token"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		assert.NotEqual(t, "current-token", response.RefreshToken)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reused Token Revokes Family", func(t *testing.T) {
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		current := storedToken("rotated-token")
+		usedAt := time.Now().Add(-time.Minute)
+		current.UsedAt = &usedAt
+
+		mockRefreshTokenRepo.On("FindByHash", ctx, current.TokenHash).Return(current, nil)
+		mockRefreshTokenRepo.On("RevokeFamily", ctx, current.FamilyID).Return(nil)
+
+		response, err := authService.Refresh(ctx, dtos.RefreshRequest{RefreshToken: "rotated-token"})
+
+		assert.Equal(t, services.ErrRefreshTokenReused, err)
+		assert.Nil(t, response)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent Rotation Revokes Family", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		current := storedToken("raced-token")
+
+		mockRefreshTokenRepo.On("FindByHash", ctx, current.TokenHash).Return(current, nil)
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockRefreshTokenRepo.On("MarkUsed", ctx, current.ID, mock.Anything).Return(false, nil)
+		mockRefreshTokenRepo.On("RevokeFamily", ctx, current.FamilyID).Return(nil)
+
+		response, err := authService.Refresh(ctx, dtos.RefreshRequest{RefreshToken: "raced-token"})
+
+		assert.Equal(t, services.ErrRefreshTokenReused, err)
+		assert.Nil(t, response)
+		mockRefreshTokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Expired Token", func(t *testing.T) {
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		current := storedToken("expired-token")
+		current.ExpiresAt = time.Now().Add(-time.Minute)
+
+		mockRefreshTokenRepo.On("FindByHash", ctx, current.TokenHash).Return(current, nil)
+
+		response, err := authService.Refresh(ctx, dtos.RefreshRequest{RefreshToken: "expired-token"})
+
+		assert.Equal(t, services.ErrInvalidRefreshToken, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		mockRefreshTokenRepo.On("FindByHash", ctx, mock.Anything).Return(nil, nil)
+
+		response, err := authService.Refresh(ctx, dtos.RefreshRequest{RefreshToken: "unknown"})
+
+		assert.Equal(t, services.ErrInvalidRefreshToken, err)
+		assert.Nil(t, response)
+	})
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Revokes Session", func(t *testing.T) {
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		current := &models.RefreshToken{ID: primitive.NewObjectID(), FamilyID: primitive.NewObjectID()}
+
+		mockRefreshTokenRepo.On("FindByHash", ctx, mock.Anything).Return(current, nil)
+		mockRefreshTokenRepo.On("RevokeFamily", ctx, current.FamilyID).Return(nil)
+
+		err := authService.Logout(ctx, dtos.LogoutRequest{RefreshToken: "token"})
+
+		assert.NoError(t, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		mockRefreshTokenRepo.On("FindByHash", ctx, mock.Anything).Return(nil, nil)
+
+		err := authService.Logout(ctx, dtos.LogoutRequest{RefreshToken: "token"})
+
+		assert.Equal(t, services.ErrInvalidRefreshToken, err)
 	})
 }
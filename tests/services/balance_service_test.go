@@ -0,0 +1,191 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBalanceService_RebuildBalances(t *testing.T) {
+	ctx := context.Background()
+	accountID := primitive.NewObjectID()
+
+	t.Run("Projections Reset From Postings", func(t *testing.T) {
+		txRunner := &mocks.MockTxRunner{}
+		mockBalanceRepo := &mocks.MockBalanceRepository{}
+		mockLedgerRepo := &mocks.MockLedgerRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		balanceService := services.NewBalanceService(txRunner, mockBalanceRepo, &mocks.MockAccountRepository{}, mockLedgerRepo, mockAuditRepo)
+
+		// EUR has no postings left and is reset to zero
+		mockLedgerRepo.On("SumCustomerPostings", ctx, accountID).Return(map[string]money.Amount{"USD": 7500}, nil)
+		mockBalanceRepo.On("GetBalances", ctx, accountID).Return([]models.Balance{
+			{AccountID: accountID, Currency: "USD", Amount: 7000},
+			{AccountID: accountID, Currency: "EUR", Amount: 300},
+		}, nil).Once()
+		mockBalanceRepo.On("SetBalance", ctx, accountID, money.Amount(7500), "USD").Return(nil)
+		mockBalanceRepo.On("SetBalance", ctx, accountID, money.Amount(0), "EUR").Return(nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionBalancesRebuilt && len(event.Balances) == 2
+		})).Return(nil)
+		mockBalanceRepo.On("GetBalances", ctx, accountID).Return([]models.Balance{
+			{AccountID: accountID, Currency: "USD", Amount: 7500},
+			{AccountID: accountID, Currency: "EUR"},
+		}, nil).Once()
+
+		response, err := balanceService.RebuildBalances(ctx, accountID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.Decimal("75.00"), response.Balances[0].Current)
+		assert.Equal(t, 1, txRunner.Runs)
+		mockBalanceRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed Write Records No Event", func(t *testing.T) {
+		txRunner := &mocks.MockTxRunner{}
+		mockBalanceRepo := &mocks.MockBalanceRepository{}
+		mockLedgerRepo := &mocks.MockLedgerRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		balanceService := services.NewBalanceService(txRunner, mockBalanceRepo, &mocks.MockAccountRepository{}, mockLedgerRepo, mockAuditRepo)
+
+		mockLedgerRepo.On("SumCustomerPostings", ctx, accountID).Return(map[string]money.Amount{"USD": 7500}, nil)
+		mockBalanceRepo.On("GetBalances", ctx, accountID).Return([]models.Balance{}, nil)
+		mockBalanceRepo.On("SetBalance", ctx, accountID, money.Amount(7500), "USD").Return(errors.New("write conflict"))
+
+		response, err := balanceService.RebuildBalances(ctx, accountID)
+
+		assert.Nil(t, response)
+		assert.EqualError(t, err, "write conflict")
+		mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBalanceService_SetOverdraft(t *testing.T) {
+	ctx := context.Background()
+	accountID := primitive.NewObjectID()
+
+	t.Run("Sets Terms In Minor Units", func(t *testing.T) {
+		mockBalanceRepo := &mocks.MockBalanceRepository{}
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		balanceService := services.NewBalanceService(&mocks.MockTxRunner{}, mockBalanceRepo, mockAccountRepo, &mocks.MockLedgerRepository{}, mockAuditRepo)
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(&models.Account{ID: accountID}, nil)
+		overdraft := models.Overdraft{Limit: 50000, InterestRateBps: 1500, DailyFee: 250}
+		mockBalanceRepo.On("SetOverdraft", ctx, accountID, "USD", overdraft).Return(&models.Balance{
+			AccountID: accountID,
+			Currency:  "USD",
+			Amount:    -1000,
+			Overdraft: &overdraft,
+		}, nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionOverdraftSet &&
+				event.Details["limit"] == "500.00" &&
+				event.Details["daily_fee"] == "2.50"
+		})).Return(nil)
+
+		response, err := balanceService.SetOverdraft(ctx, accountID, "USD", dtos.SetOverdraftRequest{
+			Limit:           "500",
+			InterestRateBps: 1500,
+			DailyFee:        "2.50",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.Decimal("500.00"), response.OverdraftLimit)
+		assert.Equal(t, money.Decimal("10.00"), response.OverdraftUsed)
+		assert.Equal(t, money.Decimal("490.00"), response.Available)
+		mockBalanceRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Terms", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			input dtos.SetOverdraftRequest
+		}{
+			{name: "Negative Limit", input: dtos.SetOverdraftRequest{Limit: "-100"}},
+			{name: "Negative Fee", input: dtos.SetOverdraftRequest{Limit: "100", DailyFee: "-1"}},
+			{name: "Unparseable Limit", input: dtos.SetOverdraftRequest{Limit: "lots"}},
+			{name: "Limit Finer Than Minor Units", input: dtos.SetOverdraftRequest{Limit: "100.001"}},
+			{name: "Unparseable Fee", input: dtos.SetOverdraftRequest{Limit: "100", DailyFee: "1,50"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockBalanceRepo := &mocks.MockBalanceRepository{}
+				mockAuditRepo := &mocks.MockAuditEventRepository{}
+				balanceService := services.NewBalanceService(&mocks.MockTxRunner{}, mockBalanceRepo, &mocks.MockAccountRepository{}, &mocks.MockLedgerRepository{}, mockAuditRepo)
+
+				response, err := balanceService.SetOverdraft(ctx, accountID, "USD", tt.input)
+
+				assert.Nil(t, response)
+				var customErr *utils.CustomError
+				if assert.True(t, errors.As(err, &customErr)) {
+					assert.Equal(t, http.StatusBadRequest, customErr.Code)
+				}
+				mockBalanceRepo.AssertNotCalled(t, "SetOverdraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Negative Amounts Are Invalid Amounts", func(t *testing.T) {
+		balanceService := services.NewBalanceService(&mocks.MockTxRunner{}, &mocks.MockBalanceRepository{}, &mocks.MockAccountRepository{}, &mocks.MockLedgerRepository{}, &mocks.MockAuditEventRepository{})
+
+		_, err := balanceService.SetOverdraft(ctx, accountID, "USD", dtos.SetOverdraftRequest{Limit: "100", DailyFee: "-0.01"})
+
+		assert.Equal(t, utils.ErrInvalidAmount, err)
+	})
+
+	t.Run("Account Not Found", func(t *testing.T) {
+		mockBalanceRepo := &mocks.MockBalanceRepository{}
+		mockAccountRepo := &mocks.MockAccountRepository{}
+		balanceService := services.NewBalanceService(&mocks.MockTxRunner{}, mockBalanceRepo, mockAccountRepo, &mocks.MockLedgerRepository{}, &mocks.MockAuditEventRepository{})
+
+		mockAccountRepo.On("FindByID", ctx, accountID).Return(nil, nil)
+
+		response, err := balanceService.SetOverdraft(ctx, accountID, "USD", dtos.SetOverdraftRequest{Limit: "100"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrAccountNotFound, err)
+		mockBalanceRepo.AssertNotCalled(t, "SetOverdraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unusable Currency", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			currency string
+			expected error
+		}{
+			{name: "Unknown", currency: "XYZ", expected: utils.ErrCurrencyNotFound},
+			{name: "Disabled", currency: "HRK", expected: utils.ErrCurrencyDisabled},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockBalanceRepo := &mocks.MockBalanceRepository{}
+				mockAccountRepo := &mocks.MockAccountRepository{}
+				balanceService := services.NewBalanceService(&mocks.MockTxRunner{}, mockBalanceRepo, mockAccountRepo, &mocks.MockLedgerRepository{}, &mocks.MockAuditEventRepository{})
+
+				response, err := balanceService.SetOverdraft(ctx, accountID, tt.currency, dtos.SetOverdraftRequest{Limit: "100"})
+
+				assert.Nil(t, response)
+				assert.Equal(t, tt.expected, err)
+				mockAccountRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+				mockBalanceRepo.AssertNotCalled(t, "SetOverdraft", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
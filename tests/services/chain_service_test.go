@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// buildChain returns n chained transactions of the account, oldest first
+func buildChain(accountID primitive.ObjectID, n int) []models.Transaction {
+	transactions := make([]models.Transaction, n)
+	var previous *models.Transaction
+	for i := range transactions {
+		transactions[i] = models.Transaction{
+			ID:              primitive.NewObjectID(),
+			AccountID:       accountID,
+			Type:            models.TransactionTypeCredit,
+			Amount:          money.Amount(1000 * (i + 1)),
+			Currency:        "USD",
+			Status:          models.TransactionStatusCompleted,
+			ChainVersion:    models.ChainVersionFinalState,
+			TransactionDate: time.Now().Truncate(time.Millisecond),
+		}
+		transactions[i].ChainTo(previous)
+		previous = &transactions[i]
+	}
+	return transactions
+}
+
+func TestTransaction_ChainTo(t *testing.T) {
+	chain := buildChain(primitive.NewObjectID(), 2)
+
+	assert.Equal(t, int64(1), chain[0].ChainSequence)
+	assert.Empty(t, chain[0].PreviousHash)
+	assert.Equal(t, int64(2), chain[1].ChainSequence)
+	assert.Equal(t, chain[0].Hash, chain[1].PreviousHash)
+	assert.Len(t, chain[1].Hash, 64)
+
+	// The reversed amount is checked against the chained reversals instead
+	chain[0].ReversedAmount = 500
+	assert.Equal(t, chain[0].Hash, chain[0].ComputeHash())
+
+	edited := chain[1]
+	edited.Status = models.TransactionStatusCancelled
+	assert.NotEqual(t, chain[1].Hash, edited.ComputeHash())
+
+	edited = chain[1]
+	edited.JournalEntryID = primitive.NewObjectID()
+	assert.NotEqual(t, chain[1].Hash, edited.ComputeHash())
+
+	edited = chain[1]
+	edited.Amount++
+	assert.NotEqual(t, chain[1].Hash, edited.ComputeHash())
+}
+
+func TestTransaction_ChainTo_Legacy(t *testing.T) {
+	// Holds chained before the final state was covered hash their authorized amount and no status
+	hold := models.Transaction{
+		ID: primitive.NewObjectID(), AccountID: primitive.NewObjectID(), Type: models.TransactionTypeDebit,
+		Amount: 5000, HeldAmount: 5000, Currency: "USD", Status: models.TransactionStatusPending,
+		TransactionDate: time.Now().Truncate(time.Millisecond),
+	}
+	hold.ChainTo(nil)
+
+	hold.Status = models.TransactionStatusCompleted
+	hold.Amount = 3000
+	hold.JournalEntryID = primitive.NewObjectID()
+	assert.Equal(t, hold.Hash, hold.ComputeHash())
+}
+
+func TestChainService_VerifyAccount(t *testing.T) {
+	ctx := context.Background()
+	accountID := primitive.NewObjectID()
+
+	verify := func(transactions []models.Transaction, unchained int64) *dtos.ChainVerification {
+		mockRepo := new(mocks.MockTransactionRepository)
+		mockRepo.On("ListChain", ctx, accountID, int64(0), mock.AnythingOfType("int64")).Return(transactions, nil)
+		mockRepo.On("CountUnchained", ctx, accountID).Return(unchained, nil).Maybe()
+
+		result, err := services.NewChainService(mockRepo).VerifyAccount(ctx, accountID)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("Intact", func(t *testing.T) {
+		chain := buildChain(accountID, 3)
+
+		result := verify(chain, 0)
+
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(3), result.Verified)
+		assert.Equal(t, int64(3), result.HeadSequence)
+		assert.Equal(t, chain[2].Hash, result.HeadHash)
+		assert.Nil(t, result.Break)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		result := verify([]models.Transaction{}, 0)
+
+		assert.True(t, result.Valid)
+		assert.Zero(t, result.Verified)
+	})
+
+	t.Run("Edited Amount", func(t *testing.T) {
+		chain := buildChain(accountID, 3)
+		chain[1].Amount = 1
+
+		result := verify(chain, 0)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(1), result.Verified)
+		assert.Equal(t, &dtos.ChainBreak{Sequence: 2, TransactionID: chain[1].ID.Hex(), Reason: dtos.ChainBreakHashMismatch}, result.Break)
+	})
+
+	t.Run("Rehashed After Edit", func(t *testing.T) {
+		chain := buildChain(accountID, 3)
+		chain[1].Amount = 1
+		chain[1].Hash = chain[1].ComputeHash()
+
+		result := verify(chain, 0)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, &dtos.ChainBreak{Sequence: 3, TransactionID: chain[2].ID.Hex(), Reason: dtos.ChainBreakPreviousHashMismatch}, result.Break)
+	})
+
+	t.Run("Deleted Transaction", func(t *testing.T) {
+		chain := buildChain(accountID, 3)
+
+		result := verify([]models.Transaction{chain[0], chain[2]}, 0)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, &dtos.ChainBreak{Sequence: 3, TransactionID: chain[2].ID.Hex(), Reason: dtos.ChainBreakSequenceGap}, result.Break)
+	})
+
+	// reverse appends a chained reversal of amount of the transaction at index i to the chain
+	reverse := func(chain []models.Transaction, i int, amount money.Amount) []models.Transaction {
+		reversal := models.Transaction{
+			ID: primitive.NewObjectID(), AccountID: accountID, Type: models.TransactionTypeDebit, Amount: amount,
+			Currency: "USD", Status: models.TransactionStatusCompleted, ReversalOf: &chain[i].ID,
+			ChainVersion: models.ChainVersionFinalState, TransactionDate: time.Now().Truncate(time.Millisecond),
+		}
+		reversal.ChainTo(&chain[len(chain)-1])
+		return append(chain, reversal)
+	}
+
+	t.Run("Reversed Amount Matches Reversals", func(t *testing.T) {
+		chain := buildChain(accountID, 2)
+		chain = reverse(chain, 1, 500)
+		chain = reverse(chain, 1, 700)
+		chain[1].ReversedAmount = 1200
+
+		result := verify(chain, 0)
+
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(4), result.Verified)
+	})
+
+	t.Run("Edited Reversed Amount", func(t *testing.T) {
+		chain := buildChain(accountID, 2)
+		chain = reverse(chain, 1, 500)
+		chain[1].ReversedAmount = 2000
+
+		result := verify(chain, 0)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, &dtos.ChainBreak{Sequence: 2, TransactionID: chain[1].ID.Hex(), Reason: dtos.ChainBreakReversedAmountMismatch}, result.Break)
+	})
+
+	t.Run("Cleared Reversed Amount", func(t *testing.T) {
+		chain := buildChain(accountID, 2)
+		chain = reverse(chain, 0, 500)
+
+		result := verify(chain, 0)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, &dtos.ChainBreak{Sequence: 3, TransactionID: chain[0].ID.Hex(), Reason: dtos.ChainBreakReversedAmountMismatch}, result.Break)
+	})
+
+	t.Run("Unchained Transaction", func(t *testing.T) {
+		result := verify(buildChain(accountID, 2), 1)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(2), result.Verified)
+		assert.Equal(t, dtos.ChainBreakUnchained, result.Break.Reason)
+	})
+}
@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCurrencyRegistry(t *testing.T) {
+	t.Cleanup(func() { money.SetOverrides(nil) })
+
+	usd, ok := money.LookupCurrency("USD")
+	assert.True(t, ok)
+	assert.Equal(t, "840", usd.NumericCode)
+	assert.Equal(t, 3, money.MinorUnits("kwd"))
+	assert.True(t, money.IsEnabled("EUR"))
+	assert.False(t, money.IsEnabled("eur"), "codes are upper case")
+	assert.False(t, money.IsEnabled("XYZ"))
+	assert.False(t, money.IsEnabled("HRK"), "withdrawn currencies are disabled")
+
+	disabled, units := false, 0
+	money.SetOverrides([]money.CurrencyOverride{{Code: "EUR", Enabled: &disabled}, {Code: "ISK", MinorUnits: &units}})
+
+	assert.False(t, money.IsEnabled("EUR"))
+	_, err := money.Parse("1.5", "ISK")
+	assert.ErrorIs(t, err, money.ErrTooManyDecimals)
+}
+
+func TestCurrencyService_UpdateCurrency(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+	t.Cleanup(func() { money.SetOverrides(nil) })
+
+	t.Run("Disable", func(t *testing.T) {
+		mockCurrencyRepo := &mocks.MockCurrencyRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		currencyService := services.NewCurrencyService(&mocks.MockTxRunner{}, mockCurrencyRepo, mockAuditRepo)
+		disabled := false
+
+		mockCurrencyRepo.On("UpsertOverride", ctx, mock.MatchedBy(func(override *models.CurrencyOverride) bool {
+			return override.Code == "GBP" && !*override.Enabled && override.MinorUnits == nil && *override.UpdatedBy == admin.UserID
+		})).Return(&models.CurrencyOverride{Code: "GBP", Enabled: &disabled}, nil)
+		mockCurrencyRepo.On("ListOverrides", ctx).Return([]models.CurrencyOverride{{Code: "GBP", Enabled: &disabled}}, nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionCurrencyUpdated && event.Details["code"] == "GBP" && event.Details["enabled"] == "false"
+		})).Return(nil)
+
+		currency, err := currencyService.UpdateCurrency(ctx, admin, "gbp", dtos.UpdateCurrencyRequest{Enabled: &disabled})
+
+		assert.NoError(t, err)
+		assert.False(t, currency.Enabled)
+		assert.False(t, money.IsEnabled("GBP"))
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Precision Of Currency In Use", func(t *testing.T) {
+		mockCurrencyRepo := &mocks.MockCurrencyRepository{}
+		txRunner := &mocks.MockTxRunner{}
+		currencyService := services.NewCurrencyService(txRunner, mockCurrencyRepo, recordAudit())
+		units := 3
+
+		mockCurrencyRepo.On("IsInUse", ctx, "USD").Return(true, nil)
+
+		currency, err := currencyService.UpdateCurrency(ctx, admin, "USD", dtos.UpdateCurrencyRequest{MinorUnits: &units})
+
+		assert.Nil(t, currency)
+		assert.Equal(t, utils.ErrCurrencyInUse, err)
+		assert.Equal(t, 1, txRunner.Runs, "the check runs in the transaction that would write the override")
+		mockCurrencyRepo.AssertNotCalled(t, "UpsertOverride", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Precision Of Unused Currency", func(t *testing.T) {
+		mockCurrencyRepo := &mocks.MockCurrencyRepository{}
+		currencyService := services.NewCurrencyService(&mocks.MockTxRunner{}, mockCurrencyRepo, recordAudit())
+		units := 3
+
+		mockCurrencyRepo.On("IsInUse", ctx, "ISK").Return(false, nil)
+		mockCurrencyRepo.On("UpsertOverride", ctx, mock.Anything).Return(&models.CurrencyOverride{Code: "ISK", MinorUnits: &units}, nil)
+		mockCurrencyRepo.On("ListOverrides", ctx).Return([]models.CurrencyOverride{{Code: "ISK", MinorUnits: &units}}, nil)
+
+		currency, err := currencyService.UpdateCurrency(ctx, admin, "ISK", dtos.UpdateCurrencyRequest{MinorUnits: &units})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, currency.MinorUnits)
+		mockCurrencyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Enabling Skips Precision Check", func(t *testing.T) {
+		mockCurrencyRepo := &mocks.MockCurrencyRepository{}
+		currencyService := services.NewCurrencyService(&mocks.MockTxRunner{}, mockCurrencyRepo, recordAudit())
+		enabled := true
+
+		mockCurrencyRepo.On("UpsertOverride", ctx, mock.Anything).Return(&models.CurrencyOverride{Code: "HRK", Enabled: &enabled}, nil)
+		mockCurrencyRepo.On("ListOverrides", ctx).Return([]models.CurrencyOverride{{Code: "HRK", Enabled: &enabled}}, nil)
+
+		_, err := currencyService.UpdateCurrency(ctx, admin, "HRK", dtos.UpdateCurrencyRequest{Enabled: &enabled})
+
+		assert.NoError(t, err)
+		mockCurrencyRepo.AssertNotCalled(t, "IsInUse", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Currency", func(t *testing.T) {
+		currencyService := services.NewCurrencyService(&mocks.MockTxRunner{}, &mocks.MockCurrencyRepository{}, recordAudit())
+		enabled := true
+
+		currency, err := currencyService.UpdateCurrency(ctx, admin, "XYZ", dtos.UpdateCurrencyRequest{Enabled: &enabled})
+
+		assert.Nil(t, currency)
+		assert.Equal(t, utils.ErrCurrencyNotFound, err)
+	})
+}
+
+func TestCurrencyService_ResetCurrency(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+	t.Cleanup(func() { money.SetOverrides(nil) })
+
+	t.Run("Restored Precision Of Currency In Use", func(t *testing.T) {
+		mockCurrencyRepo := &mocks.MockCurrencyRepository{}
+		currencyService := services.NewCurrencyService(&mocks.MockTxRunner{}, mockCurrencyRepo, recordAudit())
+		units := 0
+
+		mockCurrencyRepo.On("ListOverrides", ctx).Return([]models.CurrencyOverride{{Code: "ISK", MinorUnits: &units}}, nil)
+		mockCurrencyRepo.On("IsInUse", ctx, "ISK").Return(true, nil)
+
+		currency, err := currencyService.ResetCurrency(ctx, admin, "ISK")
+
+		assert.Nil(t, currency)
+		assert.Equal(t, utils.ErrCurrencyInUse, err)
+		mockCurrencyRepo.AssertNotCalled(t, "DeleteOverride", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Enablement Only", func(t *testing.T) {
+		mockCurrencyRepo := &mocks.MockCurrencyRepository{}
+		currencyService := services.NewCurrencyService(&mocks.MockTxRunner{}, mockCurrencyRepo, recordAudit())
+		disabled := false
+
+		mockCurrencyRepo.On("ListOverrides", ctx).Return([]models.CurrencyOverride{{Code: "EUR", Enabled: &disabled}}, nil).Once()
+		mockCurrencyRepo.On("DeleteOverride", ctx, "EUR").Return(true, nil)
+		mockCurrencyRepo.On("ListOverrides", ctx).Return([]models.CurrencyOverride{}, nil)
+
+		currency, err := currencyService.ResetCurrency(ctx, admin, "EUR")
+
+		assert.NoError(t, err)
+		assert.True(t, currency.Enabled)
+		mockCurrencyRepo.AssertNotCalled(t, "IsInUse", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,159 @@
+package services_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/fx"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func staticRates(t *testing.T) fx.RateProvider {
+	rates, err := fx.NewStaticProvider(map[string]string{"USD/EUR": "0.92", "USD/JPY": "151.50"})
+	assert.NoError(t, err)
+	return rates
+}
+
+func TestFXService_CreateQuote(t *testing.T) {
+	ctx := context.Background()
+	principal := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+	accountID := primitive.NewObjectID()
+
+	t.Run("Spread Taken From Mid Rate", func(t *testing.T) {
+		mockQuoteRepo := &mocks.MockFXQuoteRepository{}
+		fxService := services.NewFXService(nil, mockQuoteRepo, staticRates(t))
+
+		// 100.00 USD at 0.92 is 92.00 EUR mid-market; a 50 bps spread keeps 0.46 EUR
+		mockQuoteRepo.On("Create", ctx, mock.MatchedBy(func(quote *models.FXQuote) bool {
+			return quote.AccountID == accountID &&
+				quote.UserID == principal.UserID &&
+				quote.SellAmount == 10000 &&
+				quote.BuyAmount == 9154 &&
+				quote.Spread == 46 &&
+				quote.MidRate == "0.92" &&
+				quote.Rate == "0.9154" &&
+				quote.ExpiresAt.After(quote.CreatedAt)
+		})).Return(nil)
+
+		response, err := fxService.CreateQuote(ctx, principal, accountID, "usd", "eur", 10000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", response.BuyCurrency)
+		assert.Equal(t, money.Decimal("91.54"), response.BuyAmount)
+		mockQuoteRepo.AssertExpectations(t)
+	})
+
+	t.Run("Inverse Rate And Minor Units", func(t *testing.T) {
+		mockQuoteRepo := &mocks.MockFXQuoteRepository{}
+		fxService := services.NewFXService(nil, mockQuoteRepo, staticRates(t))
+
+		// 10000 JPY at 1/151.50 is 66.0066 USD; amounts are rounded down to the cent
+		mockQuoteRepo.On("Create", ctx, mock.MatchedBy(func(quote *models.FXQuote) bool {
+			return quote.BuyAmount == 6567 && quote.Spread == 33
+		})).Return(nil)
+
+		response, err := fxService.CreateQuote(ctx, principal, accountID, "JPY", "USD", 10000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.Decimal("65.67"), response.BuyAmount)
+	})
+
+	t.Run("Unsupported Pair", func(t *testing.T) {
+		fxService := services.NewFXService(nil, &mocks.MockFXQuoteRepository{}, staticRates(t))
+
+		response, err := fxService.CreateQuote(ctx, principal, accountID, "EUR", "JPY", 10000)
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrUnsupportedCurrencyPair, err)
+	})
+
+	t.Run("Same Currency", func(t *testing.T) {
+		fxService := services.NewFXService(nil, &mocks.MockFXQuoteRepository{}, staticRates(t))
+
+		response, err := fxService.CreateQuote(ctx, principal, accountID, "USD", "usd", 10000)
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrSameCurrency, err)
+	})
+
+	t.Run("Disabled Currency", func(t *testing.T) {
+		fxService := services.NewFXService(nil, &mocks.MockFXQuoteRepository{}, staticRates(t))
+		disabled := false
+		money.SetOverrides([]money.CurrencyOverride{{Code: "EUR", Enabled: &disabled}})
+		t.Cleanup(func() { money.SetOverrides(nil) })
+
+		response, err := fxService.CreateQuote(ctx, principal, accountID, "USD", "EUR", 10000)
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrCurrencyDisabled, err)
+	})
+
+	t.Run("Too Small To Convert", func(t *testing.T) {
+		fxService := services.NewFXService(nil, &mocks.MockFXQuoteRepository{}, staticRates(t))
+
+		response, err := fxService.CreateQuote(ctx, principal, accountID, "JPY", "USD", 1)
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrInvalidAmount, err)
+	})
+}
+
+func TestFXService_GetQuote(t *testing.T) {
+	ctx := context.Background()
+	principal := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleCustomer}
+
+	t.Run("Own Quote", func(t *testing.T) {
+		mockQuoteRepo := &mocks.MockFXQuoteRepository{}
+		fxService := services.NewFXService(nil, mockQuoteRepo, staticRates(t))
+		quote := &models.FXQuote{ID: primitive.NewObjectID(), UserID: principal.UserID}
+
+		mockQuoteRepo.On("FindByID", ctx, quote.ID).Return(quote, nil)
+
+		found, err := fxService.GetQuote(ctx, principal, quote.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, quote, found)
+	})
+
+	t.Run("Other User's Quote", func(t *testing.T) {
+		mockQuoteRepo := &mocks.MockFXQuoteRepository{}
+		fxService := services.NewFXService(nil, mockQuoteRepo, staticRates(t))
+		quote := &models.FXQuote{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+
+		mockQuoteRepo.On("FindByID", ctx, quote.ID).Return(quote, nil)
+
+		found, err := fxService.GetQuote(ctx, principal, quote.ID)
+
+		assert.Nil(t, found)
+		assert.Equal(t, utils.ErrQuoteNotFound, err)
+	})
+}
+
+func TestFXFileProvider(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rates.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"USD/EUR": "0.92", "EUR/USD": "1.08"}`), 0o600))
+
+	rates, err := fx.NewFileProvider(path)
+	assert.NoError(t, err)
+
+	rate, err := rates.Rate(ctx, "EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rate.Cmp(big.NewRat(108, 100)), "listed pairs take precedence over derived inverses")
+
+	_, err = rates.Rate(ctx, "USD", "GBP")
+	assert.ErrorIs(t, err, fx.ErrRateUnavailable)
+
+	_, err = fx.NewFileProvider(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
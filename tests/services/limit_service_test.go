@@ -0,0 +1,387 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLimitPolicy_Check(t *testing.T) {
+	policy := &models.LimitPolicy{
+		Tier:              models.AccountTierStandard,
+		Currency:          "USD",
+		Direction:         models.LimitDirectionWithdrawal,
+		MinPerTransaction: 100,
+		MaxPerTransaction: 100000,
+		DailyCount:        3,
+		DailyAmount:       150000,
+		MonthlyAmount:     1000000,
+	}
+
+	tests := []struct {
+		name   string
+		usage  *models.LimitUsage
+		amount money.Amount
+		breach *models.LimitBreach
+	}{
+		{"Within limits", &models.LimitUsage{DailyCount: 2, DailyAmount: 50000, MonthlyAmount: 800000}, 100000, nil},
+		{"Below minimum", &models.LimitUsage{}, 99, &models.LimitBreach{Limit: models.LimitMinPerTransaction, Allowed: 100}},
+		{"Above maximum", &models.LimitUsage{}, 100001, &models.LimitBreach{Limit: models.LimitMaxPerTransaction, Allowed: 100000}},
+		{"Daily count", &models.LimitUsage{DailyCount: 3, DailyAmount: 300}, 100, &models.LimitBreach{Limit: models.LimitDailyCount, Allowed: 3, Used: 3}},
+		{"Daily amount", &models.LimitUsage{DailyCount: 1, DailyAmount: 60000}, 90001, &models.LimitBreach{Limit: models.LimitDailyAmount, Allowed: 150000, Used: 60000}},
+		{"Monthly amount", &models.LimitUsage{MonthlyAmount: 950000}, 50001, &models.LimitBreach{Limit: models.LimitMonthlyAmount, Allowed: 1000000, Used: 950000}},
+		{"Unset monthly count is not enforced", &models.LimitUsage{MonthlyCount: 500}, 100, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.breach, policy.Check(tt.usage, tt.amount))
+		})
+	}
+}
+
+func TestLimitName_ResetsAt(t *testing.T) {
+	// Still New Year's Eve locally, but already January in UTC
+	now := time.Date(2026, time.December, 31, 20, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	dayStart, monthStart := models.LimitWindows(now)
+	assert.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), dayStart)
+	assert.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), monthStart)
+
+	assert.Equal(t, time.Date(2027, time.January, 2, 0, 0, 0, 0, time.UTC), *models.LimitDailyAmount.ResetsAt(now))
+	assert.Equal(t, time.Date(2027, time.February, 1, 0, 0, 0, 0, time.UTC), *models.LimitMonthlyCount.ResetsAt(now))
+	assert.Nil(t, models.LimitMaxPerTransaction.ResetsAt(now))
+}
+
+func TestLimitService_SetPolicy(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+	t.Run("Successful Set", func(t *testing.T) {
+		mockLimitRepo := &mocks.MockLimitPolicyRepository{}
+		mockAuditRepo := &mocks.MockAuditEventRepository{}
+		limitService := services.NewLimitService(mockLimitRepo, mockAuditRepo)
+
+		mockLimitRepo.On("Upsert", ctx, mock.MatchedBy(func(policy *models.LimitPolicy) bool {
+			return policy.Tier == models.AccountTierBusiness && policy.Currency == "EUR" && policy.Direction == models.LimitDirectionDeposit &&
+				policy.MaxPerTransaction == 500000 && policy.DailyCount == 10 && policy.MinPerTransaction == 0 && *policy.UpdatedBy == admin.UserID
+		})).Return(&models.LimitPolicy{
+			Tier:              models.AccountTierBusiness,
+			Currency:          "EUR",
+			Direction:         models.LimitDirectionDeposit,
+			MaxPerTransaction: 500000,
+			DailyCount:        10,
+		}, nil)
+		mockAuditRepo.On("Create", ctx, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionLimitPolicySet && event.Details["max_per_transaction"] == "5000.00"
+		})).Return(nil)
+
+		policy, err := limitService.SetPolicy(ctx, admin, "business", "eur", "deposit", dtos.SetLimitPolicyRequest{
+			MaxPerTransaction: "5000",
+			DailyCount:        10,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.Decimal("5000.00"), policy.MaxPerTransaction)
+		assert.Equal(t, money.Decimal(""), policy.MinPerTransaction)
+		assert.Equal(t, int64(10), policy.DailyCount)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Tier", func(t *testing.T) {
+		limitService := services.NewLimitService(&mocks.MockLimitPolicyRepository{}, recordAudit())
+
+		policy, err := limitService.SetPolicy(ctx, admin, "gold", "USD", "deposit", dtos.SetLimitPolicyRequest{})
+
+		assert.Nil(t, policy)
+		assert.Equal(t, utils.ErrInvalidAccountTier, err)
+	})
+
+	t.Run("Unknown Direction", func(t *testing.T) {
+		limitService := services.NewLimitService(&mocks.MockLimitPolicyRepository{}, recordAudit())
+
+		policy, err := limitService.SetPolicy(ctx, admin, "standard", "USD", "transfer", dtos.SetLimitPolicyRequest{})
+
+		assert.Nil(t, policy)
+		assert.Equal(t, utils.ErrInvalidLimitDirection, err)
+	})
+
+	t.Run("Minimum Above Maximum", func(t *testing.T) {
+		limitService := services.NewLimitService(&mocks.MockLimitPolicyRepository{}, recordAudit())
+
+		policy, err := limitService.SetPolicy(ctx, admin, "standard", "USD", "withdrawal", dtos.SetLimitPolicyRequest{
+			MinPerTransaction: "100",
+			MaxPerTransaction: "50",
+		})
+
+		assert.Nil(t, policy)
+		assert.Equal(t, utils.ErrInvalidLimitPolicy, err)
+	})
+}
+
+func TestLimitService_DeletePolicy(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+	t.Run("Successful Delete", func(t *testing.T) {
+		mockLimitRepo := &mocks.MockLimitPolicyRepository{}
+		limitService := services.NewLimitService(mockLimitRepo, recordAudit())
+
+		mockLimitRepo.On("Delete", ctx, models.AccountTierPremium, "GBP", models.LimitDirectionWithdrawal).Return(true, nil)
+
+		assert.NoError(t, limitService.DeletePolicy(ctx, admin, "premium", "GBP", "withdrawal"))
+	})
+
+	t.Run("Policy Not Found", func(t *testing.T) {
+		mockLimitRepo := &mocks.MockLimitPolicyRepository{}
+		limitService := services.NewLimitService(mockLimitRepo, recordAudit())
+
+		mockLimitRepo.On("Delete", ctx, models.AccountTierPremium, "GBP", models.LimitDirectionWithdrawal).Return(false, nil)
+
+		assert.Equal(t, utils.ErrLimitPolicyNotFound, limitService.DeletePolicy(ctx, admin, "premium", "GBP", "withdrawal"))
+	})
+}
+
+// limitAccount registers an account of tier with an active owner and policy as the only limit policy
+// that applies to it, then allows the rest of the movement
+func (s *testTransactionService) limitAccount(tier models.AccountTier, policy *models.LimitPolicy) primitive.ObjectID {
+	accountID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	s.mockAccountRepo.On("FindByID", mock.Anything, accountID).Return(&models.Account{
+		ID:      accountID,
+		Tier:    tier,
+		Holders: []models.AccountHolder{{UserID: ownerID, Role: models.HolderRoleOwner}},
+	}, nil)
+	s.mockUserRepo.On("FindByID", mock.Anything, ownerID).Return(&models.User{ID: ownerID, Status: models.UserStatusActive}, nil)
+	s.mockLimitRepo.On("Find", mock.Anything, policy.Tier, policy.Currency, policy.Direction).Return(policy, nil)
+	s.allowMovement(accountID)
+	return accountID
+}
+
+// assertLimitExceeded checks err is the limit_exceeded error with details, apart from when the window resets
+func assertLimitExceeded(t *testing.T, err error, message string, details dtos.LimitExceededDetails) *dtos.LimitExceededDetails {
+	var customErr *utils.CustomError
+	if !assert.True(t, errors.As(err, &customErr)) {
+		return nil
+	}
+	assert.Equal(t, http.StatusUnprocessableEntity, customErr.Code)
+	assert.Equal(t, utils.ReasonLimitExceeded, customErr.Reason)
+	assert.Equal(t, message, customErr.Message)
+
+	actual, ok := customErr.Details.(dtos.LimitExceededDetails)
+	if !assert.True(t, ok) {
+		return nil
+	}
+	resetsAt := actual.ResetsAt
+	actual.ResetsAt = nil
+	assert.Equal(t, details, actual)
+	actual.ResetsAt = resetsAt
+	return &actual
+}
+
+func TestTransactionService_Limits(t *testing.T) {
+	ctx := context.Background()
+
+	// The usage query covers the current UTC day and month
+	dayStart := mock.MatchedBy(func(start time.Time) bool {
+		return start.Location() == time.UTC && start.Equal(start.Truncate(24*time.Hour)) && time.Since(start) < 24*time.Hour
+	})
+	monthStart := mock.MatchedBy(func(start time.Time) bool {
+		return start.Location() == time.UTC && start.Day() == 1 && start.Equal(start.Truncate(24*time.Hour)) && time.Since(start) < 31*24*time.Hour
+	})
+
+	t.Run("Deposit Above Tier Maximum", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := testService.limitAccount(models.AccountTierPremium, &models.LimitPolicy{
+			Tier:              models.AccountTierPremium,
+			Currency:          "USD",
+			Direction:         models.LimitDirectionDeposit,
+			MaxPerTransaction: 100000,
+		})
+
+		// Execute
+		result, err := testService.TransactionService.Deposit(ctx, accountID, 150000, "USD", "")
+
+		// Assert
+		assert.Empty(t, result)
+		details := assertLimitExceeded(t, err, "deposit exceeds the maximum per transaction limit", dtos.LimitExceededDetails{
+			Limit:     "max_per_transaction",
+			Direction: "deposit",
+			Currency:  "USD",
+			Tier:      "premium",
+			Allowed:   "1000.00",
+		})
+		if details != nil {
+			assert.Nil(t, details.ResetsAt)
+		}
+		testService.mockTransactionRepo.AssertNotCalled(t, "SumLimitUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockTransactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deposit Below Tier Minimum", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := testService.limitAccount(models.AccountTierBusiness, &models.LimitPolicy{
+			Tier:              models.AccountTierBusiness,
+			Currency:          "USD",
+			Direction:         models.LimitDirectionDeposit,
+			MinPerTransaction: 1000,
+		})
+
+		// Execute
+		_, err := testService.TransactionService.Deposit(ctx, accountID, 999, "USD", "")
+
+		// Assert
+		assertLimitExceeded(t, err, "deposit is below the minimum per transaction", dtos.LimitExceededDetails{
+			Limit:     "min_per_transaction",
+			Direction: "deposit",
+			Currency:  "USD",
+			Tier:      "business",
+			Allowed:   "10.00",
+		})
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Withdrawal Over Daily Amount Of Untiered Account", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := testService.limitAccount("", &models.LimitPolicy{
+			Tier:        models.AccountTierStandard,
+			Currency:    "USD",
+			Direction:   models.LimitDirectionWithdrawal,
+			DailyAmount: 50000,
+		})
+		testService.mockTransactionRepo.On("SumLimitUsage", mock.Anything, accountID, "USD", models.LimitDirectionWithdrawal, dayStart, monthStart).
+			Return(&models.LimitUsage{DailyCount: 2, DailyAmount: 45000, MonthlyCount: 2, MonthlyAmount: 45000}, nil)
+
+		// Execute
+		_, err := testService.TransactionService.Withdraw(ctx, accountID, 6000, "USD", "")
+
+		// Assert
+		details := assertLimitExceeded(t, err, "withdrawal exceeds the daily amount limit", dtos.LimitExceededDetails{
+			Limit:     "daily_amount",
+			Direction: "withdrawal",
+			Currency:  "USD",
+			Tier:      "standard",
+			Allowed:   "500.00",
+			Used:      "450.00",
+		})
+		if details != nil && assert.NotNil(t, details.ResetsAt) {
+			assert.True(t, details.ResetsAt.After(time.Now()))
+			assert.Equal(t, details.ResetsAt.Truncate(24*time.Hour), *details.ResetsAt)
+		}
+		testService.mockTransactionRepo.AssertExpectations(t)
+		testService.mockBalanceRepo.AssertNotCalled(t, "CheckAndDeductBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockLedgerRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Withdrawal Within Daily Amount", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := testService.limitAccount("", &models.LimitPolicy{
+			Tier:        models.AccountTierStandard,
+			Currency:    "USD",
+			Direction:   models.LimitDirectionWithdrawal,
+			DailyAmount: 50000,
+		})
+		testService.mockTransactionRepo.On("SumLimitUsage", mock.Anything, accountID, "USD", models.LimitDirectionWithdrawal, dayStart, monthStart).
+			Return(&models.LimitUsage{DailyCount: 1, DailyAmount: 44000, MonthlyCount: 1, MonthlyAmount: 44000}, nil)
+		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, accountID, money.Amount(6000), "USD").Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil)
+
+		// Execute
+		_, err := testService.TransactionService.Withdraw(ctx, accountID, 6000, "USD", "")
+
+		// Assert
+		assert.NoError(t, err)
+		testService.mockTransactionRepo.AssertExpectations(t)
+		testService.mockBalanceRepo.AssertCalled(t, "CheckAndDeductBalance", mock.Anything, accountID, money.Amount(6000), "USD")
+	})
+
+	t.Run("Authorize Counts Hold Against Withdrawal Limits", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := testService.limitAccount(models.AccountTierStandard, &models.LimitPolicy{
+			Tier:       models.AccountTierStandard,
+			Currency:   "USD",
+			Direction:  models.LimitDirectionWithdrawal,
+			DailyCount: 3,
+		})
+
+		// Pending holds are part of the usage, so three earlier holds use up the day
+		testService.mockTransactionRepo.On("SumLimitUsage", mock.Anything, accountID, "USD", models.LimitDirectionWithdrawal, dayStart, monthStart).
+			Return(&models.LimitUsage{DailyCount: 3, DailyAmount: 3000, MonthlyCount: 3, MonthlyAmount: 3000}, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Authorize(ctx, accountID, 1000, "USD", "hotel", time.Hour)
+
+		// Assert
+		assert.Nil(t, result)
+		assertLimitExceeded(t, err, "withdrawal exceeds the daily transaction count limit", dtos.LimitExceededDetails{
+			Limit:     "daily_count",
+			Direction: "withdrawal",
+			Currency:  "USD",
+			Tier:      "standard",
+			Allowed:   "3",
+			Used:      "3",
+		})
+		testService.mockBalanceRepo.AssertNotCalled(t, "PlaceHold", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockTransactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Authorize Within Withdrawal Limits", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := testService.limitAccount(models.AccountTierStandard, &models.LimitPolicy{
+			Tier:       models.AccountTierStandard,
+			Currency:   "USD",
+			Direction:  models.LimitDirectionWithdrawal,
+			DailyCount: 3,
+		})
+		testService.mockTransactionRepo.On("SumLimitUsage", mock.Anything, accountID, "USD", models.LimitDirectionWithdrawal, dayStart, monthStart).
+			Return(&models.LimitUsage{DailyCount: 2, DailyAmount: 2000, MonthlyCount: 2, MonthlyAmount: 2000}, nil)
+		testService.mockBalanceRepo.On("PlaceHold", mock.Anything, accountID, money.Amount(1000), "USD").Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
+			return dto.Status == string(models.TransactionStatusPending) && dto.HeldAmount == 1000
+		})).Return(&models.Transaction{ID: primitive.NewObjectID(), Status: models.TransactionStatusPending, HeldAmount: 1000, Amount: 1000, Currency: "USD"}, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Authorize(ctx, accountID, 1000, "USD", "hotel", time.Hour)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, string(models.TransactionStatusPending), result.Status)
+		testService.mockTransactionRepo.AssertExpectations(t)
+		testService.mockBalanceRepo.AssertCalled(t, "PlaceHold", mock.Anything, accountID, money.Amount(1000), "USD")
+	})
+
+	t.Run("Policy Lookup Error", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		testService.mockLimitRepo.On("Find", mock.Anything, models.AccountTierStandard, "USD", models.LimitDirectionDeposit).Return(nil, errors.New("database error"))
+		testService.allowMovement(accountID)
+
+		// Execute
+		_, err := testService.TransactionService.Deposit(ctx, accountID, 1000, "USD", "")
+
+		// Assert
+		assert.EqualError(t, err, "database error")
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthService_LoginThrottle(t *testing.T) {
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	input := dtos.LoginRequest{Email: "John@Example.com", Password: "wrongpassword", IP: "203.0.113.7"}
+
+	t.Run("Progressive Delay", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockLoginAttemptRepo := &mocks.MockLoginAttemptRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, mockLoginAttemptRepo, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		// Three failures a moment ago impose a wait of four delay units
+		mockLoginAttemptRepo.On("Get", ctx, "email:john@example.com", mock.Anything).Return(&models.LoginAttempt{Failures: 3, LastFailureAt: time.Now()}, nil)
+		mockLoginAttemptRepo.On("Get", ctx, "ip:203.0.113.7", mock.Anything).Return(nil, nil)
+
+		response, err := authService.Login(ctx, input)
+
+		assert.Nil(t, response)
+		var throttled *services.LoginThrottledError
+		if assert.True(t, errors.As(err, &throttled)) {
+			assert.InDelta(t, float64(4*services.LoginDelayBase), float64(throttled.RetryAfter), float64(time.Second))
+		}
+		mockUserRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Lockout After Max Failures", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockLoginAttemptRepo := &mocks.MockLoginAttemptRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, mockLoginAttemptRepo, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user := &models.User{ID: primitive.NewObjectID(), Email: "john@example.com", Password: string(hashedPassword)}
+
+		mockLoginAttemptRepo.On("Get", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(user, nil)
+		mockLoginAttemptRepo.On("RecordFailure", ctx, "email:john@example.com", mock.Anything, services.LoginFailureWindow).Return(&models.LoginAttempt{Failures: services.LoginMaxFailures}, nil)
+		mockLoginAttemptRepo.On("Lock", ctx, "email:john@example.com", mock.AnythingOfType("time.Time")).Return(nil)
+		mockUserRepo.On("Lock", ctx, user.ID, mock.MatchedBy(func(event models.LockoutEvent) bool {
+			return event.Action == models.LockoutActionLocked && event.Until != nil
+		})).Return(nil)
+		mockLoginAttemptRepo.On("RecordFailure", ctx, "ip:203.0.113.7", mock.Anything, services.LoginFailureWindow).Return(&models.LoginAttempt{Failures: 1}, nil)
+
+		response, err := authService.Login(ctx, input)
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrInvalidCredentials, err)
+		mockUserRepo.AssertExpectations(t)
+		mockLoginAttemptRepo.AssertExpectations(t)
+	})
+
+	t.Run("Locked User", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		lockedUntil := time.Now().Add(10 * time.Minute)
+		user := &models.User{ID: primitive.NewObjectID(), Email: "john@example.com", Password: string(hashedPassword), LockedUntil: &lockedUntil}
+
+		mockUserRepo.On("FindByEmail", ctx, input.Email).Return(user, nil)
+
+		// Even the right password is refused during a lockout
+		response, err := authService.Login(ctx, dtos.LoginRequest{Email: input.Email, Password: "password123", IP: input.IP})
+
+		assert.Nil(t, response)
+		var throttled *services.LoginThrottledError
+		if assert.True(t, errors.As(err, &throttled)) {
+			assert.InDelta(t, float64(10*time.Minute), float64(throttled.RetryAfter), float64(time.Second))
+		}
+	})
+
+	t.Run("Blocked IP", func(t *testing.T) {
+		mockLoginAttemptRepo := &mocks.MockLoginAttemptRepository{}
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, mockLoginAttemptRepo, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		lockedUntil := time.Now().Add(5 * time.Minute)
+
+		mockLoginAttemptRepo.On("Get", ctx, "email:john@example.com", mock.Anything).Return(nil, nil)
+		mockLoginAttemptRepo.On("Get", ctx, "ip:203.0.113.7", mock.Anything).Return(&models.LoginAttempt{Failures: services.LoginIPMaxFailures, LockedUntil: &lockedUntil}, nil)
+
+		response, err := authService.Login(ctx, input)
+
+		assert.Nil(t, response)
+		var throttled *services.LoginThrottledError
+		assert.True(t, errors.As(err, &throttled))
+	})
+}
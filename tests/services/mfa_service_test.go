@@ -0,0 +1,348 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/jwt"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/totp"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// mfaUser returns a user with MFA enabled and its current TOTP code
+func mfaUser(t *testing.T) (*models.User, string) {
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	assert.NoError(t, err)
+
+	return &models.User{
+		ID:    primitive.NewObjectID(),
+		Email: "john@example.com",
+		MFA:   models.MFA{Enabled: true, Secret: secret},
+	}, code
+}
+
+func TestAuthService_LoginWithMFA(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := &mocks.MockUserRepository{}
+	mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+	loginAttempts := allowLogins()
+	authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, loginAttempts, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+	user, _ := mfaUser(t)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	user.Password = string(hashedPassword)
+	mockUserRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+	var challengeID string
+	mockUserRepo.On("SetMFAChallenge", ctx, user.ID, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		challengeID = args.String(2)
+	}).Return(nil)
+
+	response, err := authService.Login(ctx, dtos.LoginRequest{Email: user.Email, Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.True(t, response.MFARequired)
+	assert.Empty(t, response.Token)
+	assert.Empty(t, response.RefreshToken)
+	subject, tokenID, err := jwt.ValidateMFAToken(response.MFAToken)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID.Hex(), subject)
+	assert.Equal(t, challengeID, tokenID, "the challenge is stored so the token can be used once")
+
+	// Failures are kept until the second factor is verified
+	loginAttempts.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+
+	// The challenge token is not an access token
+	_, err = jwt.ValidateToken(response.MFAToken)
+	assert.Error(t, err)
+	mockRefreshTokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// mfaChallenge returns an MFA token for user and records it as the user's outstanding challenge
+func mfaChallenge(t *testing.T, user *models.User) string {
+	token, challengeID, err := jwt.GenerateMFAToken(user.ID.Hex())
+	assert.NoError(t, err)
+	user.MFA.ChallengeID = challengeID
+	return token
+}
+
+func TestAuthService_VerifyMFA(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid Code", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		mockRefreshTokenRepo.On("Create", ctx, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+		loginAttempts := allowLogins()
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, loginAttempts, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, code := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("AdvanceMFAStep", ctx, user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+		mockUserRepo.On("ConsumeMFAChallenge", ctx, user.ID, user.MFA.ChallengeID).Return(true, nil)
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, Code: code, IP: "203.0.113.7"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		assert.NotEmpty(t, response.RefreshToken)
+		mockUserRepo.AssertExpectations(t)
+		loginAttempts.AssertCalled(t, "Reset", ctx, "email:john@example.com")
+	})
+
+	t.Run("Replayed Code", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, code := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("AdvanceMFAStep", ctx, user.ID, mock.AnythingOfType("int64")).Return(false, nil)
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, Code: code})
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrInvalidMFACode, err)
+		mockUserRepo.AssertNotCalled(t, "ConsumeMFAChallenge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Recovery Code", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		mockRefreshTokenRepo.On("Create", ctx, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, _ := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("UseRecoveryCode", ctx, user.ID, mock.AnythingOfType("string")).Return(true, nil)
+		mockUserRepo.On("ConsumeMFAChallenge", ctx, user.ID, user.MFA.ChallengeID).Return(true, nil)
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, RecoveryCode: "ABCDE-FGHIJ"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Token)
+		mockUserRepo.AssertNotCalled(t, "AdvanceMFAStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Wrong Code Counts As Failed Login", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		loginAttempts := allowLogins()
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, loginAttempts, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, _ := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("UseRecoveryCode", ctx, user.ID, mock.AnythingOfType("string")).Return(false, nil)
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, RecoveryCode: "WRONG-GUESS", IP: "203.0.113.7"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrInvalidMFACode, err)
+		loginAttempts.AssertCalled(t, "RecordFailure", ctx, "email:john@example.com", mock.Anything, mock.Anything)
+		loginAttempts.AssertCalled(t, "RecordFailure", ctx, "ip:203.0.113.7", mock.Anything, mock.Anything)
+		loginAttempts.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "ConsumeMFAChallenge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Last Allowed Wrong Code Locks User", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		loginAttempts := &mocks.MockLoginAttemptRepository{}
+		loginAttempts.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		loginAttempts.On("RecordFailure", ctx, "email:john@example.com", mock.Anything, mock.Anything).Return(&models.LoginAttempt{Failures: services.LoginMaxFailures}, nil)
+		loginAttempts.On("Lock", ctx, "email:john@example.com", mock.Anything).Return(nil)
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, loginAttempts, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, _ := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("UseRecoveryCode", ctx, user.ID, mock.AnythingOfType("string")).Return(false, nil)
+		mockUserRepo.On("Lock", ctx, user.ID, mock.MatchedBy(func(event models.LockoutEvent) bool {
+			return event.Action == models.LockoutActionLocked
+		})).Return(nil)
+
+		_, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, RecoveryCode: "WRONG-GUESS"})
+
+		assert.Equal(t, services.ErrInvalidMFACode, err)
+		mockUserRepo.AssertExpectations(t)
+		loginAttempts.AssertExpectations(t)
+	})
+
+	t.Run("Locked User", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, code := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+		lockedUntil := time.Now().Add(10 * time.Minute)
+		user.LockedUntil = &lockedUntil
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, Code: code})
+
+		assert.Nil(t, response)
+		var throttled *services.LoginThrottledError
+		assert.ErrorAs(t, err, &throttled)
+		mockUserRepo.AssertNotCalled(t, "AdvanceMFAStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Throttled Email", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		lockedUntil := time.Now().Add(10 * time.Minute)
+		loginAttempts := &mocks.MockLoginAttemptRepository{}
+		loginAttempts.On("Get", ctx, "email:john@example.com", mock.Anything).Return(&models.LoginAttempt{
+			Failures:      services.LoginMaxFailures,
+			LastFailureAt: time.Now(),
+			LockedUntil:   &lockedUntil,
+		}, nil)
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, loginAttempts, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, code := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		_, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, Code: code})
+
+		var throttled *services.LoginThrottledError
+		assert.ErrorAs(t, err, &throttled)
+		mockUserRepo.AssertNotCalled(t, "AdvanceMFAStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Token Already Used", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, code := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		// The challenge was cleared by the login it completed
+		user.MFA.ChallengeID = ""
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, Code: code})
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrInvalidMFAToken, err)
+		mockUserRepo.AssertNotCalled(t, "AdvanceMFAStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Token Used Concurrently", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, _ := mfaUser(t)
+		mfaToken := mfaChallenge(t, user)
+
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("UseRecoveryCode", ctx, user.ID, mock.AnythingOfType("string")).Return(true, nil)
+		mockUserRepo.On("ConsumeMFAChallenge", ctx, user.ID, user.MFA.ChallengeID).Return(false, nil)
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: mfaToken, RecoveryCode: "ABCDE-FGHIJ"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrInvalidMFAToken, err)
+		mockRefreshTokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Access Token Instead Of MFA Token", func(t *testing.T) {
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		accessToken, _ := jwt.GenerateToken(primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex(), "customer")
+
+		response, err := authService.VerifyMFA(ctx, dtos.MFALoginRequest{MFAToken: accessToken, Code: "123456"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrInvalidMFAToken, err)
+	})
+}
+
+func TestAuthService_EnrollAndActivateMFA(t *testing.T) {
+	ctx := context.Background()
+	principal := &models.Principal{UserID: primitive.NewObjectID(), Email: "john@example.com"}
+
+	t.Run("Enroll", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		mockUserRepo.On("SetMFASecret", ctx, principal.UserID, mock.AnythingOfType("string")).Return(true, nil)
+
+		response, err := authService.EnrollMFA(ctx, principal)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Secret)
+		assert.True(t, strings.HasPrefix(response.OTPAuthURI, "otpauth://totp/"))
+		assert.Contains(t, response.OTPAuthURI, "secret="+response.Secret)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Enroll When Enabled", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		mockUserRepo.On("SetMFASecret", ctx, principal.UserID, mock.AnythingOfType("string")).Return(false, nil)
+
+		response, err := authService.EnrollMFA(ctx, principal)
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrMFAAlreadyEnabled, err)
+	})
+
+	t.Run("Activate", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, code := mfaUser(t)
+		user.ID = principal.UserID
+		user.MFA.Enabled = false
+
+		mockUserRepo.On("FindByID", ctx, principal.UserID).Return(user, nil)
+		mockUserRepo.On("EnableMFA", ctx, principal.UserID, mock.AnythingOfType("int64"), mock.MatchedBy(func(hashes []string) bool {
+			return len(hashes) == services.RecoveryCodeCount
+		})).Return(true, nil)
+
+		response, err := authService.ActivateMFA(ctx, principal, dtos.MFAActivateRequest{Code: code})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.RecoveryCodes, services.RecoveryCodeCount)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Activate With Wrong Code", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		user, code := mfaUser(t)
+		user.MFA.Enabled = false
+		wrong := "000000"
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		mockUserRepo.On("FindByID", ctx, principal.UserID).Return(user, nil)
+
+		response, err := authService.ActivateMFA(ctx, principal, dtos.MFAActivateRequest{Code: wrong})
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrInvalidMFACode, err)
+		mockUserRepo.AssertNotCalled(t, "EnableMFA", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not Enrolled", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+
+		mockUserRepo.On("FindByID", ctx, principal.UserID).Return(&models.User{ID: principal.UserID}, nil)
+
+		response, err := authService.ActivateMFA(ctx, principal, dtos.MFAActivateRequest{Code: "123456"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, services.ErrMFANotEnrolled, err)
+	})
+}
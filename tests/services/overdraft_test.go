@@ -0,0 +1,50 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalance_Overdraft(t *testing.T) {
+	t.Run("No facility", func(t *testing.T) {
+		balance := &models.Balance{Amount: 5000, Held: 1000, Currency: "USD"}
+
+		assert.Equal(t, money.Amount(4000), balance.Available())
+		assert.Equal(t, money.Amount(0), balance.OverdraftUsed())
+		interest, fee := balance.DailyOverdraftCharge()
+		assert.Zero(t, interest)
+		assert.Zero(t, fee)
+	})
+
+	t.Run("In credit", func(t *testing.T) {
+		balance := &models.Balance{Amount: 5000, Currency: "USD", Overdraft: &models.Overdraft{Limit: 10000, InterestRateBps: 1500, DailyFee: 100}}
+
+		assert.Equal(t, money.Amount(15000), balance.Available())
+		assert.Equal(t, money.Amount(0), balance.OverdraftUsed())
+		interest, fee := balance.DailyOverdraftCharge()
+		assert.Zero(t, interest, "nothing is charged while the balance is positive")
+		assert.Zero(t, fee)
+	})
+
+	t.Run("Overdrawn", func(t *testing.T) {
+		balance := &models.Balance{Amount: -7300000, Held: 200000, Currency: "USD", Overdraft: &models.Overdraft{Limit: 10000000, InterestRateBps: 1000, DailyFee: 250}}
+
+		assert.Equal(t, money.Amount(2500000), balance.Available())
+		assert.Equal(t, money.Amount(7300000), balance.OverdraftUsed())
+		interest, fee := balance.DailyOverdraftCharge()
+		// 73,000.00 at 10% a year is 20.00 a day
+		assert.Equal(t, money.Amount(2000), interest)
+		assert.Equal(t, money.Amount(250), fee)
+	})
+
+	t.Run("Interest rounds down", func(t *testing.T) {
+		balance := &models.Balance{Amount: -100, Currency: "USD", Overdraft: &models.Overdraft{Limit: 1000, InterestRateBps: 2000}}
+
+		interest, fee := balance.DailyOverdraftCharge()
+		assert.Zero(t, interest)
+		assert.Zero(t, fee)
+	})
+}
@@ -0,0 +1,268 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/sms"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// profileUser returns a stored user whose password is "password123"
+func profileUser(t *testing.T) *models.User {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	return &models.User{
+		ID:            primitive.NewObjectID(),
+		Name:          "John Doe",
+		Email:         "john@example.com",
+		EmailVerified: true,
+		PhoneNumber:   "+1234567890",
+		PhoneVerified: true,
+		Password:      string(hashedPassword),
+		UpdatedAt:     time.Now().Truncate(time.Millisecond),
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestAuthService_GetProfile(t *testing.T) {
+	ctx := context.Background()
+	user := profileUser(t)
+
+	t.Run("Own User", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		profile, err := authService.GetProfile(ctx, &models.Principal{UserID: user.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, user, profile)
+	})
+
+	t.Run("API Key Without Read Scope", func(t *testing.T) {
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		keyID := primitive.NewObjectID()
+
+		_, err := authService.GetProfile(ctx, &models.Principal{UserID: user.ID, APIKeyID: &keyID, Scopes: []models.Permission{models.PermissionFundsDeposit}})
+
+		assert.Equal(t, utils.ErrAPIKeyScope, err)
+	})
+}
+
+func TestAuthService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Name", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("Update", ctx, mock.MatchedBy(func(updated *models.User) bool {
+			return updated.Name == "Jane Doe" && updated.EmailVerified
+		})).Return(true, nil)
+
+		profile, err := authService.UpdateProfile(ctx, &models.Principal{UserID: user.ID}, dtos.UpdateProfileRequest{
+			Name:      stringPtr("Jane Doe"),
+			UpdatedAt: &user.UpdatedAt,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane Doe", profile.Name)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Stale Version", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		stale := user.UpdatedAt.Add(-time.Minute)
+
+		_, err := authService.UpdateProfile(ctx, &models.Principal{UserID: user.ID}, dtos.UpdateProfileRequest{
+			Name:      stringPtr("Jane Doe"),
+			UpdatedAt: &stale,
+		})
+
+		assert.Equal(t, services.ErrUserModified, err)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent Change", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("Update", ctx, mock.Anything).Return(false, nil)
+
+		_, err := authService.UpdateProfile(ctx, &models.Principal{UserID: user.ID}, dtos.UpdateProfileRequest{
+			Name:      stringPtr("Jane Doe"),
+			UpdatedAt: &user.UpdatedAt,
+		})
+
+		assert.Equal(t, services.ErrUserModified, err)
+	})
+
+	t.Run("Email Requires Reverification", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		mockMailer := &mocks.MockMailer{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, allowLogins(), recordAudit(), mockMailer, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("FindByEmail", ctx, "jane@example.com").Return(nil, nil)
+		mockUserRepo.On("Update", ctx, mock.MatchedBy(func(updated *models.User) bool {
+			return updated.Email == "jane@example.com" && !updated.EmailVerified && updated.PhoneVerified
+		})).Return(true, nil)
+		mockActionTokenRepo.On("InvalidateAll", ctx, user.ID, models.ActionPasswordReset).Return(nil)
+		mockActionTokenRepo.On("InvalidateAll", ctx, user.ID, models.ActionEmailVerification).Return(nil)
+		mockActionTokenRepo.On("Create", ctx, mock.MatchedBy(func(token *models.ActionToken) bool {
+			return token.Purpose == models.ActionEmailVerification && token.Email == "jane@example.com"
+		})).Return(nil)
+		mockMailer.On("Send", ctx, mock.MatchedBy(func(message mailer.Message) bool {
+			return message.To == "jane@example.com" && strings.Contains(message.Body, "/verify-email?token=")
+		})).Return(nil)
+		mockMailer.On("Send", ctx, mock.MatchedBy(func(message mailer.Message) bool {
+			return message.To == "john@example.com" && strings.Contains(message.Body, "jane@example.com")
+		})).Return(nil)
+
+		profile, err := authService.UpdateProfile(ctx, &models.Principal{UserID: user.ID}, dtos.UpdateProfileRequest{
+			Email:           stringPtr("jane@example.com"),
+			CurrentPassword: "password123",
+			UpdatedAt:       &user.UpdatedAt,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, profile.EmailVerified)
+		mockActionTokenRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Phone Requires Reverification", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		mockTexter := &mocks.MockSMSSender{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, allowLogins(), recordAudit(), &mocks.MockMailer{}, mockTexter)
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("Update", ctx, mock.MatchedBy(func(updated *models.User) bool {
+			return updated.PhoneNumber == "+1987654321" && !updated.PhoneVerified && updated.EmailVerified
+		})).Return(true, nil)
+		mockActionTokenRepo.On("InvalidateAll", ctx, user.ID, models.ActionPhoneVerification).Return(nil)
+		mockActionTokenRepo.On("Create", ctx, mock.MatchedBy(func(token *models.ActionToken) bool {
+			return token.Purpose == models.ActionPhoneVerification && token.Phone == "+1987654321"
+		})).Return(nil)
+		mockTexter.On("Send", ctx, mock.MatchedBy(func(message sms.Message) bool {
+			return message.To == "+1987654321" && strings.Contains(message.Body, "/verify-phone?token=")
+		})).Return(nil)
+
+		profile, err := authService.UpdateProfile(ctx, &models.Principal{UserID: user.ID}, dtos.UpdateProfileRequest{
+			PhoneNumber:     stringPtr("+1987654321"),
+			CurrentPassword: "password123",
+			UpdatedAt:       &user.UpdatedAt,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, profile.PhoneVerified)
+		mockTexter.AssertExpectations(t)
+	})
+
+	t.Run("Email Change With Wrong Password", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		_, err := authService.UpdateProfile(ctx, &models.Principal{UserID: user.ID}, dtos.UpdateProfileRequest{
+			Email:           stringPtr("jane@example.com"),
+			CurrentPassword: "wrong-password",
+			UpdatedAt:       &user.UpdatedAt,
+		})
+
+		assert.Equal(t, services.ErrWrongPassword, err)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Email Taken", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("FindByEmail", ctx, "jane@example.com").Return(&models.User{ID: primitive.NewObjectID()}, nil)
+
+		_, err := authService.UpdateProfile(ctx, &models.Principal{UserID: user.ID}, dtos.UpdateProfileRequest{
+			Email:           stringPtr("jane@example.com"),
+			CurrentPassword: "password123",
+			UpdatedAt:       &user.UpdatedAt,
+		})
+
+		assert.Equal(t, services.ErrEmailExists, err)
+	})
+
+	t.Run("API Key", func(t *testing.T) {
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		keyID := primitive.NewObjectID()
+		now := time.Now()
+
+		_, err := authService.UpdateProfile(ctx, &models.Principal{UserID: primitive.NewObjectID(), APIKeyID: &keyID}, dtos.UpdateProfileRequest{
+			Name:      stringPtr("Jane Doe"),
+			UpdatedAt: &now,
+		})
+
+		assert.Equal(t, services.ErrProfileManagement, err)
+	})
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Revokes Other Sessions", func(t *testing.T) {
+		user := profileUser(t)
+		principal := &models.Principal{UserID: user.ID, SessionID: primitive.NewObjectID()}
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, mockActionTokenRepo, allowLogins(), recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+		mockUserRepo.On("Update", ctx, mock.MatchedBy(func(updated *models.User) bool {
+			return bcrypt.CompareHashAndPassword([]byte(updated.Password), []byte("new-password123")) == nil
+		})).Return(true, nil)
+		mockRefreshTokenRepo.On("RevokeOtherSessions", ctx, user.ID, principal.SessionID).Return(nil)
+		mockActionTokenRepo.On("InvalidateAll", ctx, user.ID, models.ActionPasswordReset).Return(nil)
+
+		err := authService.ChangePassword(ctx, principal, dtos.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "new-password123"})
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Current Password", func(t *testing.T) {
+		user := profileUser(t)
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockLoginAttemptRepo := allowLogins()
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, mockLoginAttemptRepo, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		mockUserRepo.On("FindByID", ctx, user.ID).Return(user, nil)
+
+		err := authService.ChangePassword(ctx, &models.Principal{UserID: user.ID}, dtos.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "new-password123"})
+
+		assert.Equal(t, services.ErrWrongPassword, err)
+		mockLoginAttemptRepo.AssertCalled(t, "RecordFailure", ctx, "email:john@example.com", mock.Anything, mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
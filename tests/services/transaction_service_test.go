@@ -2,12 +2,19 @@ package services_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"math"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
 	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/money"
 	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
 	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
 	"github.com/stretchr/testify/assert"
@@ -16,54 +23,72 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// Create a custom service for testing that uses mocked repositories
+// testTransactionService is a transaction service built on mocked repositories and a transaction
+// runner that runs its work inline
 type testTransactionService struct {
-	*services.TransactionService
-	mockDB              *mocks.MockMongoDatabase
-	mockClient          *mocks.MockMongoClient
-	mockSession         *mocks.MockMongoSession
+	services.TransactionService
+	txRunner            *mocks.MockTxRunner
 	mockTransactionRepo *mocks.MockTransactionRepository
 	mockBalanceRepo     *mocks.MockBalanceRepository
+	mockIdempotencyRepo *mocks.MockIdempotencyRepository
+	mockLedgerRepo      *mocks.MockLedgerRepository
+	mockAuditRepo       *mocks.MockAuditEventRepository
+	mockAccountRepo     *mocks.MockAccountRepository
+	mockUserRepo        *mocks.MockUserRepository
+	mockLimitRepo       *mocks.MockLimitPolicyRepository
 }
 
 func setupTestService() *testTransactionService {
-	mockDB := new(mocks.MockMongoDatabase)
-	mockClient := new(mocks.MockMongoClient)
-	mockSession := new(mocks.MockMongoSession)
-	mockTransactionRepo := new(mocks.MockTransactionRepository)
-	mockBalanceRepo := new(mocks.MockBalanceRepository)
-
-	// Setup the chain of mocks
-	mockDB.On("Client").Return(mockClient)
-
-	// Create the service with the mocked database
-	service := services.NewTransactionService(mockDB)
-
-	// Replace the repositories with mocks
 	testService := &testTransactionService{
-		TransactionService: service,
-		mockDB:             mockDB,
-		mockClient:         mockClient,
-		mockSession:        mockSession,
-		mockTransactionRepo: mockTransactionRepo,
-		mockBalanceRepo:    mockBalanceRepo,
+		txRunner:            &mocks.MockTxRunner{},
+		mockTransactionRepo: new(mocks.MockTransactionRepository),
+		mockBalanceRepo:     new(mocks.MockBalanceRepository),
+		mockIdempotencyRepo: new(mocks.MockIdempotencyRepository),
+		mockLedgerRepo:      new(mocks.MockLedgerRepository),
+		mockAuditRepo:       new(mocks.MockAuditEventRepository),
+		mockAccountRepo:     new(mocks.MockAccountRepository),
+		mockUserRepo:        new(mocks.MockUserRepository),
+		mockLimitRepo:       new(mocks.MockLimitPolicyRepository),
 	}
 
-	// Use reflection to set the mocked repositories
-	// This is a bit of a hack, but necessary for testing with mocks
-	serviceValue := reflect.ValueOf(service).Elem()
-	
-	transactionRepoField := serviceValue.FieldByName("transactionRepo")
-	transactionRepoField = reflect.NewAt(transactionRepoField.Type(), unsafe.Pointer(transactionRepoField.UnsafeAddr())).Elem()
-	transactionRepoField.Set(reflect.ValueOf(mockTransactionRepo))
-	
-	balanceRepoField := serviceValue.FieldByName("balanceRepo")
-	balanceRepoField = reflect.NewAt(balanceRepoField.Type(), unsafe.Pointer(balanceRepoField.UnsafeAddr())).Elem()
-	balanceRepoField.Set(reflect.ValueOf(mockBalanceRepo))
+	testService.TransactionService = services.NewTransactionService(
+		testService.txRunner,
+		testService.mockTransactionRepo,
+		testService.mockBalanceRepo,
+		testService.mockIdempotencyRepo,
+		testService.mockLedgerRepo,
+		testService.mockAuditRepo,
+		testService.mockAccountRepo,
+		testService.mockUserRepo,
+		testService.mockLimitRepo,
+	)
 
 	return testService
 }
 
+// allowMovement lets money move on accountIDs: each account has an active owner, no limit policy applies
+// and audit events are stored. Balance snapshots read no balance; expect specific GetBalance calls before
+// calling it.
+func (s *testTransactionService) allowMovement(accountIDs ...primitive.ObjectID) {
+	for _, accountID := range accountIDs {
+		ownerID := primitive.NewObjectID()
+		s.mockAccountRepo.On("FindByID", mock.Anything, accountID).Return(&models.Account{
+			ID:      accountID,
+			Holders: []models.AccountHolder{{UserID: ownerID, Role: models.HolderRoleOwner}},
+		}, nil).Maybe()
+		s.mockUserRepo.On("FindByID", mock.Anything, ownerID).Return(&models.User{ID: ownerID, Status: models.UserStatusActive}, nil).Maybe()
+	}
+	s.mockLimitRepo.On("Find", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	s.mockBalanceRepo.On("GetBalance", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	s.mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+// depositHash reproduces the request fingerprint the service stores with an idempotency key
+func (s *testTransactionService) depositHash(accountID primitive.ObjectID, amount money.Amount, currency string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%s", services.IdempotencyOperationDeposit, accountID.Hex(), amount, currency)))
+	return hex.EncodeToString(sum[:])
+}
+
 func TestTransactionService_Deposit(t *testing.T) {
 	ctx := context.Background()
 
@@ -71,26 +96,24 @@ func TestTransactionService_Deposit(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 100.0
+		amount := money.Amount(10000)
 		currency := "USD"
 		transactionID := primitive.NewObjectID()
 
-		// Mock session setup
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(nil)
-		testService.mockSession.On("CommitTransaction", mock.Anything).Return(nil)
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock an active account with no limits
+		testService.allowMovement(accountID)
 
 		// Mock repository calls
 		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, amount, currency).Return(nil)
-		
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+
 		expectedCreateDTO := &dtos.CreateTransactionDTO{
 			AccountID: accountID,
 			Amount:    amount,
 			Currency:  currency,
 			Type:      string(models.TransactionTypeCredit),
 		}
-		
+
 		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
 			return dto.AccountID == expectedCreateDTO.AccountID &&
 				dto.Amount == expectedCreateDTO.Amount &&
@@ -105,13 +128,11 @@ func TestTransactionService_Deposit(t *testing.T) {
 		}, nil)
 
 		// Execute
-		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, transactionID.Hex(), result)
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
 		testService.mockBalanceRepo.AssertExpectations(t)
 		testService.mockTransactionRepo.AssertExpectations(t)
 	})
@@ -120,88 +141,73 @@ func TestTransactionService_Deposit(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		
+
 		// Test with zero amount
-		result, err := testService.TransactionService.Deposit(ctx, accountID, 0, "USD")
+		result, err := testService.TransactionService.Deposit(ctx, accountID, 0, "USD", "")
 		assert.Error(t, err)
 		assert.Equal(t, utils.ErrInvalidAmount, err)
 		assert.Empty(t, result)
-		
+
 		// Test with negative amount
-		result, err = testService.TransactionService.Deposit(ctx, accountID, -10, "USD")
+		result, err = testService.TransactionService.Deposit(ctx, accountID, -10, "USD", "")
 		assert.Error(t, err)
 		assert.Equal(t, utils.ErrInvalidAmount, err)
 		assert.Empty(t, result)
 	})
 
-	t.Run("Session Start Error", func(t *testing.T) {
+	t.Run("Disabled Currency", func(t *testing.T) {
 		// Setup
 		testService := setupTestService()
-		accountID := primitive.NewObjectID()
-		amount := 100.0
-		currency := "USD"
-
-		// Mock session error
-		testService.mockClient.On("StartSession").Return(nil, errors.New("session error"))
 
 		// Execute
-		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Deposit(ctx, primitive.NewObjectID(), 1000, "HRK", "")
 
 		// Assert
-		assert.Error(t, err)
+		assert.Equal(t, utils.ErrCurrencyDisabled, err)
 		assert.Empty(t, result)
-		assert.Equal(t, "session error", err.Error())
-		testService.mockClient.AssertExpectations(t)
+		assert.Zero(t, testService.txRunner.Runs)
 	})
 
 	t.Run("Transaction Start Error", func(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 100.0
+		amount := money.Amount(10000)
 		currency := "USD"
 
-		// Mock session setup with transaction error
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(errors.New("transaction error"))
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock transaction error
+		testService.txRunner.StartErr = errors.New("transaction error")
 
 		// Execute
-		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		assert.Equal(t, "transaction error", err.Error())
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("Update Balance Error", func(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 100.0
+		amount := money.Amount(10000)
 		currency := "USD"
 
-		// Mock session setup
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(nil)
-		testService.mockSession.On("AbortTransaction", mock.Anything).Return(nil)
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock an active account with no limits
+		testService.allowMovement(accountID)
 
 		// Mock repository error
 		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, amount, currency).Return(errors.New("balance update error"))
 
 		// Execute
-		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		assert.Equal(t, "balance update error", err.Error())
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
 		testService.mockBalanceRepo.AssertExpectations(t)
 	})
 
@@ -209,28 +215,24 @@ func TestTransactionService_Deposit(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 100.0
+		amount := money.Amount(10000)
 		currency := "USD"
 
-		// Mock session setup
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(nil)
-		testService.mockSession.On("AbortTransaction", mock.Anything).Return(nil)
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock an active account with no limits
+		testService.allowMovement(accountID)
 
 		// Mock repository calls
 		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, amount, currency).Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
 		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(nil, errors.New("transaction creation error"))
 
 		// Execute
-		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		assert.Equal(t, "transaction creation error", err.Error())
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
 		testService.mockBalanceRepo.AssertExpectations(t)
 		testService.mockTransactionRepo.AssertExpectations(t)
 	})
@@ -239,19 +241,17 @@ func TestTransactionService_Deposit(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 100.0
+		amount := money.Amount(10000)
 		currency := "USD"
 		transactionID := primitive.NewObjectID()
 
-		// Mock session setup with commit error
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(nil)
-		testService.mockSession.On("CommitTransaction", mock.Anything).Return(errors.New("commit error"))
-		testService.mockSession.On("AbortTransaction", mock.Anything).Return(nil)
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock commit error
+		testService.allowMovement(accountID)
+		testService.txRunner.CommitErr = errors.New("commit error")
 
 		// Mock repository calls
 		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, amount, currency).Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
 		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(&models.Transaction{
 			ID:        transactionID,
 			AccountID: accountID,
@@ -261,17 +261,97 @@ func TestTransactionService_Deposit(t *testing.T) {
 		}, nil)
 
 		// Execute
-		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		assert.Equal(t, "commit error", err.Error())
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
 		testService.mockBalanceRepo.AssertExpectations(t)
 		testService.mockTransactionRepo.AssertExpectations(t)
 	})
+
+	t.Run("Idempotent Replay", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		amount := money.Amount(10000)
+		currency := "USD"
+		transactionID := primitive.NewObjectID()
+
+		// First call stored the hash of the same request
+		first := &models.IdempotencyKey{AccountID: accountID, Key: "retry-1", TransactionID: transactionID}
+		testService.mockIdempotencyRepo.On("Find", ctx, accountID, "retry-1").Return(first, nil).Once()
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "retry-1")
+		assert.Equal(t, utils.ErrIdempotencyKeyReused, err)
+		assert.Empty(t, result)
+
+		// Execute with a matching fingerprint
+		first.RequestHash = testService.depositHash(accountID, amount, currency)
+		testService.mockIdempotencyRepo.On("Find", ctx, accountID, "retry-1").Return(first, nil).Once()
+		result, err = testService.TransactionService.Deposit(ctx, accountID, amount, currency, "retry-1")
+
+		// Assert no money moved and the original ID is returned
+		assert.NoError(t, err)
+		assert.Equal(t, transactionID.Hex(), result)
+		assert.Equal(t, 0, testService.txRunner.Runs)
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent Duplicate Returns Original Transaction", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		amount := money.Amount(10000)
+		currency := "USD"
+		transactionID := primitive.NewObjectID()
+
+		// Another request committed the same key after this one checked for it
+		duplicate := mongo.WriteException{WriteErrors: mongo.WriteErrors{{
+			Code:    11000,
+			Message: "E11000 duplicate key error collection: axis.idempotency_keys index: " + models.IdempotencyKeyIndex,
+		}}}
+		committed := &models.IdempotencyKey{AccountID: accountID, Key: "retry-1", RequestHash: testService.depositHash(accountID, amount, currency), TransactionID: transactionID}
+		testService.mockIdempotencyRepo.On("Find", mock.Anything, accountID, "retry-1").Return(nil, nil).Once()
+		testService.mockIdempotencyRepo.On("Create", mock.Anything, mock.Anything).Return(duplicate)
+		testService.mockIdempotencyRepo.On("Find", mock.Anything, accountID, "retry-1").Return(committed, nil).Once()
+
+		// Execute
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "retry-1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, transactionID.Hex(), result)
+		testService.mockIdempotencyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unrelated Write Error Is Not A Key Conflict", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		amount := money.Amount(10000)
+		currency := "USD"
+		testService.allowMovement(accountID)
+
+		// A duplicate on another index after the key was claimed
+		duplicate := mongo.WriteException{WriteErrors: mongo.WriteErrors{{
+			Code:    11000,
+			Message: "E11000 duplicate key error collection: axis.transactions index: account_id_1_chain_sequence_1",
+		}}}
+		testService.mockIdempotencyRepo.On("Find", mock.Anything, accountID, "retry-1").Return(nil, nil).Once()
+		testService.mockIdempotencyRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, amount, currency).Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(nil, duplicate)
+
+		// Execute
+		result, err := testService.TransactionService.Deposit(ctx, accountID, amount, currency, "retry-1")
+
+		// Assert the error is returned as is rather than reported as a key in progress
+		assert.Empty(t, result)
+		assert.Equal(t, duplicate, err)
+		testService.mockIdempotencyRepo.AssertNumberOfCalls(t, "Find", 1)
+	})
 }
 
 func TestTransactionService_Withdraw(t *testing.T) {
@@ -281,26 +361,24 @@ func TestTransactionService_Withdraw(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 50.0
+		amount := money.Amount(5000)
 		currency := "USD"
 		transactionID := primitive.NewObjectID()
 
-		// Mock session setup
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(nil)
-		testService.mockSession.On("CommitTransaction", mock.Anything).Return(nil)
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock an active account with no limits
+		testService.allowMovement(accountID)
 
 		// Mock repository calls
 		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, accountID, amount, currency).Return(nil)
-		
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+
 		expectedCreateDTO := &dtos.CreateTransactionDTO{
 			AccountID: accountID,
 			Amount:    amount,
 			Currency:  currency,
 			Type:      string(models.TransactionTypeDebit),
 		}
-		
+
 		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
 			return dto.AccountID == expectedCreateDTO.AccountID &&
 				dto.Amount == expectedCreateDTO.Amount &&
@@ -315,13 +393,11 @@ func TestTransactionService_Withdraw(t *testing.T) {
 		}, nil)
 
 		// Execute
-		result, err := testService.TransactionService.Withdraw(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Withdraw(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, transactionID.Hex(), result)
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
 		testService.mockBalanceRepo.AssertExpectations(t)
 		testService.mockTransactionRepo.AssertExpectations(t)
 	})
@@ -330,46 +406,59 @@ func TestTransactionService_Withdraw(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		
+
 		// Test with zero amount
-		result, err := testService.TransactionService.Withdraw(ctx, accountID, 0, "USD")
+		result, err := testService.TransactionService.Withdraw(ctx, accountID, 0, "USD", "")
 		assert.Error(t, err)
 		assert.Equal(t, utils.ErrInvalidAmount, err)
 		assert.Empty(t, result)
-		
+
 		// Test with negative amount
-		result, err = testService.TransactionService.Withdraw(ctx, accountID, -10, "USD")
+		result, err = testService.TransactionService.Withdraw(ctx, accountID, -10, "USD", "")
 		assert.Error(t, err)
 		assert.Equal(t, utils.ErrInvalidAmount, err)
 		assert.Empty(t, result)
 	})
 
+	t.Run("Disabled Currency", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		testService.allowMovement(accountID)
+
+		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, accountID, money.Amount(1000), "HRK").Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Withdraw(ctx, accountID, 1000, "HRK", "")
+
+		// Assert
+		assert.NoError(t, err, "funds in a disabled currency can still be taken out")
+		assert.NotEmpty(t, result)
+	})
+
 	t.Run("Insufficient Funds", func(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 1000.0
+		amount := money.Amount(100000)
 		currency := "USD"
 
-		// Mock session setup
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(nil)
-		testService.mockSession.On("AbortTransaction", mock.Anything).Return(nil)
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock an active account with no limits
+		testService.allowMovement(accountID)
 
 		// Mock insufficient funds error
 		insufficientFundsErr := utils.NewError(http.StatusBadRequest, "insufficient funds")
 		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, accountID, amount, currency).Return(insufficientFundsErr)
 
 		// Execute
-		result, err := testService.TransactionService.Withdraw(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Withdraw(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		assert.Equal(t, insufficientFundsErr, err)
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
 		testService.mockBalanceRepo.AssertExpectations(t)
 	})
 
@@ -377,33 +466,88 @@ func TestTransactionService_Withdraw(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		amount := 50.0
+		amount := money.Amount(5000)
 		currency := "USD"
 
-		// Mock session setup
-		testService.mockClient.On("StartSession").Return(testService.mockSession, nil)
-		testService.mockSession.On("StartTransaction").Return(nil)
-		testService.mockSession.On("AbortTransaction", mock.Anything).Return(nil)
-		testService.mockSession.On("EndSession", mock.Anything).Return()
+		// Mock an active account with no limits
+		testService.allowMovement(accountID)
 
 		// Mock repository calls
 		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, accountID, amount, currency).Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
 		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(nil, errors.New("transaction creation error"))
 
 		// Execute
-		result, err := testService.TransactionService.Withdraw(ctx, accountID, amount, currency)
+		result, err := testService.TransactionService.Withdraw(ctx, accountID, amount, currency, "")
 
 		// Assert
 		assert.Error(t, err)
 		assert.Empty(t, result)
 		assert.Equal(t, "transaction creation error", err.Error())
-		testService.mockClient.AssertExpectations(t)
-		testService.mockSession.AssertExpectations(t)
 		testService.mockBalanceRepo.AssertExpectations(t)
 		testService.mockTransactionRepo.AssertExpectations(t)
 	})
 }
 
+func TestTransactionService_HolderStatus(t *testing.T) {
+	ctx := context.Background()
+
+	// withdraw withdraws from an account held by an owner and a joint holder with the given statuses
+	withdraw := func(ownerStatus, jointStatus models.UserStatus) (*testTransactionService, error) {
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		ownerID := primitive.NewObjectID()
+		jointID := primitive.NewObjectID()
+
+		testService.mockAccountRepo.On("FindByID", mock.Anything, accountID).Return(&models.Account{
+			ID: accountID,
+			Holders: []models.AccountHolder{
+				{UserID: ownerID, Role: models.HolderRoleOwner},
+				{UserID: jointID, Role: models.HolderRoleJoint, Permissions: []models.Permission{models.PermissionFundsWithdraw}},
+			},
+		}, nil)
+		testService.mockUserRepo.On("FindByID", mock.Anything, ownerID).Return(&models.User{ID: ownerID, Status: ownerStatus}, nil)
+		testService.mockUserRepo.On("FindByID", mock.Anything, jointID).Return(&models.User{ID: jointID, Status: jointStatus}, nil).Maybe()
+		testService.allowMovement()
+		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, accountID, money.Amount(500), "USD").Return(nil).Maybe()
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil).Maybe()
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil).Maybe()
+
+		_, err := testService.TransactionService.Withdraw(ctx, accountID, 500, "USD", "")
+		return testService, err
+	}
+
+	t.Run("Blocked Owner Freezes Account", func(t *testing.T) {
+		testService, err := withdraw(models.UserStatusBlocked, models.UserStatusActive)
+
+		assert.Equal(t, utils.ErrAccountFrozen, err)
+		testService.mockBalanceRepo.AssertNotCalled(t, "CheckAndDeductBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deactivated Owner Freezes Account", func(t *testing.T) {
+		_, err := withdraw(models.UserStatusInactive, models.UserStatusActive)
+
+		assert.Equal(t, utils.ErrAccountFrozen, err)
+	})
+
+	t.Run("Blocked Joint Holder Leaves Account Open", func(t *testing.T) {
+		testService, err := withdraw(models.UserStatusActive, models.UserStatusBlocked)
+
+		assert.NoError(t, err)
+		testService.mockBalanceRepo.AssertCalled(t, "CheckAndDeductBalance", mock.Anything, mock.Anything, money.Amount(500), "USD")
+	})
+
+	t.Run("Account Without Owner", func(t *testing.T) {
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		testService.mockAccountRepo.On("FindByID", mock.Anything, accountID).Return(&models.Account{ID: accountID}, nil)
+
+		_, err := testService.TransactionService.Deposit(ctx, accountID, 500, "USD", "")
+
+		assert.Equal(t, utils.ErrAccountFrozen, err)
+	})
+}
+
 func TestTransactionService_GetBalances(t *testing.T) {
 	ctx := context.Background()
 
@@ -411,12 +555,12 @@ func TestTransactionService_GetBalances(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		
+
 		balances := []models.Balance{
-			{AccountID: accountID, Currency: "USD", Amount: 100.0},
-			{AccountID: accountID, Currency: "EUR", Amount: 50.0},
+			{AccountID: accountID, Currency: "USD", Amount: 10000, Held: 2500},
+			{AccountID: accountID, Currency: "EUR", Amount: 5000},
 		}
-		
+
 		testService.mockBalanceRepo.On("GetBalances", ctx, accountID).Return(balances, nil)
 
 		// Execute
@@ -427,9 +571,10 @@ func TestTransactionService_GetBalances(t *testing.T) {
 		assert.NotNil(t, result)
 		assert.Equal(t, 2, len(result.Balances))
 		assert.Equal(t, "USD", result.Balances[0].Currency)
-		assert.Equal(t, 100.0, result.Balances[0].Amount)
+		assert.Equal(t, money.Decimal("100.00"), result.Balances[0].Current)
+		assert.Equal(t, money.Decimal("75.00"), result.Balances[0].Available)
 		assert.Equal(t, "EUR", result.Balances[1].Currency)
-		assert.Equal(t, 50.0, result.Balances[1].Amount)
+		assert.Equal(t, money.Decimal("50.00"), result.Balances[1].Current)
 		testService.mockBalanceRepo.AssertExpectations(t)
 	})
 
@@ -437,7 +582,7 @@ func TestTransactionService_GetBalances(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		
+
 		var emptyBalances []models.Balance
 		testService.mockBalanceRepo.On("GetBalances", ctx, accountID).Return(emptyBalances, nil)
 
@@ -455,7 +600,7 @@ func TestTransactionService_GetBalances(t *testing.T) {
 		// Setup
 		testService := setupTestService()
 		accountID := primitive.NewObjectID()
-		
+
 		testService.mockBalanceRepo.On("GetBalances", ctx, accountID).Return(nil, errors.New("database error"))
 
 		// Execute
@@ -468,3 +613,718 @@ func TestTransactionService_GetBalances(t *testing.T) {
 		testService.mockBalanceRepo.AssertExpectations(t)
 	})
 }
+
+func TestTransactionService_Transfer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Successful Transfer", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		sourceID := primitive.NewObjectID()
+		destinationID := primitive.NewObjectID()
+		amount := money.Amount(2500)
+		currency := "USD"
+
+		// Mock repository calls
+		testService.mockBalanceRepo.On("GetBalance", mock.Anything, destinationID, currency).Return(&models.Balance{AccountID: destinationID, Currency: currency}, nil)
+		testService.allowMovement(sourceID, destinationID)
+		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, sourceID, amount, currency).Return(nil)
+		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, destinationID, amount, currency).Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
+			return dto.AccountID == sourceID && dto.Type == string(models.TransactionTypeDebit) && dto.TransferID != nil
+		})).Return(&models.Transaction{ID: primitive.NewObjectID(), AccountID: sourceID}, nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
+			return dto.AccountID == destinationID && dto.Type == string(models.TransactionTypeCredit) && dto.TransferID != nil
+		})).Return(&models.Transaction{ID: primitive.NewObjectID(), AccountID: destinationID}, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Transfer(ctx, sourceID, destinationID, amount, currency, "rent")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.TransferID)
+		assert.NotEqual(t, result.DebitTransactionID, result.CreditTransactionID)
+		testService.mockBalanceRepo.AssertExpectations(t)
+		testService.mockTransactionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Self Transfer", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+
+		// Execute
+		result, err := testService.TransactionService.Transfer(ctx, accountID, accountID, 100, "USD", "")
+
+		// Assert
+		assert.Nil(t, result)
+		assert.Equal(t, utils.ErrSelfTransfer, err)
+	})
+
+	t.Run("Currency Mismatch", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		sourceID := primitive.NewObjectID()
+		destinationID := primitive.NewObjectID()
+
+		// Destination has no EUR balance
+		testService.mockBalanceRepo.On("GetBalance", mock.Anything, destinationID, "EUR").Return(nil, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Transfer(ctx, sourceID, destinationID, 100, "EUR", "")
+
+		// Assert
+		assert.Nil(t, result)
+		assert.Equal(t, utils.ErrCurrencyMismatch, err)
+		testService.mockBalanceRepo.AssertNotCalled(t, "CheckAndDeductBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionService_ListTransactions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Returns Next Cursor When More Pages Exist", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		now := time.Now().UTC().Truncate(time.Millisecond)
+
+		page := []models.Transaction{
+			{ID: primitive.NewObjectID(), AccountID: accountID, Amount: 300, Currency: "USD", TransactionDate: now},
+			{ID: primitive.NewObjectID(), AccountID: accountID, Amount: 200, Currency: "USD", TransactionDate: now.Add(-time.Minute)},
+			{ID: primitive.NewObjectID(), AccountID: accountID, Amount: 100, Currency: "USD", TransactionDate: now.Add(-2 * time.Minute)},
+		}
+		testService.mockTransactionRepo.On("ListTransactions", ctx, mock.MatchedBy(func(filter *dtos.TransactionFilter) bool {
+			return filter.AccountID == accountID && filter.Limit == 3 && filter.After == nil
+		})).Return(page, nil).Once()
+
+		// Execute
+		result, err := testService.TransactionService.ListTransactions(ctx, accountID, dtos.TransactionHistoryQuery{Limit: 2})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, result.Transactions, 2)
+		assert.Equal(t, money.Decimal("3.00"), result.Transactions[0].Amount)
+		assert.NotEmpty(t, result.NextCursor)
+
+		// The cursor resumes after the last returned transaction
+		testService.mockTransactionRepo.On("ListTransactions", ctx, mock.MatchedBy(func(filter *dtos.TransactionFilter) bool {
+			return filter.After != nil && filter.After.ID == page[1].ID && filter.After.TransactionDate.Equal(page[1].TransactionDate)
+		})).Return(page[2:], nil).Once()
+
+		result, err = testService.TransactionService.ListTransactions(ctx, accountID, dtos.TransactionHistoryQuery{Limit: 2, Cursor: result.NextCursor})
+		assert.NoError(t, err)
+		assert.Len(t, result.Transactions, 1)
+		assert.Empty(t, result.NextCursor)
+		testService.mockTransactionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Amount Filter Requires Currency", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+
+		// Execute
+		result, err := testService.TransactionService.ListTransactions(ctx, primitive.NewObjectID(), dtos.TransactionHistoryQuery{MinAmount: "10"})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		testService.mockTransactionRepo.AssertNotCalled(t, "ListTransactions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+
+		// Execute
+		result, err := testService.TransactionService.ListTransactions(ctx, primitive.NewObjectID(), dtos.TransactionHistoryQuery{Cursor: "not-a-cursor"})
+
+		// Assert
+		assert.Equal(t, utils.ErrInvalidCursor, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestTransactionService_GetTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Not Found", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		transactionID := primitive.NewObjectID()
+		testService.mockTransactionRepo.On("FindByID", ctx, transactionID).Return(nil, nil)
+
+		// Execute
+		result, err := testService.TransactionService.GetTransaction(ctx, transactionID)
+
+		// Assert
+		assert.Equal(t, utils.ErrTransactionNotFound, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestTransactionService_Ledger(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Deposit Posts Balanced Journal Entry", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		amount := money.Amount(1234)
+
+		// Mock an active account with no limits
+		testService.allowMovement(accountID)
+
+		// Mock repository calls
+		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, amount, "USD").Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.MatchedBy(func(entry *models.JournalEntry) bool {
+			return entry.Kind == models.JournalEntryKindDeposit &&
+				len(entry.Postings) == 2 &&
+				entry.Postings[0].LedgerAccount == models.LedgerAccountCashIn &&
+				entry.Postings[0].Direction == models.PostingDirectionDebit &&
+				entry.Postings[1].LedgerAccount == models.CustomerLedgerAccount(accountID) &&
+				entry.Postings[1].Direction == models.PostingDirectionCredit &&
+				entry.Postings[0].Amount == entry.Postings[1].Amount
+		})).Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil)
+
+		// Execute
+		_, err := testService.TransactionService.Deposit(ctx, accountID, amount, "USD", "")
+
+		// Assert
+		assert.NoError(t, err)
+		testService.mockLedgerRepo.AssertExpectations(t)
+	})
+
+	t.Run("Insufficient Balance Skips Credit And Entry", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		sourceID := primitive.NewObjectID()
+		destinationID := primitive.NewObjectID()
+
+		// Mock repository calls
+		testService.mockBalanceRepo.On("GetBalance", mock.Anything, destinationID, "USD").Return(&models.Balance{}, nil)
+		testService.allowMovement(sourceID, destinationID)
+		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, sourceID, money.Amount(500), "USD").Return(utils.ErrInsufficientBalance)
+
+		// Execute
+		_, err := testService.TransactionService.Transfer(ctx, sourceID, destinationID, 500, "USD", "")
+
+		// Assert
+		assert.Equal(t, utils.ErrInsufficientBalance, err)
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockLedgerRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionService_Holds(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Authorize Reserves Available Balance", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		amount := money.Amount(4000)
+		testService.allowMovement(accountID)
+
+		testService.mockBalanceRepo.On("PlaceHold", mock.Anything, accountID, amount, "USD").Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
+			return dto.Status == string(models.TransactionStatusPending) && dto.HeldAmount == amount && dto.ExpiresAt != nil
+		})).Return(&models.Transaction{ID: primitive.NewObjectID(), Status: models.TransactionStatusPending, HeldAmount: amount, Amount: amount, Currency: "USD"}, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Authorize(ctx, accountID, amount, "USD", "hotel", time.Hour)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, string(models.TransactionStatusPending), result.Status)
+		testService.mockLedgerRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+		testService.mockBalanceRepo.AssertNotCalled(t, "CheckAndDeductBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Partial Capture Releases Full Hold", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		hold := &models.Transaction{ID: primitive.NewObjectID(), AccountID: accountID, Status: models.TransactionStatusPending, HeldAmount: 4000, Amount: 4000, Currency: "USD"}
+		captured := money.Amount(3000)
+		testService.allowMovement(accountID)
+
+		testService.mockTransactionRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+		testService.mockBalanceRepo.On("ReleaseHold", mock.Anything, accountID, money.Amount(4000), "USD").Return(nil)
+		testService.mockBalanceRepo.On("CheckAndDeductBalance", mock.Anything, accountID, captured, "USD").Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+		testService.mockTransactionRepo.On("ResolveHold", mock.Anything, hold.ID, models.TransactionStatusCompleted, captured, mock.Anything).Return(true, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Capture(ctx, hold.ID, &captured)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, money.Decimal("30.00"), result.Amount)
+		assert.Equal(t, string(models.TransactionStatusCompleted), result.Status)
+		testService.mockBalanceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Capture More Than Held", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		hold := &models.Transaction{ID: primitive.NewObjectID(), Status: models.TransactionStatusPending, HeldAmount: 4000, Currency: "USD"}
+		tooMuch := money.Amount(4001)
+
+		testService.mockTransactionRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Capture(ctx, hold.ID, &tooMuch)
+
+		// Assert
+		assert.Nil(t, result)
+		assert.Equal(t, utils.ErrCaptureExceedsHold, err)
+	})
+
+	t.Run("Capture Expired Hold", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		expiredAt := time.Now().Add(-time.Minute)
+		hold := &models.Transaction{ID: primitive.NewObjectID(), Status: models.TransactionStatusPending, HeldAmount: 4000, Currency: "USD", ExpiresAt: &expiredAt}
+
+		testService.mockTransactionRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Capture(ctx, hold.ID, nil)
+
+		// Assert the expiry worker has not voided it yet, but it is not captured either
+		assert.Nil(t, result)
+		assert.Equal(t, utils.ErrHoldExpired, err)
+		testService.mockBalanceRepo.AssertNotCalled(t, "ReleaseHold", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockLedgerRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Void Already Captured Hold", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		hold := &models.Transaction{ID: primitive.NewObjectID(), Status: models.TransactionStatusCompleted, HeldAmount: 4000, Currency: "USD"}
+
+		testService.mockTransactionRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Void(ctx, hold.ID)
+
+		// Assert
+		assert.Nil(t, result)
+		assert.Equal(t, utils.ErrHoldNotPending, err)
+		testService.mockBalanceRepo.AssertNotCalled(t, "ReleaseHold", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionService_Reverse(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Partial Refund Of Debit", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		entryID := primitive.NewObjectID()
+		original := &models.Transaction{
+			ID: primitive.NewObjectID(), AccountID: accountID, Type: models.TransactionTypeDebit,
+			Amount: 5000, ReversedAmount: 1000, Currency: "USD", Status: models.TransactionStatusCompleted, JournalEntryID: entryID,
+		}
+		refund := money.Amount(1500)
+		testService.allowMovement(accountID)
+
+		testService.mockTransactionRepo.On("FindByID", mock.Anything, original.ID).Return(original, nil)
+		testService.mockLedgerRepo.On("FindEntry", mock.Anything, entryID).Return(&models.JournalEntry{
+			ID: entryID,
+			Postings: []models.Posting{
+				{LedgerAccount: models.CustomerLedgerAccount(accountID), AccountID: &accountID, Direction: models.PostingDirectionDebit, Amount: 5000, Currency: "USD"},
+				{LedgerAccount: models.LedgerAccountCashOut, Direction: models.PostingDirectionCredit, Amount: 5000, Currency: "USD"},
+			},
+		}, nil)
+		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, refund, "USD").Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.MatchedBy(func(entry *models.JournalEntry) bool {
+			return entry.Kind == models.JournalEntryKindReversal && entry.Postings[0].Direction == models.PostingDirectionCredit
+		})).Return(nil)
+		testService.mockTransactionRepo.On("AddReversedAmount", mock.Anything, original.ID, refund).Return(true, nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
+			return dto.Type == string(models.TransactionTypeCredit) && dto.Amount == refund && *dto.ReversalOf == original.ID
+		})).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Reverse(ctx, original.ID, &refund, "duplicate charge")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, money.Decimal("25.00"), result.ReversedAmount)
+		assert.Equal(t, money.Decimal("25.00"), result.RemainingAmount)
+		testService.mockTransactionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Partial Refund Of Split Entry", func(t *testing.T) {
+		accountID := primitive.NewObjectID()
+		customer := models.Posting{LedgerAccount: models.CustomerLedgerAccount(accountID), AccountID: &accountID, Direction: models.PostingDirectionDebit, Currency: "USD"}
+		split := func(ledgerAccount string, amount money.Amount) models.Posting {
+			return models.Posting{LedgerAccount: ledgerAccount, Direction: models.PostingDirectionCredit, Amount: amount, Currency: "USD"}
+		}
+
+		tests := []struct {
+			name     string
+			total    money.Amount
+			credits  []models.Posting
+			refund   money.Amount
+			expected map[string]money.Amount
+		}{
+			{
+				name:     "Interest And Fee",
+				total:    2250,
+				credits:  []models.Posting{split(models.LedgerAccountOverdraftInterest, 2000), split(models.LedgerAccountFees, 250)},
+				refund:   1000,
+				expected: map[string]money.Amount{models.LedgerAccountOverdraftInterest: 889, models.LedgerAccountFees: 111},
+			},
+			{
+				name:     "Smallest Unit",
+				total:    2250,
+				credits:  []models.Posting{split(models.LedgerAccountOverdraftInterest, 2000), split(models.LedgerAccountFees, 250)},
+				refund:   1,
+				expected: map[string]money.Amount{models.LedgerAccountOverdraftInterest: 1},
+			},
+			{
+				name:     "Even Three Way",
+				total:    300,
+				credits:  []models.Posting{split(models.LedgerAccountFees, 100), split(models.LedgerAccountOverdraftInterest, 100), split(models.LedgerAccountCashOut, 100)},
+				refund:   200,
+				expected: map[string]money.Amount{models.LedgerAccountFees: 68, models.LedgerAccountOverdraftInterest: 66, models.LedgerAccountCashOut: 66},
+			},
+			{
+				name:     "Everything Left",
+				total:    2250,
+				credits:  []models.Posting{split(models.LedgerAccountOverdraftInterest, 2000), split(models.LedgerAccountFees, 250)},
+				refund:   2250,
+				expected: map[string]money.Amount{models.LedgerAccountOverdraftInterest: 2000, models.LedgerAccountFees: 250},
+			},
+			{
+				name:     "Third Of A Huge Entry",
+				total:    math.MaxInt64 / 2,
+				credits:  []models.Posting{split(models.LedgerAccountOverdraftInterest, math.MaxInt64/2-1000), split(models.LedgerAccountFees, 1000)},
+				refund:   math.MaxInt64 / 6,
+				expected: map[string]money.Amount{models.LedgerAccountOverdraftInterest: 1537228672809128968, models.LedgerAccountFees: 333},
+			},
+			{
+				name:     "All Of A Huge Entry",
+				total:    math.MaxInt64 / 2,
+				credits:  []models.Posting{split(models.LedgerAccountOverdraftInterest, math.MaxInt64/2-1000), split(models.LedgerAccountFees, 1000)},
+				refund:   math.MaxInt64 / 2,
+				expected: map[string]money.Amount{models.LedgerAccountOverdraftInterest: math.MaxInt64/2 - 1000, models.LedgerAccountFees: 1000},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Setup
+				testService := setupTestService()
+				entryID := primitive.NewObjectID()
+				original := &models.Transaction{
+					ID: primitive.NewObjectID(), AccountID: accountID, Type: models.TransactionTypeDebit, Charge: true,
+					Amount: tt.total, Currency: "USD", Status: models.TransactionStatusCompleted, JournalEntryID: entryID,
+				}
+				debit := customer
+				debit.Amount = tt.total
+				testService.allowMovement(accountID)
+
+				var posted *models.JournalEntry
+				testService.mockTransactionRepo.On("FindByID", mock.Anything, original.ID).Return(original, nil)
+				testService.mockLedgerRepo.On("FindEntry", mock.Anything, entryID).Return(&models.JournalEntry{
+					ID: entryID, Postings: append([]models.Posting{debit}, tt.credits...),
+				}, nil)
+				testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, tt.refund, "USD").Return(nil)
+				testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					posted = args.Get(1).(*models.JournalEntry)
+				}).Return(nil)
+				testService.mockTransactionRepo.On("AddReversedAmount", mock.Anything, original.ID, tt.refund).Return(true, nil)
+				testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil)
+
+				// Execute
+				_, err := testService.TransactionService.Reverse(ctx, original.ID, &tt.refund, "goodwill")
+
+				// Assert
+				assert.NoError(t, err)
+				debits := map[string]money.Amount{}
+				var credited, debited money.Amount
+				for _, posting := range posted.Postings {
+					if posting.Direction == models.PostingDirectionCredit {
+						assert.Equal(t, models.CustomerLedgerAccount(accountID), posting.LedgerAccount)
+						credited += posting.Amount
+						continue
+					}
+					debits[posting.LedgerAccount] = posting.Amount
+					debited += posting.Amount
+				}
+				assert.Equal(t, tt.refund, credited)
+				assert.Equal(t, tt.refund, debited)
+				assert.Equal(t, tt.expected, debits)
+			})
+		}
+	})
+
+	t.Run("Partial Reversal Of Credit", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		original := &models.Transaction{ID: primitive.NewObjectID(), Type: models.TransactionTypeCredit, Amount: 5000, Currency: "USD", Status: models.TransactionStatusCompleted}
+		partial := money.Amount(100)
+
+		testService.mockTransactionRepo.On("FindByID", mock.Anything, original.ID).Return(original, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Reverse(ctx, original.ID, &partial, "mistake")
+
+		// Assert
+		assert.Nil(t, result)
+		assert.Equal(t, utils.ErrPartialReversalNotAllowed, err)
+	})
+
+	t.Run("Already Reversed", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		original := &models.Transaction{ID: primitive.NewObjectID(), Type: models.TransactionTypeDebit, Amount: 5000, ReversedAmount: 5000, Currency: "USD", Status: models.TransactionStatusCompleted}
+
+		testService.mockTransactionRepo.On("FindByID", mock.Anything, original.ID).Return(original, nil)
+
+		// Execute
+		result, err := testService.TransactionService.Reverse(ctx, original.ID, nil, "again")
+
+		// Assert
+		assert.Nil(t, result)
+		assert.Equal(t, utils.ErrAlreadyReversed, err)
+		testService.mockLedgerRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionService_ChargeOverdrafts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		day := today.AddDate(0, 0, -days)
+		return &day
+	}
+
+	// 365.00 overdrawn at 10% a year accrues 0.10 of interest a day, plus a 2.50 fee
+	overdrawn := func(accountID primitive.ObjectID, chargedThrough *time.Time) models.Balance {
+		return models.Balance{
+			ID:        primitive.NewObjectID(),
+			AccountID: accountID,
+			Currency:  "USD",
+			Amount:    -36500,
+			Overdraft: &models.Overdraft{Limit: 100000, InterestRateBps: 1000, DailyFee: 250, ChargedThrough: chargedThrough},
+		}
+	}
+	expectCharges := func(testService *testTransactionService, balance models.Balance) {
+		testService.mockBalanceRepo.On("FindOverdraftsDue", mock.Anything, today, mock.Anything, mock.Anything).Return([]models.Balance{balance}, nil)
+		testService.mockBalanceRepo.On("GetBalance", mock.Anything, balance.AccountID, "USD").Return(&balance, nil)
+		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, balance.AccountID, money.Amount(-260), "USD").Return(nil)
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.Anything).Return(nil)
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil)
+		testService.mockAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	}
+
+	t.Run("Charge Posted And Revenue Credited", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		accountID := primitive.NewObjectID()
+		balance := overdrawn(accountID, daysAgo(1))
+
+		// Mock repository calls
+		testService.mockBalanceRepo.On("FindOverdraftsDue", mock.Anything, today, mock.Anything, mock.Anything).Return([]models.Balance{balance}, nil)
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(1), today).Return(true, nil).Once()
+		testService.mockBalanceRepo.On("GetBalance", mock.Anything, accountID, "USD").Return(&balance, nil)
+		testService.mockBalanceRepo.On("UpdateBalance", mock.Anything, accountID, money.Amount(-260), "USD").Return(nil).Once()
+		testService.mockLedgerRepo.On("CreateEntry", mock.Anything, mock.MatchedBy(func(entry *models.JournalEntry) bool {
+			return entry.Kind == models.JournalEntryKindOverdraftCharge &&
+				len(entry.Postings) == 3 &&
+				entry.Postings[0].LedgerAccount == models.CustomerLedgerAccount(accountID) &&
+				entry.Postings[0].Direction == models.PostingDirectionDebit &&
+				entry.Postings[0].Amount == 260 &&
+				entry.Postings[1].LedgerAccount == models.LedgerAccountOverdraftInterest &&
+				entry.Postings[1].Direction == models.PostingDirectionCredit &&
+				entry.Postings[1].Amount == 10 &&
+				entry.Postings[2].LedgerAccount == models.LedgerAccountFees &&
+				entry.Postings[2].Direction == models.PostingDirectionCredit &&
+				entry.Postings[2].Amount == 250
+		})).Return(nil).Once()
+		testService.mockTransactionRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(dto *dtos.CreateTransactionDTO) bool {
+			return dto.AccountID == accountID && dto.Amount == 260 && dto.Type == string(models.TransactionTypeDebit) && dto.Charge
+		})).Return(&models.Transaction{ID: primitive.NewObjectID()}, nil).Once()
+		testService.mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.Action == models.AuditActionOverdraftCharged &&
+				event.Details["day"] == "2026-03-10" &&
+				event.Details["interest"] == "0.10" &&
+				event.Details["fee"] == "2.50"
+		})).Return(nil).Once()
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, charged)
+		testService.mockBalanceRepo.AssertExpectations(t)
+		testService.mockLedgerRepo.AssertExpectations(t)
+		testService.mockTransactionRepo.AssertExpectations(t)
+		testService.mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Never Charged Balance Is Only Due Today", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		balance := overdrawn(primitive.NewObjectID(), nil)
+		expectCharges(testService, balance)
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, (*time.Time)(nil), today).Return(true, nil).Once()
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, charged)
+		testService.mockBalanceRepo.AssertNumberOfCalls(t, "MarkOverdraftCharged", 1)
+	})
+
+	t.Run("Missed Days Charged One By One", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		balance := overdrawn(primitive.NewObjectID(), daysAgo(3))
+		expectCharges(testService, balance)
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(3), *daysAgo(2)).Return(true, nil).Once()
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(2), *daysAgo(1)).Return(true, nil).Once()
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(1), today).Return(true, nil).Once()
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, charged)
+		testService.mockBalanceRepo.AssertExpectations(t)
+		testService.mockBalanceRepo.AssertNumberOfCalls(t, "UpdateBalance", 3)
+		testService.mockTransactionRepo.AssertNumberOfCalls(t, "CreateTransaction", 3)
+		for _, day := range []string{"2026-03-08", "2026-03-09", "2026-03-10"} {
+			testService.mockAuditRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(event *models.AuditEvent) bool {
+				return event.Details["day"] == day
+			}))
+		}
+	})
+
+	t.Run("No Double Charge Same Day", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		balance := overdrawn(primitive.NewObjectID(), daysAgo(1))
+		expectCharges(testService, balance)
+
+		// Another run marks the day first
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(1), today).Return(false, nil).Once()
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, charged)
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockLedgerRepo.AssertNotCalled(t, "CreateEntry", mock.Anything, mock.Anything)
+		testService.mockTransactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Balance Already Charged Today Is Skipped", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		balance := overdrawn(primitive.NewObjectID(), daysAgo(0))
+		expectCharges(testService, balance)
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, charged)
+		testService.mockBalanceRepo.AssertNotCalled(t, "MarkOverdraftCharged", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Stops When Another Run Charges A Missed Day", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		balance := overdrawn(primitive.NewObjectID(), daysAgo(2))
+		expectCharges(testService, balance)
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(2), *daysAgo(1)).Return(false, nil).Once()
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, charged)
+		testService.mockBalanceRepo.AssertNumberOfCalls(t, "MarkOverdraftCharged", 1)
+	})
+
+	t.Run("Balance No Longer Overdrawn Is Marked Without Charge", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		balance := overdrawn(primitive.NewObjectID(), daysAgo(1))
+		balance.Amount = 500
+		expectCharges(testService, balance)
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(1), today).Return(true, nil).Once()
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, charged)
+		testService.mockBalanceRepo.AssertCalled(t, "MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(1), today)
+		testService.mockBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		testService.mockTransactionRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failing Balance Does Not Stop The Run", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		failing := overdrawn(primitive.NewObjectID(), daysAgo(1))
+		balance := overdrawn(primitive.NewObjectID(), daysAgo(1))
+		testService.mockBalanceRepo.On("FindOverdraftsDue", mock.Anything, today, mock.Anything, mock.Anything).Return([]models.Balance{failing, balance}, nil)
+		expectCharges(testService, balance)
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, failing.ID, daysAgo(1), today).Return(false, errors.New("write conflict")).Once()
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, balance.ID, daysAgo(1), today).Return(true, nil).Once()
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), failing.ID.Hex())
+		assert.Contains(t, err.Error(), "write conflict")
+		assert.Equal(t, 1, charged)
+		testService.mockBalanceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed Balances Are Not Listed Again", func(t *testing.T) {
+		// Setup
+		testService := setupTestService()
+		batch := make([]models.Balance, 100)
+		for i := range batch {
+			batch[i] = overdrawn(primitive.NewObjectID(), daysAgo(1))
+		}
+		failed := func(skip []primitive.ObjectID) bool { return len(skip) == len(batch) }
+		testService.mockBalanceRepo.On("FindOverdraftsDue", mock.Anything, today, mock.MatchedBy(func(skip []primitive.ObjectID) bool { return !failed(skip) }), 100).Return(batch, nil).Once()
+		testService.mockBalanceRepo.On("FindOverdraftsDue", mock.Anything, today, mock.MatchedBy(failed), 100).Return([]models.Balance{}, nil).Once()
+		testService.mockBalanceRepo.On("MarkOverdraftCharged", mock.Anything, mock.Anything, daysAgo(1), today).Return(false, errors.New("write conflict"))
+
+		// Execute
+		charged, err := testService.TransactionService.ChargeOverdrafts(ctx, now)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, 0, charged)
+		testService.mockBalanceRepo.AssertExpectations(t)
+		testService.mockBalanceRepo.AssertNumberOfCalls(t, "MarkOverdraftCharged", len(batch))
+	})
+}
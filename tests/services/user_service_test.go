@@ -0,0 +1,251 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/utils"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUserService_ListUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Next Page Cursor", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		users := []models.User{{ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}}
+
+		mockUserRepo.On("List", ctx, dtos.UserFilter{Role: "teller", Limit: 3}).Return(users, nil)
+
+		response, err := userService.ListUsers(ctx, dtos.UserListQuery{Role: "teller", Limit: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Users, 2)
+		assert.Equal(t, users[1].ID.Hex(), response.NextCursor)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		userService := services.NewUserService(&mocks.MockUserRepository{}, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+
+		response, err := userService.ListUsers(ctx, dtos.UserListQuery{Cursor: "not-an-id"})
+
+		assert.Nil(t, response)
+		assert.Equal(t, utils.ErrInvalidCursor, err)
+	})
+}
+
+func TestUserService_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+	t.Run("Successful Update", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("UpdateRole", ctx, userID, models.RoleTeller).Return(&models.User{ID: userID, Role: models.RoleTeller}, nil)
+
+		user, err := userService.UpdateRole(ctx, admin, userID, models.RoleTeller)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.RoleTeller, user.Role)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Own Role", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+
+		user, err := userService.UpdateRole(ctx, admin, admin.UserID, models.RoleCustomer)
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrCannotChangeOwnRole, err)
+		mockUserRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Role", func(t *testing.T) {
+		userService := services.NewUserService(&mocks.MockUserRepository{}, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+
+		user, err := userService.UpdateRole(ctx, admin, primitive.NewObjectID(), models.Role("superuser"))
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrInvalidRole, err)
+	})
+
+	t.Run("User Not Found", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("UpdateRole", ctx, userID, models.RoleAuditor).Return(nil, nil)
+
+		user, err := userService.UpdateRole(ctx, admin, userID, models.RoleAuditor)
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrUserNotFound, err)
+	})
+}
+
+func TestUserService_Unlock(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+	t.Run("Successful Unlock", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockLoginAttemptRepo := &mocks.MockLoginAttemptRepository{}
+		userService := services.NewUserService(mockUserRepo, mockLoginAttemptRepo, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("Unlock", ctx, userID, mock.MatchedBy(func(event models.LockoutEvent) bool {
+			return event.Action == models.LockoutActionUnlocked && *event.ActorID == admin.UserID && event.Reason == "verified by phone"
+		})).Return(&models.User{ID: userID, Email: "John@Example.com"}, nil)
+		mockLoginAttemptRepo.On("Reset", ctx, "email:john@example.com").Return(nil)
+
+		user, err := userService.Unlock(ctx, admin, userID, "verified by phone")
+
+		assert.NoError(t, err)
+		assert.Equal(t, userID, user.ID)
+		mockUserRepo.AssertExpectations(t)
+		mockLoginAttemptRepo.AssertExpectations(t)
+	})
+
+	t.Run("User Not Found", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("Unlock", ctx, userID, mock.Anything).Return(nil, nil)
+
+		user, err := userService.Unlock(ctx, admin, userID, "")
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrUserNotFound, err)
+	})
+}
+
+func TestUserService_Block(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+	t.Run("Successful Block", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, mockRefreshTokenRepo, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("UpdateStatus", ctx, userID, mock.MatchedBy(func(change models.StatusChange) bool {
+			return change.From == models.UserStatusActive && change.To == models.UserStatusBlocked &&
+				*change.ActorID == admin.UserID && change.Reason == "suspected fraud"
+		})).Return(&models.User{ID: userID, Status: models.UserStatusBlocked}, nil)
+		mockRefreshTokenRepo.On("RevokeUser", ctx, userID).Return(nil)
+
+		user, err := userService.Block(ctx, admin, userID, "suspected fraud")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.UserStatusBlocked, user.Status)
+		mockUserRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Own User", func(t *testing.T) {
+		userService := services.NewUserService(&mocks.MockUserRepository{}, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+
+		user, err := userService.Block(ctx, admin, admin.UserID, "testing")
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrCannotChangeOwnStatus, err)
+	})
+
+	t.Run("Already Blocked", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("UpdateStatus", ctx, userID, mock.Anything).Return(nil, nil)
+		mockUserRepo.On("FindByID", ctx, userID).Return(&models.User{ID: userID, Status: models.UserStatusBlocked}, nil)
+
+		user, err := userService.Block(ctx, admin, userID, "suspected fraud")
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrInvalidStatusChange, err)
+	})
+
+	t.Run("User Not Found", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("UpdateStatus", ctx, userID, mock.Anything).Return(nil, nil)
+		mockUserRepo.On("FindByID", ctx, userID).Return(nil, nil)
+
+		user, err := userService.Block(ctx, admin, userID, "suspected fraud")
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrUserNotFound, err)
+	})
+}
+
+func TestUserService_Unblock(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+	mockUserRepo := &mocks.MockUserRepository{}
+	userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+	userID := primitive.NewObjectID()
+
+	// Sessions are not touched when a user becomes active again
+	mockUserRepo.On("UpdateStatus", ctx, userID, mock.MatchedBy(func(change models.StatusChange) bool {
+		return change.From == models.UserStatusBlocked && change.To == models.UserStatusActive
+	})).Return(&models.User{ID: userID, Status: models.UserStatusActive}, nil)
+
+	user, err := userService.Unblock(ctx, admin, userID, "cleared by compliance")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserStatusActive, user.Status)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserService_Deactivate(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.Principal{UserID: primitive.NewObjectID(), Role: models.RoleAdmin}
+
+	t.Run("Blocked User", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, mockRefreshTokenRepo, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("FindByID", ctx, userID).Return(&models.User{ID: userID, Status: models.UserStatusBlocked}, nil)
+		mockUserRepo.On("UpdateStatus", ctx, userID, mock.MatchedBy(func(change models.StatusChange) bool {
+			return change.From == models.UserStatusBlocked && change.To == models.UserStatusInactive
+		})).Return(&models.User{ID: userID, Status: models.UserStatusInactive}, nil)
+		mockRefreshTokenRepo.On("RevokeUser", ctx, userID).Return(nil)
+
+		user, err := userService.Deactivate(ctx, admin, userID, "closed at customer request")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.UserStatusInactive, user.Status)
+		mockUserRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Already Deactivated", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		userService := services.NewUserService(mockUserRepo, &mocks.MockLoginAttemptRepository{}, &mocks.MockRefreshTokenRepository{}, recordAudit())
+		userID := primitive.NewObjectID()
+
+		mockUserRepo.On("FindByID", ctx, userID).Return(&models.User{ID: userID, Status: models.UserStatusInactive}, nil)
+
+		user, err := userService.Deactivate(ctx, admin, userID, "closed at customer request")
+
+		assert.Nil(t, user)
+		assert.Equal(t, utils.ErrInvalidStatusChange, err)
+	})
+}
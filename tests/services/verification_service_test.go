@@ -0,0 +1,199 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/dtos"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/models"
+	"github.com/Ahmed1monm/Axis-BE-assessment/internal/services"
+	"github.com/Ahmed1monm/Axis-BE-assessment/pkg/mailer"
+	"github.com/Ahmed1monm/Axis-BE-assessment/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// hashToken hashes a mailed token as it is stored
+func hashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAuthService_ForgotPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Known Email", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		mockMailer := &mocks.MockMailer{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), mockMailer, &mocks.MockSMSSender{})
+		user := &models.User{ID: primitive.NewObjectID(), Email: "john@example.com"}
+
+		mockUserRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+		mockActionTokenRepo.On("InvalidateAll", ctx, user.ID, models.ActionPasswordReset).Return(nil)
+		mockActionTokenRepo.On("Create", ctx, mock.MatchedBy(func(token *models.ActionToken) bool {
+			return token.UserID == user.ID && token.Purpose == models.ActionPasswordReset && token.ExpiresAt.After(time.Now())
+		})).Return(nil)
+		mockMailer.On("Send", ctx, mock.MatchedBy(func(message mailer.Message) bool {
+			return message.To == user.Email
+		})).Return(nil)
+
+		err := authService.ForgotPassword(ctx, dtos.ForgotPasswordRequest{Email: user.Email})
+
+		assert.NoError(t, err)
+		mockActionTokenRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Email", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockMailer := &mocks.MockMailer{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, &mocks.MockActionTokenRepository{}, &mocks.MockLoginAttemptRepository{}, recordAudit(), mockMailer, &mocks.MockSMSSender{})
+
+		mockUserRepo.On("FindByEmail", ctx, "nobody@example.com").Return(nil, nil)
+
+		err := authService.ForgotPassword(ctx, dtos.ForgotPasswordRequest{Email: "nobody@example.com"})
+
+		assert.NoError(t, err)
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid Token", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockRefreshTokenRepo := &mocks.MockRefreshTokenRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, mockRefreshTokenRepo, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		token := &models.ActionToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Purpose: models.ActionPasswordReset, ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionPasswordReset, hashToken("reset-token")).Return(token, nil)
+		mockActionTokenRepo.On("MarkUsed", ctx, token.ID).Return(true, nil)
+		mockUserRepo.On("UpdatePassword", ctx, token.UserID, mock.AnythingOfType("string")).Return(true, nil)
+		mockRefreshTokenRepo.On("RevokeUser", ctx, token.UserID).Return(nil)
+
+		err := authService.ResetPassword(ctx, dtos.ResetPasswordRequest{Token: "reset-token", Password: "new-password"})
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Used Token", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		usedAt := time.Now().Add(-time.Minute)
+		token := &models.ActionToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionPasswordReset, hashToken("reset-token")).Return(token, nil)
+
+		err := authService.ResetPassword(ctx, dtos.ResetPasswordRequest{Token: "reset-token", Password: "new-password"})
+
+		assert.Equal(t, services.ErrInvalidActionToken, err)
+		mockUserRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent Redemption", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		token := &models.ActionToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionPasswordReset, hashToken("reset-token")).Return(token, nil)
+		mockActionTokenRepo.On("MarkUsed", ctx, token.ID).Return(false, nil)
+
+		err := authService.ResetPassword(ctx, dtos.ResetPasswordRequest{Token: "reset-token", Password: "new-password"})
+
+		assert.Equal(t, services.ErrInvalidActionToken, err)
+		mockUserRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid Token", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		token := &models.ActionToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Email: "john@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionEmailVerification, hashToken("verify-token")).Return(token, nil)
+		mockActionTokenRepo.On("MarkUsed", ctx, token.ID).Return(true, nil)
+		mockUserRepo.On("MarkEmailVerified", ctx, token.UserID, token.Email).Return(true, nil)
+
+		err := authService.VerifyEmail(ctx, dtos.VerifyEmailRequest{Token: "verify-token"})
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Expired Token", func(t *testing.T) {
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(&mocks.MockUserRepository{}, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		token := &models.ActionToken{ID: primitive.NewObjectID(), ExpiresAt: time.Now().Add(-time.Minute)}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionEmailVerification, hashToken("verify-token")).Return(token, nil)
+
+		err := authService.VerifyEmail(ctx, dtos.VerifyEmailRequest{Token: "verify-token"})
+
+		assert.Equal(t, services.ErrInvalidActionToken, err)
+	})
+
+	t.Run("Email Changed Since", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		token := &models.ActionToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Email: "old@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionEmailVerification, hashToken("verify-token")).Return(token, nil)
+		mockActionTokenRepo.On("MarkUsed", ctx, token.ID).Return(true, nil)
+		mockUserRepo.On("MarkEmailVerified", ctx, token.UserID, token.Email).Return(false, nil)
+
+		err := authService.VerifyEmail(ctx, dtos.VerifyEmailRequest{Token: "verify-token"})
+
+		assert.Equal(t, services.ErrInvalidActionToken, err)
+	})
+}
+
+func TestAuthService_VerifyPhone(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid Token", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		token := &models.ActionToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Phone: "+1234567890", ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionPhoneVerification, hashToken("verify-token")).Return(token, nil)
+		mockActionTokenRepo.On("MarkUsed", ctx, token.ID).Return(true, nil)
+		mockUserRepo.On("MarkPhoneVerified", ctx, token.UserID, token.Phone).Return(true, nil)
+
+		err := authService.VerifyPhone(ctx, dtos.VerifyPhoneRequest{Token: "verify-token"})
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Phone Changed Since", func(t *testing.T) {
+		mockUserRepo := &mocks.MockUserRepository{}
+		mockActionTokenRepo := &mocks.MockActionTokenRepository{}
+		authService := services.NewAuthService(mockUserRepo, &mocks.MockAccountRepository{}, &mocks.MockRefreshTokenRepository{}, mockActionTokenRepo, &mocks.MockLoginAttemptRepository{}, recordAudit(), &mocks.MockMailer{}, &mocks.MockSMSSender{})
+		token := &models.ActionToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Phone: "+1234567890", ExpiresAt: time.Now().Add(time.Hour)}
+
+		mockActionTokenRepo.On("FindByHash", ctx, models.ActionPhoneVerification, hashToken("verify-token")).Return(token, nil)
+		mockActionTokenRepo.On("MarkUsed", ctx, token.ID).Return(true, nil)
+		mockUserRepo.On("MarkPhoneVerified", ctx, token.UserID, token.Phone).Return(false, nil)
+
+		err := authService.VerifyPhone(ctx, dtos.VerifyPhoneRequest{Token: "verify-token"})
+
+		assert.Equal(t, services.ErrInvalidActionToken, err)
+	})
+}